@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/messaging"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/natsclient"
+)
+
+const usage = `Inspect and replay the messages in the dead letter queue.
+
+Usage:
+  dlq list [-limit n]
+  dlq replay [-limit n] [-seq 12,15] [-subject NODES.created]
+  dlq discard -seq 12,15
+
+Flags:
+`
+
+// entry is how a dead letter is printed.
+type entry struct {
+	Sequence   uint64          `json:"seq"`
+	Subject    string          `json:"subject"`
+	Queue      string          `json:"queue"`
+	Error      string          `json:"error"`
+	Deliveries int             `json:"deliveries"`
+	FailedAt   time.Time       `json:"failed_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	command := os.Args[1]
+
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flags.PrintDefaults()
+	}
+	natsURL := flags.String(
+		"nats-url",
+		os.Getenv("NATS_URL"),
+		"URL of the NATS server, defaults to $NATS_URL",
+	)
+	limit := flags.Int("limit", 100, "maximum number of messages to read")
+	seqs := flags.String(
+		"seq",
+		"",
+		"comma separated sequences of the messages, all of them if empty",
+	)
+	subject := flags.String(
+		"subject",
+		"",
+		"only the messages originally published to this subject",
+	)
+	_ = flags.Parse(os.Args[2:])
+
+	if command != "list" && command != "replay" && command != "discard" {
+		flags.Usage()
+		os.Exit(2)
+	}
+	if command == "discard" && *seqs == "" {
+		fmt.Fprintln(os.Stderr, "discard needs the -seq of the messages")
+		os.Exit(2)
+	}
+	selected, err := parseSequences(*seqs)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if err := natsclient.Initialize(*natsURL); err != nil {
+		logger.Error("Failed to create Nats client", err)
+		os.Exit(1)
+	}
+	defer func() {
+		_ = natsclient.GetInstance().Disconnect()
+	}()
+
+	queue, err := messaging.OpenDeadLetterQueue()
+	if err != nil {
+		logger.Error("Failed to open the dead letter queue", err)
+		os.Exit(1)
+	}
+	defer func() {
+		_ = queue.Close()
+	}()
+
+	messages, err := queue.Fetch(*limit)
+	if err != nil {
+		logger.Error("Failed to read the dead letter queue", err)
+		os.Exit(1)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	for _, msg := range messages {
+		if len(selected) > 0 && !selected[msg.Sequence] {
+			continue
+		}
+		if *subject != "" && msg.Subject != *subject {
+			continue
+		}
+
+		switch command {
+		case "list":
+			_ = encoder.Encode(toEntry(msg))
+		case "replay":
+			err = queue.Replay(msg)
+		case "discard":
+			err = queue.Discard(msg)
+		}
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to %s message", command), err)
+			os.Exit(1)
+		}
+		if command != "list" {
+			fmt.Printf("%s %d %s\n", command, msg.Sequence, msg.Subject)
+		}
+	}
+}
+
+func parseSequences(value string) (map[uint64]bool, error) {
+	selected := make(map[uint64]bool)
+	if value == "" {
+		return selected, nil
+	}
+	for _, part := range strings.Split(value, ",") {
+		seq, err := strconv.ParseUint(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sequence %q", part)
+		}
+		selected[seq] = true
+	}
+	return selected, nil
+}
+
+func toEntry(msg *messaging.DeadLetterMessage) entry {
+	data := json.RawMessage(msg.Data)
+	if !json.Valid(data) {
+		// Keep the payloads which aren't JSON readable.
+		data, _ = json.Marshal(string(msg.Data))
+	}
+	return entry{
+		Sequence:   msg.Sequence,
+		Subject:    msg.Subject,
+		Queue:      msg.Queue,
+		Error:      msg.Error,
+		Deliveries: msg.Deliveries,
+		FailedAt:   msg.FailedAt,
+		Data:       data,
+	}
+}
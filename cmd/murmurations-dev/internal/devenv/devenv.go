@@ -0,0 +1,73 @@
+// Package devenv sets the environment variables of the services run by
+// murmurations-dev, so they don't have to be set by hand.
+//
+// The services parse their configuration when their packages are
+// initialized. Go initializes the packages without dependencies in the order
+// of their import paths, so this package is initialized before the services
+// as long as it only imports the standard library.
+package devenv
+
+import (
+	"os"
+)
+
+// defaults are the values of the variables which are not set. The ports and
+// the URLs of the services are set by murmurations-dev afterwards.
+var defaults = map[string]string{
+	"APP_ENV":              "development",
+	"SERVER_PORT":          "8080",
+	"SERVER_TIMEOUT_READ":  "5s",
+	"SERVER_TIMEOUT_WRITE": "15s",
+	"SERVER_TIMEOUT_IDLE":  "15s",
+	// Rate limits
+	"GET_RATE_LIMIT_PERIOD":  "6000-M",
+	"POST_RATE_LIMIT_PERIOD": "6000-M",
+	// The services share a database, their collections don't overlap.
+	"MONGO_USERNAME":    "admin",
+	"MONGO_PASSWORD":    "password",
+	"MONGO_HOST":        "localhost:27017",
+	"MONGO_DB_NAME":     "murmurationsDev",
+	"ELASTICSEARCH_URL": "http://localhost:9200",
+	// The search index is stored in MongoDB, Elasticsearch is not needed.
+	"SEARCH_BACKEND": "mongo",
+	// The events go through an in-memory bus, NATS is not used.
+	"NATS_CLUSTER_ID": "murmurations",
+	"NATS_CLIENT_ID":  "murmurations-dev",
+	"NATS_URL":        "nats://localhost:4222",
+	"NATS_BATCH_SIZE": "10",
+	"NATS_ACK_WAIT":   "30s",
+	// Same as redis.MemoryURL.
+	"REDIS_URL": "memory://",
+	// Index
+	"TAGS_ARRAY_SIZE":           "100",
+	"TAGS_STRING_LENGTH":        "100",
+	"TAGS_FUZZINESS":            "3",
+	"DELETED_TTL":               "120",
+	"PEERING_INDEX_URL":         "http://localhost:8080",
+	"PEERING_INTERVAL":          "1m",
+	"SCHEMA_VERSIONS_CACHE_TTL": "5m",
+	// Validation
+	"SCHEMA_CACHE_TTL":  "1h",
+	"SCHEMA_CACHE_SIZE": "500",
+	// Library
+	"STATIC_FILE_PATH": "services/library/static",
+	"SAMPLE_TIMEOUT":   "20s",
+	// Schemaparser, the schemas are only loaded from the local folder.
+	"IS_LOCAL":          "true",
+	"GITHUB_TOKEN":      "",
+	"GITHUB_BRANCH_URL": "",
+	"GITHUB_TREE_URL":   "",
+	// Service URLs
+	"LIBRARY_URL":             "",
+	"DEFAULT_SCHEMA":          "default-v2.1.0",
+	"INDEX_HOST":              "",
+	"EXTERNAL_DATA_PROXY_URL": "",
+}
+
+func init() {
+	for key, value := range defaults {
+		if _, ok := os.LookupEnv(key); !ok {
+			os.Setenv(key, value)
+		}
+	}
+}
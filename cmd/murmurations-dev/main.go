@@ -0,0 +1,144 @@
+package main
+
+import (
+	// Sets the environment variables before the services parse them.
+	_ "github.com/MurmurationsNetwork/MurmurationsServices/cmd/murmurations-dev/internal/devenv"
+
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/messaging"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/redis"
+	dataproxyconfig "github.com/MurmurationsNetwork/MurmurationsServices/services/dataproxy/config"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/dataproxy/pkg/dataproxy"
+	indexconfig "github.com/MurmurationsNetwork/MurmurationsServices/services/index/config"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/pkg/index"
+	libraryconfig "github.com/MurmurationsNetwork/MurmurationsServices/services/library/config"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/pkg/library"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/pkg/schemaparser"
+	validationconfig "github.com/MurmurationsNetwork/MurmurationsServices/services/validation/config"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/validation/pkg/validation"
+)
+
+var (
+	indexPort   = flag.String("index-port", "8080", "port of the index")
+	libraryPort = flag.String(
+		"library-port",
+		"8081",
+		"port of the library",
+	)
+	dataProxyPort = flag.String(
+		"dataproxy-port",
+		"8082",
+		"port of the data proxy",
+	)
+	validationPort = flag.String(
+		"validation-port",
+		"8083",
+		"port of the validation service",
+	)
+	libraryDir = flag.String(
+		"library-dir",
+		"library",
+		"folder of the local schemas and fields",
+	)
+)
+
+// service is a service run by murmurations-dev.
+type service struct {
+	name string
+	svc  interface {
+		Run()
+		WaitUntilUp() <-chan struct{}
+	}
+}
+
+func main() {
+	flag.Parse()
+	logger.Info("Murmurations services starting in development mode")
+
+	bus := messaging.NewMemoryBus()
+	messaging.SetBus(bus)
+	configure()
+
+	// The library comes first, the others fetch the schemas from it.
+	services := []service{
+		{name: "Library", svc: library.NewService()},
+		{name: "Index", svc: index.NewService()},
+		{name: "Validation", svc: validation.NewService()},
+		{name: "Data proxy", svc: dataproxy.NewService()},
+	}
+
+	var wg sync.WaitGroup
+	for _, s := range services {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.svc.Run()
+		}()
+	}
+	for _, s := range services {
+		<-s.svc.WaitUntilUp()
+		logger.Info(s.name + " service started")
+	}
+
+	// The MongoDB client is connected by the services.
+	schemaRedis := redis.NewClient(redis.MemoryURL)
+	loadSchemas(schemaRedis)
+	go reloadSchemasOnHangup(schemaRedis)
+
+	logger.Info(fmt.Sprintf(
+		"Murmurations services started: index on :%s, library on :%s, "+
+			"data proxy on :%s, validation on :%s",
+		*indexPort, *libraryPort, *dataProxyPort, *validationPort,
+	))
+
+	wg.Wait()
+	bus.Close()
+}
+
+// configure points the services to each other.
+func configure() {
+	libraryURL := "http://localhost:" + *libraryPort
+
+	indexconfig.Values.Server.Port = *indexPort
+	indexconfig.Values.Peering.IndexURL = "http://localhost:" + *indexPort
+	indexconfig.Values.Library.InternalURL = libraryURL
+
+	libraryconfig.Values.Server.Port = *libraryPort
+	libraryconfig.Values.Index.URL = "http://localhost:" + *indexPort
+
+	validationconfig.Values.Server.Port = *validationPort
+	validationconfig.Values.Library.InternalURL = libraryURL
+
+	dataproxyconfig.Values.Server.Port = *dataProxyPort
+	dataproxyconfig.Values.Index.URL = "http://localhost:" + *indexPort
+	dataproxyconfig.Values.Library.InternalURL = libraryURL
+	dataproxyconfig.Values.DataProxy.URL = "http://localhost:" + *dataProxyPort
+}
+
+// loadSchemas loads the schemas of the local library folder. The services
+// keep running when it fails, so the schemas can be fixed and reloaded.
+func loadSchemas(redisClient redis.Redis) {
+	err := schemaparser.LoadLocalSchemas(*libraryDir, redisClient)
+	if err != nil {
+		logger.Error("Failed to load the local schemas", err)
+		return
+	}
+	logger.Info("Local schemas loaded from " + *libraryDir)
+}
+
+// reloadSchemasOnHangup loads the local schemas again on SIGHUP, after
+// editing them.
+func reloadSchemasOnHangup(redisClient redis.Redis) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	for range hangup {
+		loadSchemas(redisClient)
+	}
+}
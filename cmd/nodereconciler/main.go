@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"time"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/nodereconciler/config"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/nodereconciler/pkg/nodereconciler"
+)
+
+func main() {
+	nr := nodereconciler.NewCronJob()
+
+	dryRun := flag.Bool(
+		"dry-run",
+		config.Values.Reconcile.DryRun,
+		"report the drift between the stores without repairing it",
+	)
+	flag.Parse()
+
+	startTime := time.Now()
+
+	if err := nr.Run(context.Background(), *dryRun); err != nil {
+		logger.Error("Error running NodeReconciler", err)
+		os.Exit(1)
+	}
+
+	duration := time.Since(startTime)
+	logger.Info("NodeReconciler run duration: " + duration.String())
+}
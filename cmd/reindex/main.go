@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"time"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/pkg/reindex"
+)
+
+func main() {
+	deleteOld := flag.Bool(
+		"delete-old",
+		false,
+		"delete the previous index once the new one is live",
+	)
+	flag.Parse()
+
+	r := reindex.NewReindexer()
+
+	startTime := time.Now()
+
+	err := r.Run(context.Background(), reindex.Options{DeleteOld: *deleteOld})
+	if err != nil {
+		logger.Error("Failed to reindex nodes", err)
+		os.Exit(1)
+	}
+
+	duration := time.Since(startTime)
+	logger.Info("Reindex run duration: " + duration.String())
+}
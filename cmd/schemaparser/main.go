@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"os"
 	"time"
 
@@ -8,10 +10,26 @@ import (
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/pkg/schemaparser"
 )
 
+var rollback = flag.Bool(
+	"rollback",
+	false,
+	"make the previous generation of the schemas active again and exit",
+)
+
 func main() {
+	flag.Parse()
 	startTime := time.Now()
 
 	s := schemaparser.NewCronJob()
+	if *rollback {
+		if err := s.Rollback(); err != nil {
+			logger.Error("Failed to roll back the schemas: ", err)
+			os.Exit(1)
+		}
+		logger.Info("Rolled back to the previous generation of the schemas")
+		return
+	}
+
 	if err := s.Run(); err != nil {
 		logger.Error("Failed to run SchemaParser: ", err)
 		os.Exit(1)
@@ -20,4 +38,7 @@ func main() {
 	// Calculate and log the duration
 	duration := time.Since(startTime)
 	logger.Info("SchemaParser run duration: " + duration.String())
+
+	// In watch mode, keep loading the changes of the schemas.
+	s.Watch(context.Background())
 }
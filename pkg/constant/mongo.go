@@ -1,17 +1,33 @@
 package constant
 
 var MongoIndex = struct {
-	Node    string
-	Schema  string
-	Mapping string
-	Profile string
-	Update  string
-	Batch   string
+	Node           string
+	Schema         string
+	Mapping        string
+	Profile        string
+	Update         string
+	Batch          string
+	Outbox         string
+	ProcessedEvent string
+	SearchNode     string
+	Peer           string
+	Field          string
+	SchemaDraft    string
+	SchemaMeta     string
+	SchemaRun      string
 }{
-	Node:    "nodes",
-	Schema:  "schemas",
-	Mapping: "mappings",
-	Profile: "profiles",
-	Update:  "updates",
-	Batch:   "batches",
+	Node:           "nodes",
+	Schema:         "schemas",
+	Mapping:        "mappings",
+	Profile:        "profiles",
+	Update:         "updates",
+	Batch:          "batches",
+	Outbox:         "outbox",
+	ProcessedEvent: "processedEvents",
+	SearchNode:     "searchNodes",
+	Peer:           "peers",
+	Field:          "fields",
+	SchemaDraft:    "schemaDrafts",
+	SchemaMeta:     "schemaMeta",
+	SchemaRun:      "schemaRuns",
 }
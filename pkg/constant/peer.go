@@ -0,0 +1,13 @@
+package constant
+
+// PeerDirection lists the directions in which the nodes flow between an index
+// and its peers.
+var PeerDirection = struct {
+	// Upstream peers are replicated into the index.
+	Upstream string
+	// Downstream peers replicate the index.
+	Downstream string
+}{
+	Upstream:   "upstream",
+	Downstream: "downstream",
+}
@@ -0,0 +1,11 @@
+package constant
+
+// SearchBackend lists the backends which can store the search index of the
+// nodes.
+var SearchBackend = struct {
+	Elasticsearch string
+	Mongo         string
+}{
+	Elasticsearch: "elasticsearch",
+	Mongo:         "mongo",
+}
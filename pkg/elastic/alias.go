@@ -0,0 +1,179 @@
+package elastic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/olivere/elastic/v7"
+)
+
+// CreateIndex creates the concrete index of the given version, without any
+// alias.
+func (c *esClient) CreateIndex(index Index) error {
+	result, err := c.client.CreateIndex(index.VersionedName()).
+		BodyString(index.Body).
+		Do(context.Background())
+	if err != nil {
+		return fmt.Errorf(
+			"failed to create index %s: %w",
+			index.VersionedName(),
+			err,
+		)
+	}
+	if !result.Acknowledged {
+		return fmt.Errorf(
+			"creation of index %s was not acknowledged",
+			index.VersionedName(),
+		)
+	}
+	return nil
+}
+
+// DeleteIndex deletes the concrete index.
+func (c *esClient) DeleteIndex(name string) error {
+	_, err := c.client.DeleteIndex(name).Do(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to delete index %s: %w", name, err)
+	}
+	return nil
+}
+
+// IndicesByAlias returns the concrete indices behind the alias. If the name
+// is a concrete index rather than an alias, the index itself is returned.
+func (c *esClient) IndicesByAlias(alias string) ([]string, error) {
+	result, err := c.client.Aliases().Alias(alias).Do(context.Background())
+	if err != nil && !elastic.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get alias %s: %w", alias, err)
+	}
+	if err == nil {
+		if indices := result.IndicesByAlias(alias); len(indices) > 0 {
+			return indices, nil
+		}
+	}
+
+	exists, err := c.client.IndexExists(alias).Do(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return []string{alias}, nil
+	}
+	return nil, nil
+}
+
+// SwapAlias atomically points the alias at the index. The alias is removed
+// from the indices it pointed at before; a concrete index named like the
+// alias (created before indices were versioned) is deleted in the same
+// request so that the name can be taken over by the alias.
+func (c *esClient) SwapAlias(alias string, index string) error {
+	current, err := c.IndicesByAlias(alias)
+	if err != nil {
+		return err
+	}
+
+	actions := []elastic.AliasAction{
+		elastic.NewAliasAddAction(alias).Index(index),
+	}
+	for _, name := range current {
+		switch name {
+		case index:
+		case alias:
+			actions = append(actions, elastic.NewAliasRemoveIndexAction(name))
+		default:
+			actions = append(
+				actions,
+				elastic.NewAliasRemoveAction(alias).Index(name),
+			)
+		}
+	}
+
+	result, err := c.client.Alias().Action(actions...).Do(context.Background())
+	if err != nil {
+		return fmt.Errorf(
+			"failed to point alias %s at %s: %w",
+			alias,
+			index,
+			err,
+		)
+	}
+	if !result.Acknowledged {
+		return fmt.Errorf(
+			"alias swap of %s to %s was not acknowledged",
+			alias,
+			index,
+		)
+	}
+	return nil
+}
+
+// Refresh makes the documents indexed so far visible to searches.
+func (c *esClient) Refresh(index string) error {
+	_, err := c.client.Refresh(index).Do(context.Background())
+	return err
+}
+
+// BulkIndex indexes the documents in a single request.
+func (c *esClient) BulkIndex(index string, docs []Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	bulk := c.client.Bulk().Index(index)
+	for _, doc := range docs {
+		bulk.Add(elastic.NewBulkIndexRequest().Id(doc.ID).Doc(doc.Body))
+	}
+
+	result, err := bulk.Do(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to bulk index into %s: %w", index, err)
+	}
+
+	failed := result.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+	reasons := make([]string, 0, len(failed))
+	for _, item := range failed {
+		reason := "unknown error"
+		if item.Error != nil {
+			reason = item.Error.Reason
+		}
+		reasons = append(reasons, item.Id+": "+reason)
+	}
+	return fmt.Errorf(
+		"failed to index %d documents into %s: %s",
+		len(failed),
+		index,
+		strings.Join(reasons, "; "),
+	)
+}
+
+// GetSources returns the source of the documents found in the index, keyed by
+// document ID.
+func (c *esClient) GetSources(
+	index string,
+	ids []string,
+) (map[string]json.RawMessage, error) {
+	sources := make(map[string]json.RawMessage, len(ids))
+	if len(ids) == 0 {
+		return sources, nil
+	}
+
+	mget := c.client.MultiGet()
+	for _, id := range ids {
+		mget.Add(elastic.NewMultiGetItem().Index(index).Id(id))
+	}
+
+	result, err := mget.Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get documents from %s: %w", index, err)
+	}
+	for _, doc := range result.Docs {
+		if doc.Found {
+			sources[doc.Id] = doc.Source
+		}
+	}
+	return sources, nil
+}
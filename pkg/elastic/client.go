@@ -18,8 +18,8 @@ func (c *esClient) setClient(client *elastic.Client) {
 	c.client = client
 }
 
-func (c *esClient) GetClient() *elastic.Client {
-	return c.client
+func (c *esClient) IsConfigured() bool {
+	return c.client != nil
 }
 
 // Ping checks the health status of the Elasticsearch cluster, specifically
@@ -41,6 +41,10 @@ func (c *esClient) Ping() error {
 	return nil
 }
 
+// CreateMappings creates the indices that don't exist yet. Versioned indices
+// are created under their versioned name with an alias named after the index.
+// Existing indices are left untouched, changing their mappings requires a
+// reindex followed by an alias swap.
 func (c *esClient) CreateMappings(indices []Index) error {
 	for _, index := range indices {
 		exists, err := c.client.IndexExists(index.Name).
@@ -48,25 +52,51 @@ func (c *esClient) CreateMappings(indices []Index) error {
 		if err != nil {
 			return err
 		}
-		if !exists {
-			createIndex, err := c.client.CreateIndex(index.Name).
-				BodyString(index.Body).
-				Do(context.Background())
-			if err != nil {
-				return err
-			}
-			if !createIndex.Acknowledged {
-				return err
-			}
+		if exists {
+			c.logOutdatedIndex(index)
+			continue
+		}
+
+		if err := c.CreateIndex(index); err != nil {
+			return err
+		}
+		if index.Version == 0 {
+			continue
+		}
+		if err := c.SwapAlias(index.Name, index.VersionedName()); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
+// logOutdatedIndex warns when the alias doesn't point at the expected version.
+func (c *esClient) logOutdatedIndex(index Index) {
+	if index.Version == 0 {
+		return
+	}
+	current, err := c.IndicesByAlias(index.Name)
+	if err != nil {
+		logger.Error("Failed to get the indices behind an alias", err)
+		return
+	}
+	if len(current) == 1 && current[0] == index.VersionedName() {
+		return
+	}
+	logger.Info(
+		fmt.Sprintf(
+			"Index %s is not served by %s yet, run the reindex command to migrate it",
+			index.Name,
+			index.VersionedName(),
+		),
+	)
+}
+
+// Index indexes the document and returns its generated ID.
 func (c *esClient) Index(
 	index string,
 	doc interface{},
-) (*elastic.IndexResponse, error) {
+) (string, error) {
 	ctx := context.Background()
 	result, err := c.client.Index().
 		Index(index).
@@ -80,19 +110,19 @@ func (c *esClient) Index(
 			),
 			err,
 		)
-		return nil, err
+		return "", err
 	}
 
-	return result, nil
+	return result.Id, nil
 }
 
 func (c *esClient) IndexWithID(
 	index string,
 	id string,
 	doc interface{},
-) (*elastic.IndexResponse, error) {
+) error {
 	ctx := context.Background()
-	result, err := c.client.Index().
+	_, err := c.client.Index().
 		Index(index).
 		Id(id).
 		BodyJson(doc).
@@ -105,16 +135,16 @@ func (c *esClient) IndexWithID(
 			),
 			err,
 		)
-		return nil, err
+		return err
 	}
 
-	return result, nil
+	return nil
 }
 
 func (c *esClient) Search(
 	index string,
 	q *Query,
-) (*elastic.SearchResult, error) {
+) (*SearchResult, error) {
 	ctx := context.Background()
 
 	// sort strategy - 1. _score 2. primary_url
@@ -140,7 +170,7 @@ func (c *esClient) Search(
 		return nil, err
 	}
 
-	return result, nil
+	return newSearchResult(result), nil
 }
 
 func (c *esClient) Update(
@@ -239,7 +269,7 @@ func (c *esClient) Export(
 	index string,
 	q *Query,
 	searchAfter []interface{},
-) (*elastic.SearchResult, error) {
+) (*SearchResult, error) {
 	ctx := context.Background()
 
 	sortQuery1 := elastic.NewFieldSort("last_updated")
@@ -265,13 +295,13 @@ func (c *esClient) Export(
 		return nil, err
 	}
 
-	return result, nil
+	return newSearchResult(result), nil
 }
 
 func (c *esClient) GetNodes(
 	index string,
 	q *Query,
-) (*elastic.SearchResult, error) {
+) (*SearchResult, error) {
 	ctx := context.Background()
 
 	source := elastic.NewFetchSourceContext(true).
@@ -301,5 +331,5 @@ func (c *esClient) GetNodes(
 		return nil, err
 	}
 
-	return result, nil
+	return newSearchResult(result), nil
 }
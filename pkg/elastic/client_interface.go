@@ -1,6 +1,7 @@
 package elastic
 
 import (
+	"encoding/json"
 	"os"
 	"time"
 
@@ -17,18 +18,28 @@ var (
 
 type esClientInterface interface {
 	CreateMappings([]Index) error
-	Index(string, interface{}) (*elastic.IndexResponse, error)
-	IndexWithID(string, string, interface{}) (*elastic.IndexResponse, error)
-	Search(string, *Query) (*elastic.SearchResult, error)
+	Index(string, interface{}) (string, error)
+	IndexWithID(string, string, interface{}) error
+	Search(string, *Query) (*SearchResult, error)
 	Update(string, string, map[string]interface{}) error
 	UpdateMany(string, *Query, map[string]interface{}) error
 	Delete(string, string) error
 	DeleteMany(string, *Query) error
-	Export(string, *Query, []interface{}) (*elastic.SearchResult, error)
-	GetNodes(string, *Query) (*elastic.SearchResult, error)
+	Export(string, *Query, []interface{}) (*SearchResult, error)
+	GetNodes(string, *Query) (*SearchResult, error)
 	Ping() error
 
-	GetClient() *elastic.Client
+	CreateIndex(Index) error
+	DeleteIndex(string) error
+	IndicesByAlias(string) ([]string, error)
+	SwapAlias(string, string) error
+	Refresh(string) error
+	BulkIndex(string, []Document) error
+	GetSources(string, []string) (map[string]json.RawMessage, error)
+	Scroll(string, []string, int, func([]Hit) error) error
+
+	// IsConfigured returns whether a client was created with NewClient.
+	IsConfigured() bool
 	setClient(*elastic.Client)
 }
 
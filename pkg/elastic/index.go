@@ -1,6 +1,28 @@
 package elastic
 
+import "fmt"
+
+// Index describes an Elasticsearch index and its settings and mappings.
+//
+// When Version is set, Name is an alias pointing at the versioned index
+// (e.g. "nodes" -> "nodes-v3"), so a new version with different mappings can
+// be built next to the live one and swapped in without downtime.
 type Index struct {
-	Name string
-	Body string
+	Name    string
+	Version int
+	Body    string
+}
+
+// VersionedName returns the name of the concrete index backing the alias.
+func (i Index) VersionedName() string {
+	if i.Version == 0 {
+		return i.Name
+	}
+	return fmt.Sprintf("%s-v%d", i.Name, i.Version)
+}
+
+// Document is a document to index in bulk.
+type Document struct {
+	ID   string
+	Body interface{}
 }
@@ -1,6 +1,8 @@
 package elastic
 
 import (
+	"encoding/json"
+
 	elastic "github.com/olivere/elastic/v7"
 )
 
@@ -10,8 +12,8 @@ type mockClient struct {
 func (*mockClient) setClient(_ *elastic.Client) {
 }
 
-func (*mockClient) GetClient() *elastic.Client {
-	return nil
+func (*mockClient) IsConfigured() bool {
+	return false
 }
 
 func (mockClient) Ping() error {
@@ -25,22 +27,22 @@ func (*mockClient) CreateMappings(_ []Index) error {
 func (*mockClient) Index(
 	_ string,
 	_ interface{},
-) (*elastic.IndexResponse, error) {
-	return nil, nil
+) (string, error) {
+	return "", nil
 }
 
 func (*mockClient) IndexWithID(
 	_ string,
 	_ string,
 	_ interface{},
-) (*elastic.IndexResponse, error) {
-	return nil, nil
+) error {
+	return nil
 }
 
 func (*mockClient) Search(
 	_ string,
 	_ *Query,
-) (*elastic.SearchResult, error) {
+) (*SearchResult, error) {
 	return nil, nil
 }
 
@@ -72,13 +74,53 @@ func (*mockClient) Export(
 	_ string,
 	_ *Query,
 	_ []interface{},
-) (*elastic.SearchResult, error) {
+) (*SearchResult, error) {
 	return nil, nil
 }
 
 func (*mockClient) GetNodes(
 	_ string,
 	_ *Query,
-) (*elastic.SearchResult, error) {
+) (*SearchResult, error) {
+	return nil, nil
+}
+
+func (*mockClient) CreateIndex(_ Index) error {
+	return nil
+}
+
+func (*mockClient) DeleteIndex(_ string) error {
+	return nil
+}
+
+func (*mockClient) IndicesByAlias(_ string) ([]string, error) {
 	return nil, nil
 }
+
+func (*mockClient) SwapAlias(_ string, _ string) error {
+	return nil
+}
+
+func (*mockClient) Refresh(_ string) error {
+	return nil
+}
+
+func (*mockClient) BulkIndex(_ string, _ []Document) error {
+	return nil
+}
+
+func (*mockClient) GetSources(
+	_ string,
+	_ []string,
+) (map[string]json.RawMessage, error) {
+	return map[string]json.RawMessage{}, nil
+}
+
+func (*mockClient) Scroll(
+	_ string,
+	_ []string,
+	_ int,
+	_ func([]Hit) error,
+) error {
+	return nil
+}
@@ -48,3 +48,7 @@ func NewWildcardQuery(name, wildcard string) *elastic.WildcardQuery {
 func NewExistQuery(name string) *elastic.ExistsQuery {
 	return elastic.NewExistsQuery(name)
 }
+
+func NewIdsQuery(ids ...string) *elastic.IdsQuery {
+	return elastic.NewIdsQuery().Ids(ids...)
+}
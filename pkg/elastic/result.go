@@ -0,0 +1,40 @@
+package elastic
+
+import (
+	"encoding/json"
+
+	elastic "github.com/olivere/elastic/v7"
+)
+
+// SearchResult holds the documents found by a search, so that callers don't
+// depend on the types of the Elasticsearch client.
+type SearchResult struct {
+	// TotalHits is the number of documents matching the query, which can be
+	// more than the hits returned.
+	TotalHits int64
+	Hits      []Hit
+}
+
+// Hit is a document found by a search.
+type Hit struct {
+	ID     string
+	Source json.RawMessage
+	// Sort holds the sort values of the document, to search after it.
+	Sort []interface{}
+}
+
+func newSearchResult(result *elastic.SearchResult) *SearchResult {
+	searchResult := &SearchResult{Hits: newHits(result.Hits.Hits)}
+	if result.Hits.TotalHits != nil {
+		searchResult.TotalHits = result.Hits.TotalHits.Value
+	}
+	return searchResult
+}
+
+func newHits(searchHits []*elastic.SearchHit) []Hit {
+	hits := make([]Hit, 0, len(searchHits))
+	for _, hit := range searchHits {
+		hits = append(hits, Hit{ID: hit.Id, Source: hit.Source, Sort: hit.Sort})
+	}
+	return hits
+}
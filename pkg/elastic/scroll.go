@@ -0,0 +1,42 @@
+package elastic
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/olivere/elastic/v7"
+)
+
+// Scroll walks through every document of the index in batches of the given
+// size, passing the hits of each batch to handle. Only the listed source
+// fields are fetched.
+func (c *esClient) Scroll(
+	index string,
+	fields []string,
+	size int,
+	handle func([]Hit) error,
+) error {
+	ctx := context.Background()
+
+	source := elastic.NewFetchSourceContext(true).Include(fields...)
+	scroll := c.client.Scroll(index).
+		FetchSourceContext(source).
+		Size(size)
+	defer func() {
+		_ = scroll.Clear(ctx)
+	}()
+
+	for {
+		result, err := scroll.Do(ctx)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to scroll through %s: %w", index, err)
+		}
+		if err := handle(newHits(result.Hits.Hits)); err != nil {
+			return err
+		}
+	}
+}
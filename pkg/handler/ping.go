@@ -56,7 +56,7 @@ func checkNATS(c *gin.Context) bool {
 
 // checkES verifies Elasticsearch's health. Returns true if healthy or not configured.
 func checkES(c *gin.Context) bool {
-	if elastic.Client.GetClient() == nil {
+	if !elastic.Client.IsConfigured() {
 		// If there is no client, assume Elasticsearch is not configured and return true.
 		return true
 	}
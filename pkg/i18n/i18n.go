@@ -0,0 +1,157 @@
+// Package i18n translates the English messages returned by the services into
+// the languages spoken across the network.
+//
+// English is the source language: messages are written, logged and stored in
+// English and only translated when they are sent back to a client. Each
+// catalog maps an English message to its translation. Messages containing
+// variable parts are described by templates where every `%s` stands for a
+// value that is carried over into the translation as is.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Supported lists the languages with a message catalog. English must stay
+// first so it is picked when nothing else matches.
+var Supported = []language.Tag{
+	language.English,
+	language.French,
+	language.German,
+	language.Spanish,
+	language.Portuguese,
+}
+
+//go:embed locales/*.json
+var locales embed.FS
+
+var (
+	matcher  = language.NewMatcher(Supported)
+	catalogs = mustLoadCatalogs()
+)
+
+// Negotiate picks the supported language that best matches the value of an
+// `Accept-Language` header. English is returned when the header is empty,
+// malformed or doesn't match any supported language.
+func Negotiate(acceptLanguage string) language.Tag {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return language.English
+	}
+
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return language.English
+	}
+	return Supported[index]
+}
+
+// Translate returns the message in the given language. The message is
+// returned unchanged when there is no translation for it.
+func Translate(tag language.Tag, message string) string {
+	c, ok := catalogs[tag]
+	if !ok || message == "" {
+		return message
+	}
+	return c.translate(message)
+}
+
+type catalog struct {
+	messages  map[string]string
+	templates []template
+}
+
+type template struct {
+	// literal is the number of fixed characters in the template, used to try
+	// the most specific templates first.
+	literal     int
+	pattern     *regexp.Regexp
+	translation string
+}
+
+func (c *catalog) translate(message string) string {
+	if translation, ok := c.messages[message]; ok {
+		return translation
+	}
+
+	for _, t := range c.templates {
+		matches := t.pattern.FindStringSubmatch(message)
+		if matches == nil {
+			continue
+		}
+		args := make([]interface{}, 0, len(matches)-1)
+		for _, match := range matches[1:] {
+			args = append(args, match)
+		}
+		return fmt.Sprintf(t.translation, args...)
+	}
+
+	return message
+}
+
+func mustLoadCatalogs() map[language.Tag]*catalog {
+	catalogs := make(map[language.Tag]*catalog)
+	for _, tag := range Supported[1:] {
+		c, err := loadCatalog(tag)
+		if err != nil {
+			panic(fmt.Sprintf("i18n: failed to load %s catalog: %v", tag, err))
+		}
+		catalogs[tag] = c
+	}
+	return catalogs
+}
+
+func loadCatalog(tag language.Tag) (*catalog, error) {
+	data, err := locales.ReadFile(path.Join("locales", tag.String()+".json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var messages map[string]string
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return nil, err
+	}
+
+	c := &catalog{messages: make(map[string]string)}
+	for message, translation := range messages {
+		if !strings.Contains(message, "%s") {
+			c.messages[message] = translation
+			continue
+		}
+		c.templates = append(c.templates, newTemplate(message, translation))
+	}
+	sort.Slice(c.templates, func(i, j int) bool {
+		if c.templates[i].literal != c.templates[j].literal {
+			return c.templates[i].literal > c.templates[j].literal
+		}
+		return c.templates[i].pattern.String() < c.templates[j].pattern.String()
+	})
+
+	return c, nil
+}
+
+func newTemplate(message, translation string) template {
+	parts := strings.Split(message, "%s")
+	quoted := make([]string, len(parts))
+	literal := 0
+	for i, part := range parts {
+		quoted[i] = regexp.QuoteMeta(part)
+		literal += len(part)
+	}
+
+	return template{
+		literal: literal,
+		pattern: regexp.MustCompile(
+			"(?s)^" + strings.Join(quoted, "(.+?)") + "$",
+		),
+		translation: translation,
+	}
+}
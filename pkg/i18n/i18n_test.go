@@ -0,0 +1,127 @@
+package i18n_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/i18n"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   language.Tag
+	}{
+		{"", language.English},
+		{"invalid;;q=", language.English},
+		{"fr-CA,fr;q=0.9,en;q=0.8", language.French},
+		{"de", language.German},
+		{"es-MX", language.Spanish},
+		{"pt-BR", language.Portuguese},
+		{"ja", language.English},
+		{"ja,de;q=0.5", language.German},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			require.Equal(t, tt.want, i18n.Negotiate(tt.header))
+		})
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     language.Tag
+		message string
+		want    string
+	}{
+		{
+			name:    "english is returned as is",
+			tag:     language.English,
+			message: "Node Not Found",
+			want:    "Node Not Found",
+		},
+		{
+			name:    "static message",
+			tag:     language.French,
+			message: "Node Not Found",
+			want:    "Nœud introuvable",
+		},
+		{
+			name:    "unknown message",
+			tag:     language.German,
+			message: "Something else",
+			want:    "Something else",
+		},
+		{
+			name:    "template",
+			tag:     language.Spanish,
+			message: "Expected: string - Given: integer - Schema: default-v2.1.0",
+			want:    "Esperado: string - Recibido: integer - Esquema: default-v2.1.0",
+		},
+		{
+			name:    "most specific template wins",
+			tag:     language.Portuguese,
+			message: "Amount must be greater than or equal to 5 - Schema: test_schema-v1.0.0",
+			want:    "O valor deve ser maior ou igual a 5 - Esquema: test_schema-v1.0.0",
+		},
+		{
+			name:    "nested property",
+			tag:     language.German,
+			message: "The `geolocation/lat` property is required - Schema: default-v2.1.0",
+			want:    "Die Eigenschaft `geolocation/lat` ist erforderlich - Schema: default-v2.1.0",
+		},
+		{
+			name:    "optional part",
+			tag:     language.French,
+			message: "The `old_schema-v1.0.0` schema was sunset on 2024-01-01T00:00:00Z, profiles can't be posted with it anymore. Please use `new_schema-v1.0.0` instead.",
+			want:    "Le schéma `old_schema-v1.0.0` a été retiré le 2024-01-01T00:00:00Z, les profils ne peuvent plus être publiés avec lui. Veuillez utiliser `new_schema-v1.0.0` à la place.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, i18n.Translate(tt.tag, tt.message))
+		})
+	}
+}
+
+// TestCatalogs checks that every language translates the same messages and
+// keeps the values of the templates.
+func TestCatalogs(t *testing.T) {
+	var want map[string]string
+	for _, tag := range i18n.Supported[1:] {
+		data, err := os.ReadFile(
+			filepath.Join("locales", tag.String()+".json"),
+		)
+		require.NoError(t, err)
+		var messages map[string]string
+		require.NoError(t, json.Unmarshal(data, &messages))
+
+		for message, translation := range messages {
+			require.Equal(
+				t,
+				strings.Count(message, "%s"),
+				strings.Count(translation, "%s"),
+				"%s: %s",
+				tag,
+				message,
+			)
+		}
+		if want == nil {
+			want = messages
+			continue
+		}
+		require.Len(t, messages, len(want), tag.String())
+		for message := range want {
+			require.Contains(t, messages, message, tag.String())
+		}
+	}
+}
@@ -39,6 +39,7 @@ type Meta struct {
 	TotalPages      int64         `json:"total_pages,omitempty"`
 	Sort            []interface{} `json:"sort,omitempty"`
 	BatchID         string        `json:"batch_id,omitempty"`
+	Notices         []string      `json:"notices,omitempty"`
 }
 
 // JSON API Response Combination
@@ -0,0 +1,125 @@
+// Package librarygen keeps track of the generations of the schema library.
+// The schemaparser writes each update of the library into the collections of
+// a new generation, such as "schemas_g3" and "fields_g3", and then switches
+// the active generation in a single write. The readers of the library
+// therefore never see a partial update, and the previous generation is kept
+// to roll back to.
+//
+// The schemas published through the registry are written to the active
+// generation, while the schemaparser may be writing the next one. The
+// schemaparser adds the published schemas to the generation it activates,
+// and the activation is refused when a schema was published since.
+package librarygen
+
+import (
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/mongo"
+)
+
+// MetaID is the ID of the document holding the generations of the library.
+const MetaID = "library"
+
+// Generation is a complete copy of the schemas and fields of the library.
+type Generation struct {
+	// Number 0 is the library written before the generations, in the
+	// "schemas" and "fields" collections.
+	Number int64 `json:"number" bson:"number"`
+	// Source is the type of the schema source, such as "github".
+	Source string `json:"source,omitempty" bson:"source,omitempty"`
+	// Revision is the revision of the source the generation was loaded
+	// from, such as the time of the last commit on GitHub.
+	Revision string `json:"revision,omitempty" bson:"revision,omitempty"`
+	// Commit is the commit SHA, for the sources using git.
+	Commit string `json:"commit,omitempty" bson:"commit,omitempty"`
+	// CreatedAt is the Unix timestamp when the generation was written.
+	CreatedAt int64 `json:"created_at,omitempty" bson:"created_at,omitempty"`
+}
+
+// Meta holds the active generation of the library and the previous one.
+type Meta struct {
+	ID       string      `json:"-"                  bson:"_id"`
+	Active   Generation  `json:"active"             bson:"active"`
+	Previous *Generation `json:"previous,omitempty" bson:"previous,omitempty"`
+	// PublishedAt is the Unix timestamp when a schema was last published
+	// through the registry.
+	PublishedAt int64 `json:"published_at,omitempty" bson:"published_at,omitempty"`
+	// Version is increased by every change of the meta, including the
+	// publication of a schema.
+	Version int32 `json:"-" bson:"__v,omitempty"`
+}
+
+// Next returns the number of the next generation, which doesn't overwrite
+// the active or the previous one.
+func (m *Meta) Next() int64 {
+	next := m.Active.Number + 1
+	if m.Previous != nil && m.Previous.Number >= next {
+		next = m.Previous.Number + 1
+	}
+	return next
+}
+
+// Collection returns the name of the collection of the generation, such as
+// "schemas_g3" for the "schemas" collection.
+func Collection(base string, number int64) string {
+	if number == 0 {
+		return base
+	}
+	return base + "_g" + strconv.FormatInt(number, 10)
+}
+
+// GetMeta returns the generations of the library. Until the first update, the
+// active generation is generation 0.
+func GetMeta() (*Meta, error) {
+	result := mongo.Client.FindOne(
+		constant.MongoIndex.SchemaMeta,
+		bson.M{"_id": MetaID},
+	)
+
+	var meta Meta
+	err := result.Decode(&meta)
+	if err == mongo.ErrNoDocuments {
+		return &Meta{ID: MetaID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+// VersionFilter returns the filter matching the meta as long as it isn't
+// changed, such as by the publication of a schema.
+func (m *Meta) VersionFilter() bson.M {
+	filter := bson.M{"_id": MetaID, "__v": m.Version}
+	if m.Version == 0 {
+		// The meta may not be stored yet.
+		filter["__v"] = bson.M{"$in": bson.A{0, nil}}
+	}
+	return filter
+}
+
+// MarkPublished records that a schema was published to the active
+// generation, which changes the version of the meta. It returns false when
+// the active generation isn't the given one anymore, as the schema must then
+// be published to the new one.
+func MarkPublished(active int64, publishedAt int64) (bool, error) {
+	_, err := mongo.Client.FindOneAndUpdate(
+		constant.MongoIndex.SchemaMeta,
+		bson.M{"_id": MetaID, "active.number": active},
+		bson.M{"$set": bson.M{"published_at": publishedAt}},
+		options.FindOneAndUpdate().SetUpsert(true),
+	)
+	// The upsert fails on the duplicate ID when the meta doesn't match.
+	if mongodriver.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
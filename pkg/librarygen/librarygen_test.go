@@ -0,0 +1,74 @@
+package librarygen_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/librarygen"
+)
+
+func TestCollection(t *testing.T) {
+	require.Equal(t, "schemas", librarygen.Collection("schemas", 0))
+	require.Equal(t, "schemas_g3", librarygen.Collection("schemas", 3))
+	require.Equal(t, "fields_g12", librarygen.Collection("fields", 12))
+}
+
+func TestMetaNext(t *testing.T) {
+	tests := []struct {
+		name string
+		meta librarygen.Meta
+		next int64
+	}{
+		{
+			name: "first update",
+			meta: librarygen.Meta{},
+			next: 1,
+		},
+		{
+			name: "after an update",
+			meta: librarygen.Meta{
+				Active:   librarygen.Generation{Number: 2},
+				Previous: &librarygen.Generation{Number: 1},
+			},
+			next: 3,
+		},
+		{
+			// The rolled back generation isn't overwritten until the next
+			// update replaces it as the previous generation.
+			name: "after a rollback",
+			meta: librarygen.Meta{
+				Active:   librarygen.Generation{Number: 1},
+				Previous: &librarygen.Generation{Number: 2},
+			},
+			next: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.next, tt.meta.Next())
+		})
+	}
+}
+
+func TestMetaVersionFilter(t *testing.T) {
+	meta := librarygen.Meta{Version: 4}
+	require.Equal(
+		t,
+		bson.M{"_id": librarygen.MetaID, "__v": int32(4)},
+		meta.VersionFilter(),
+	)
+
+	// Before the first update, the meta may not be stored.
+	meta = librarygen.Meta{}
+	require.Equal(
+		t,
+		bson.M{
+			"_id": librarygen.MetaID,
+			"__v": bson.M{"$in": bson.A{0, nil}},
+		},
+		meta.VersionFilter(),
+	)
+}
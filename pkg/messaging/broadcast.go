@@ -0,0 +1,53 @@
+package messaging
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+)
+
+// Broadcast publishes the message on core NATS so that every subscriber of
+// the subject receives it. Unlike Publish, the message is not persisted in
+// JetStream, so subscribers that are offline will miss it.
+func (b *jetStreamBus) Broadcast(subject string, message any) error {
+	jsonMessage, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf(
+			"error marshaling message to JSON for subject '%s': %v",
+			subject,
+			err,
+		)
+	}
+
+	conn := b.natsClient.Conn()
+	if err := conn.Publish(subject, jsonMessage); err != nil {
+		return fmt.Errorf(
+			"failed to broadcast message to subject '%s': %v",
+			subject,
+			err,
+		)
+	}
+
+	// Make sure the message leaves the process, short-lived jobs may exit
+	// right after broadcasting.
+	return conn.Flush()
+}
+
+// SubscribeBroadcast subscribes to a core NATS subject. Every subscriber
+// receives each message, which makes it suitable for cache invalidation.
+func (b *jetStreamBus) SubscribeBroadcast(
+	subject string,
+	handler MessageHandler,
+) error {
+	sub, err := b.natsClient.Conn().Subscribe(subject, func(msg *nats.Msg) {
+		handler(msg)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to '%s': %w", subject, err)
+	}
+
+	b.natsClient.AddSubscription(sub)
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package messaging
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/natsclient"
+)
+
+// Bus carries the events between the services. The package functions use the
+// bus set with SetBus, or JetStream through the initialized NATS client.
+type Bus interface {
+	// Publish publishes the message to the subject, wrapped in an Envelope
+	// unless it already is one.
+	Publish(subject string, message any) error
+	// PublishSync is like Publish, but waits for the message to be stored.
+	PublishSync(subject string, message any) error
+	// QueueSubscribe processes the messages of the subject. Each message is
+	// processed by one of the subscribers sharing the queue.
+	QueueSubscribe(
+		subject, queue string,
+		handler ProcessFunc,
+		opts ...SubscribeOption,
+	) error
+	// Broadcast sends the message to every current subscriber of the
+	// subject, without storing it.
+	Broadcast(subject string, message any) error
+	// SubscribeBroadcast receives the messages broadcast to the subject.
+	SubscribeBroadcast(subject string, handler MessageHandler) error
+}
+
+var (
+	bus   Bus
+	busMu sync.Mutex
+)
+
+// SetBus sets the bus used by the package functions, such as a MemoryBus to
+// run services without a NATS server. A nil bus restores the default.
+func SetBus(b Bus) {
+	busMu.Lock()
+	defer busMu.Unlock()
+	bus = b
+}
+
+// UsesNATS returns whether the package functions go through JetStream, so the
+// services only connect to NATS when they need to.
+func UsesNATS() bool {
+	busMu.Lock()
+	defer busMu.Unlock()
+	if bus == nil {
+		return true
+	}
+	_, ok := bus.(*jetStreamBus)
+	return ok
+}
+
+// getBus returns the bus set with SetBus, or a JetStream bus using the
+// initialized NATS client.
+func getBus() (Bus, error) {
+	busMu.Lock()
+	defer busMu.Unlock()
+	if bus != nil {
+		return bus, nil
+	}
+
+	natsClient := natsclient.GetInstance()
+	if natsClient == nil {
+		return nil, fmt.Errorf("NATS client is not initialized")
+	}
+	bus = NewJetStreamBus(natsClient)
+	return bus, nil
+}
+
+// Publish publishes the message to the specified subject. The message is
+// wrapped in an Envelope, unless it already is one.
+func Publish(subject string, message any) error {
+	b, err := getBus()
+	if err != nil {
+		return fmt.Errorf("failed to initialize publisher: %v", err)
+	}
+	return b.Publish(subject, message)
+}
+
+// PublishSync is like Publish, but waits for the message to be stored by
+// JetStream.
+func PublishSync(subject string, message any) error {
+	b, err := getBus()
+	if err != nil {
+		return fmt.Errorf("failed to initialize publisher: %v", err)
+	}
+	return b.PublishSync(subject, message)
+}
+
+// QueueSubscribe subscribes to the specified queue.
+func QueueSubscribe(
+	subject, queue string,
+	handler ProcessFunc,
+	opts ...SubscribeOption,
+) error {
+	b, err := getBus()
+	if err != nil {
+		return err
+	}
+	return b.QueueSubscribe(subject, queue, handler, opts...)
+}
+
+// Broadcast sends the message to every subscriber of the subject. Unlike
+// Publish, the message is not stored, so subscribers that are offline will
+// miss it.
+func Broadcast(subject string, message any) error {
+	b, err := getBus()
+	if err != nil {
+		return err
+	}
+	return b.Broadcast(subject, message)
+}
+
+// SubscribeBroadcast subscribes to the messages broadcast to the subject.
+// Every subscriber receives each message, which makes it suitable for cache
+// invalidation.
+func SubscribeBroadcast(subject string, handler MessageHandler) error {
+	b, err := getBus()
+	if err != nil {
+		return err
+	}
+	return b.SubscribeBroadcast(subject, handler)
+}
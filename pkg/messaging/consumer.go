@@ -0,0 +1,205 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+)
+
+// Default consumer settings of queue subscriptions.
+var (
+	// DefaultBatchSize is the number of messages fetched at once.
+	DefaultBatchSize = 10
+	// DefaultAckWait is how long a message may go unacknowledged before it
+	// is redelivered.
+	DefaultAckWait = 30 * time.Second
+)
+
+const (
+	// fetchWait is how long a fetch waits for messages to arrive.
+	fetchWait = 5 * time.Second
+	// statsInterval is how often the consumer statistics are logged.
+	statsInterval = time.Minute
+)
+
+// subscribeOptions holds the settings of a queue subscription.
+type subscribeOptions struct {
+	retry     retryPolicy
+	batchSize int
+	ackWait   time.Duration
+}
+
+// SubscribeOption configures a queue subscription.
+type SubscribeOption func(*subscribeOptions)
+
+// WithBatchSize sets the number of messages fetched at once.
+func WithBatchSize(n int) SubscribeOption {
+	return func(o *subscribeOptions) {
+		if n > 0 {
+			o.batchSize = n
+		}
+	}
+}
+
+// WithAckWait sets how long a message may go unacknowledged before it is
+// redelivered, for example because the pod processing it was killed.
+func WithAckWait(d time.Duration) SubscribeOption {
+	return func(o *subscribeOptions) {
+		if d > 0 {
+			o.ackWait = d
+		}
+	}
+}
+
+func newSubscribeOptions(opts ...SubscribeOption) subscribeOptions {
+	o := subscribeOptions{
+		retry: retryPolicy{
+			maxDeliver: DefaultMaxDeliver,
+			backoff:    DefaultBackoff,
+		},
+		batchSize: DefaultBatchSize,
+		ackWait:   DefaultAckWait,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// consumerConfig returns the configuration of the durable pull consumer of
+// the subject. Redeliveries are limited by the subscriber rather than the
+// server, so the messages which fail too often are dead-lettered.
+func consumerConfig(subject string, o subscribeOptions) *nats.ConsumerConfig {
+	return &nats.ConsumerConfig{
+		Durable:       strings.Split(subject, ".")[1],
+		FilterSubject: subject,
+		AckPolicy:     nats.AckExplicitPolicy,
+		AckWait:       o.ackWait,
+		DeliverPolicy: nats.DeliverAllPolicy,
+		MaxDeliver:    -1,
+	}
+}
+
+// consume fetches the messages of the subscription and processes them one
+// after the other, until the subscription is drained.
+func (b *jetStreamBus) consume(
+	sub *nats.Subscription,
+	subject, queue string,
+	handler ProcessFunc,
+	o subscribeOptions,
+) {
+	stats := newConsumerStats(subject, time.Now())
+	defer func() {
+		stats.log()
+	}()
+
+	for {
+		msgs, err := sub.Fetch(o.batchSize, nats.MaxWait(fetchWait))
+		switch {
+		case err == nil:
+		case errors.Is(err, nats.ErrTimeout),
+			errors.Is(err, context.DeadlineExceeded):
+		case errors.Is(err, nats.ErrSubscriptionClosed),
+			errors.Is(err, nats.ErrBadSubscription),
+			errors.Is(err, nats.ErrConnectionClosed),
+			errors.Is(err, nats.ErrConnectionDraining):
+			return
+		default:
+			logger.Error(
+				fmt.Sprintf("Failed to fetch messages of '%s'", subject),
+				err,
+			)
+			time.Sleep(time.Second)
+		}
+
+		for _, msg := range msgs {
+			stats.record(b.process(msg, queue, handler, o.retry))
+		}
+
+		if now := time.Now(); now.Sub(stats.since) >= statsInterval {
+			stats.log()
+			stats = newConsumerStats(subject, now)
+		}
+	}
+}
+
+// outcome is how the delivery of a message ended.
+type outcome int
+
+const (
+	// outcomeAcked means the message was processed and acknowledged.
+	outcomeAcked outcome = iota
+	// outcomeRetried means the message failed and will be redelivered.
+	outcomeRetried
+	// outcomeDeadLettered means the message was moved to the dead letters.
+	outcomeDeadLettered
+	// outcomeRejected means the message was deliberately not processed and
+	// acknowledged.
+	outcomeRejected
+	// outcomeUnacked means the message could be neither acknowledged nor
+	// rejected, it is redelivered once its ack wait expires.
+	outcomeUnacked
+)
+
+// consumerStats counts the deliveries of a consumer, and is logged
+// periodically so the redeliveries can be graphed and alerted on.
+type consumerStats struct {
+	subject      string
+	since        time.Time
+	delivered    int
+	redelivered  int
+	acked        int
+	rejected     int
+	retried      int
+	deadLettered int
+	unacked      int
+}
+
+func newConsumerStats(subject string, since time.Time) *consumerStats {
+	return &consumerStats{subject: subject, since: since}
+}
+
+// record counts a message which was delivered the given number of times.
+func (c *consumerStats) record(deliveries int, o outcome) {
+	c.delivered++
+	if deliveries > 1 {
+		c.redelivered++
+	}
+	switch o {
+	case outcomeAcked:
+		c.acked++
+	case outcomeRejected:
+		c.rejected++
+	case outcomeRetried:
+		c.retried++
+	case outcomeDeadLettered:
+		c.deadLettered++
+	case outcomeUnacked:
+		c.unacked++
+	}
+}
+
+func (c *consumerStats) log() {
+	if c.delivered == 0 {
+		return
+	}
+	logger.Info(
+		"Consumer statistics",
+		zap.String("subject", c.subject),
+		zap.Duration("period", time.Since(c.since)),
+		zap.Int("delivered", c.delivered),
+		zap.Int("redelivered", c.redelivered),
+		zap.Int("acked", c.acked),
+		zap.Int("rejected", c.rejected),
+		zap.Int("retried", c.retried),
+		zap.Int("dead_lettered", c.deadLettered),
+		zap.Int("unacked", c.unacked),
+	)
+}
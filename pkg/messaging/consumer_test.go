@@ -0,0 +1,55 @@
+package messaging
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSubscribeOptions(t *testing.T) {
+	o := newSubscribeOptions()
+	require.Equal(t, DefaultBatchSize, o.batchSize)
+	require.Equal(t, DefaultAckWait, o.ackWait)
+
+	o = newSubscribeOptions(WithBatchSize(50), WithAckWait(time.Minute))
+	require.Equal(t, 50, o.batchSize)
+	require.Equal(t, time.Minute, o.ackWait)
+
+	// Unset values keep the defaults.
+	o = newSubscribeOptions(WithBatchSize(0), WithAckWait(0))
+	require.Equal(t, DefaultBatchSize, o.batchSize)
+	require.Equal(t, DefaultAckWait, o.ackWait)
+}
+
+func TestConsumerConfig(t *testing.T) {
+	cfg := consumerConfig(
+		NodeValidated,
+		newSubscribeOptions(WithAckWait(time.Minute)),
+	)
+	require.Equal(t, "validated", cfg.Durable)
+	require.Equal(t, NodeValidated, cfg.FilterSubject)
+	require.Equal(t, nats.AckExplicitPolicy, cfg.AckPolicy)
+	require.Equal(t, time.Minute, cfg.AckWait)
+	require.Empty(t, cfg.DeliverSubject)
+	require.Equal(t, -1, cfg.MaxDeliver)
+}
+
+func TestConsumerStats_Record(t *testing.T) {
+	stats := newConsumerStats(NodeValidated, time.Now())
+	stats.record(1, outcomeAcked)
+	stats.record(2, outcomeAcked)
+	stats.record(1, outcomeRetried)
+	stats.record(5, outcomeDeadLettered)
+	stats.record(3, outcomeUnacked)
+	stats.record(1, outcomeRejected)
+
+	require.Equal(t, 6, stats.delivered)
+	require.Equal(t, 3, stats.redelivered)
+	require.Equal(t, 2, stats.acked)
+	require.Equal(t, 1, stats.rejected)
+	require.Equal(t, 1, stats.retried)
+	require.Equal(t, 1, stats.deadLettered)
+	require.Equal(t, 1, stats.unacked)
+}
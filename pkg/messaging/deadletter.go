@@ -0,0 +1,208 @@
+package messaging
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/natsclient"
+)
+
+// Headers describing why a message was dead-lettered.
+const (
+	// HeaderSubject is the subject the message was published to.
+	HeaderSubject = "Dlq-Subject"
+	// HeaderQueue is the queue group which failed to process the message.
+	HeaderQueue = "Dlq-Queue"
+	// HeaderError is the error returned by the last delivery.
+	HeaderError = "Dlq-Error"
+	// HeaderDeliveries is the number of times the message was delivered.
+	HeaderDeliveries = "Dlq-Deliveries"
+	// HeaderFailedAt is when the message was dead-lettered, in RFC 3339.
+	HeaderFailedAt = "Dlq-Failed-At"
+)
+
+// newDeadLetter returns the message to publish to the dead letter subject,
+// with the context of the failure in its headers.
+func newDeadLetter(
+	msg *nats.Msg,
+	queue string,
+	deliveries int,
+	cause error,
+) *nats.Msg {
+	dead := nats.NewMsg(DeadLetter)
+	dead.Data = msg.Data
+	dead.Header.Set(HeaderSubject, msg.Subject)
+	dead.Header.Set(HeaderQueue, queue)
+	dead.Header.Set(HeaderError, cause.Error())
+	dead.Header.Set(HeaderDeliveries, strconv.Itoa(deliveries))
+	dead.Header.Set(HeaderFailedAt, time.Now().UTC().Format(time.RFC3339))
+	return dead
+}
+
+// deadLetter publishes the message to the dead letter subject.
+func (b *jetStreamBus) deadLetter(
+	msg *nats.Msg,
+	queue string,
+	deliveries int,
+	cause error,
+) error {
+	dead := newDeadLetter(msg, queue, deliveries, cause)
+	if _, err := b.natsClient.JsContext.PublishMsg(dead); err != nil {
+		if full, _ := b.natsClient.DeadLetterStreamFull(); full {
+			// The message stays in its stream until the dead letters are
+			// replayed or discarded, which needs someone to step in.
+			logger.Error(
+				"Dead letter queue is full",
+				err,
+				zap.String("stream", natsclient.DeadLetterStreamName),
+				zap.Bool("dlq_full", true),
+			)
+		}
+		return fmt.Errorf(
+			"failed to publish message to subject '%s': %v",
+			DeadLetter,
+			err,
+		)
+	}
+	return nil
+}
+
+// DeadLetterMessage is a message which could not be processed.
+type DeadLetterMessage struct {
+	// Sequence is the position of the message in the stream.
+	Sequence uint64
+	// Subject is the subject the message was published to.
+	Subject string
+	// Queue is the queue group which failed to process the message.
+	Queue string
+	// Error is the error returned by the last delivery.
+	Error string
+	// Deliveries is the number of times the message was delivered.
+	Deliveries int
+	// FailedAt is when the message was dead-lettered.
+	FailedAt time.Time
+	// Data is the payload of the message.
+	Data []byte
+
+	msg *nats.Msg
+}
+
+// DeadLetterQueue reads the dead-lettered messages, to inspect and replay
+// them. The messages which are neither replayed nor discarded stay in the
+// queue once it is closed.
+type DeadLetterQueue struct {
+	natsClient *natsclient.NatsClient
+	sub        *nats.Subscription
+}
+
+// OpenDeadLetterQueue opens the dead letter queue.
+func OpenDeadLetterQueue() (*DeadLetterQueue, error) {
+	natsClient := natsclient.GetInstance()
+	if natsClient == nil {
+		return nil, fmt.Errorf("NATS client is not initialized")
+	}
+
+	sub, err := natsClient.JsContext.PullSubscribe(
+		DeadLetter,
+		"",
+		nats.AckExplicit(),
+		nats.AckWait(5*time.Minute),
+		nats.InactiveThreshold(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to subscribe to '%s': %w",
+			DeadLetter,
+			err,
+		)
+	}
+
+	return &DeadLetterQueue{natsClient: natsClient, sub: sub}, nil
+}
+
+// Fetch returns up to limit dead-lettered messages, oldest first.
+func (q *DeadLetterQueue) Fetch(limit int) ([]*DeadLetterMessage, error) {
+	msgs, err := q.sub.Fetch(limit, nats.MaxWait(2*time.Second))
+	if errors.Is(err, nats.ErrTimeout) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch dead letters: %w", err)
+	}
+
+	dead := make([]*DeadLetterMessage, 0, len(msgs))
+	for _, msg := range msgs {
+		dead = append(dead, toDeadLetterMessage(msg))
+	}
+	return dead, nil
+}
+
+// Replay publishes the message to its original subject again and removes it
+// from the queue.
+func (q *DeadLetterQueue) Replay(dead *DeadLetterMessage) error {
+	if dead.Subject == "" {
+		return fmt.Errorf(
+			"dead letter %d has no original subject",
+			dead.Sequence,
+		)
+	}
+
+	_, err := q.natsClient.JsContext.Publish(dead.Subject, dead.Data)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to publish message to subject '%s': %v",
+			dead.Subject,
+			err,
+		)
+	}
+	return q.Discard(dead)
+}
+
+// Discard removes the message from the queue.
+func (q *DeadLetterQueue) Discard(dead *DeadLetterMessage) error {
+	// The dead letter stream keeps the acknowledged messages, they have to
+	// be deleted.
+	err := q.natsClient.JsContext.DeleteMsg(
+		natsclient.DeadLetterStreamName,
+		dead.Sequence,
+	)
+	if err != nil && !errors.Is(err, nats.ErrMsgNotFound) {
+		return fmt.Errorf(
+			"failed to remove dead letter %d: %w",
+			dead.Sequence,
+			err,
+		)
+	}
+	// Acknowledging only stops the consumer from delivering it again.
+	return dead.msg.Ack()
+}
+
+// Close releases the messages fetched but neither replayed nor discarded.
+func (q *DeadLetterQueue) Close() error {
+	return q.sub.Unsubscribe()
+}
+
+func toDeadLetterMessage(msg *nats.Msg) *DeadLetterMessage {
+	dead := &DeadLetterMessage{
+		Subject: msg.Header.Get(HeaderSubject),
+		Queue:   msg.Header.Get(HeaderQueue),
+		Error:   msg.Header.Get(HeaderError),
+		Data:    msg.Data,
+		msg:     msg,
+	}
+	if meta, err := msg.Metadata(); err == nil {
+		dead.Sequence = meta.Sequence.Stream
+	}
+	dead.Deliveries, _ = strconv.Atoi(msg.Header.Get(HeaderDeliveries))
+	dead.FailedAt, _ = time.Parse(
+		time.RFC3339,
+		msg.Header.Get(HeaderFailedAt),
+	)
+	return dead
+}
@@ -0,0 +1,199 @@
+package messaging
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// SpecVersion is the version of the CloudEvents specification the envelopes
+// follow.
+const SpecVersion = "1.0"
+
+// eventType describes the events published on a subject.
+type eventType struct {
+	// Type is the CloudEvents type of the events.
+	Type string
+	// DataSchema identifies the payload format. Its version is increased
+	// whenever the payload changes in a way older consumers can't read.
+	DataSchema string
+}
+
+var eventTypes = map[string]eventType{
+	NodeCreated: {
+		Type:       "network.murmurations.node.created",
+		DataSchema: "urn:murmurations:events:node.created:1",
+	},
+	NodeValidated: {
+		Type:       "network.murmurations.node.validated",
+		DataSchema: "urn:murmurations:events:node.validated:1",
+	},
+	NodeValidationFailed: {
+		Type:       "network.murmurations.node.validation_failed",
+		DataSchema: "urn:murmurations:events:node.validation_failed:1",
+	},
+}
+
+// source identifies the service publishing the events.
+var source = "/murmurations/" + filepath.Base(os.Args[0])
+
+// Envelope wraps the payload of an event with the metadata describing it, in
+// the CloudEvents structured JSON format.
+type Envelope struct {
+	// SpecVersion is the CloudEvents specification version. It is empty for
+	// the messages published before the envelope was introduced.
+	SpecVersion string `json:"specversion"`
+	// ID uniquely identifies the event, publishing it again keeps its ID.
+	ID string `json:"id"`
+	// Type is the kind of event, such as network.murmurations.node.created.
+	Type string `json:"type"`
+	// Source is the service which published the event.
+	Source string `json:"source"`
+	// Time is when the event occurred.
+	Time time.Time `json:"time"`
+	// DataContentType is the media type of Data.
+	DataContentType string `json:"datacontenttype,omitempty"`
+	// DataSchema identifies the format and version of Data.
+	DataSchema string `json:"dataschema,omitempty"`
+	// Data is the payload of the event.
+	Data json.RawMessage `json:"data"`
+}
+
+// NewEnvelope wraps the payload of an event published to the subject.
+func NewEnvelope(subject string, data any) (*Envelope, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"error marshaling message to JSON for subject '%s': %v",
+			subject,
+			err,
+		)
+	}
+
+	id, err := newEventID()
+	if err != nil {
+		return nil, err
+	}
+
+	t := typeOf(subject)
+	return &Envelope{
+		SpecVersion:     SpecVersion,
+		ID:              id,
+		Type:            t.Type,
+		Source:          source,
+		Time:            time.Now().UTC(),
+		DataContentType: "application/json",
+		DataSchema:      t.DataSchema,
+		Data:            raw,
+	}, nil
+}
+
+// ParseEnvelope reads the envelope of a message published to the subject.
+//
+// The messages published before the envelope was introduced only hold the
+// payload. They are returned wrapped in an envelope without SpecVersion, ID,
+// Source or Time.
+func ParseEnvelope(subject string, data []byte) (*Envelope, error) {
+	var probe struct {
+		SpecVersion string `json:"specversion"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse event envelope: %w", err)
+	}
+
+	if probe.SpecVersion == "" {
+		t := typeOf(subject)
+		return &Envelope{
+			Type:       t.Type,
+			DataSchema: t.DataSchema,
+			Data:       json.RawMessage(bytes.Clone(data)),
+		}, nil
+	}
+
+	var envelope Envelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse event envelope: %w", err)
+	}
+	return &envelope, nil
+}
+
+// Decode reads the payload of the message into v and returns its envelope.
+// It accepts both enveloped messages and bare payloads.
+func Decode(msg *nats.Msg, v any) (*Envelope, error) {
+	envelope, err := ParseEnvelope(msg.Subject, msg.Data)
+	if err != nil {
+		return nil, err
+	}
+	if err := envelope.Decode(v); err != nil {
+		return nil, err
+	}
+	return envelope, nil
+}
+
+// Decode reads the payload of the event into v.
+func (e *Envelope) Decode(v any) error {
+	if err := json.Unmarshal(e.Data, v); err != nil {
+		return fmt.Errorf("failed to decode %s event: %w", e.Type, err)
+	}
+	return nil
+}
+
+// encode returns the envelope of the message and its JSON encoding. The
+// message is wrapped in a new envelope unless it already is one, either as an
+// *Envelope or as its JSON encoding, such as the events kept in an outbox.
+func encode(subject string, message any) (*Envelope, []byte, error) {
+	switch m := message.(type) {
+	case *Envelope:
+		data, err := json.Marshal(m)
+		if err != nil {
+			return nil, nil, fmt.Errorf(
+				"error marshaling message to JSON for subject '%s': %v",
+				subject,
+				err,
+			)
+		}
+		return m, data, nil
+	case json.RawMessage:
+		envelope, err := ParseEnvelope(subject, m)
+		if err != nil {
+			return nil, nil, err
+		}
+		if envelope.SpecVersion != "" {
+			return envelope, m, nil
+		}
+	}
+
+	envelope, err := NewEnvelope(subject, message)
+	if err != nil {
+		return nil, nil, err
+	}
+	return encode(subject, envelope)
+}
+
+// typeOf returns the event type of the subject.
+func typeOf(subject string) eventType {
+	if t, ok := eventTypes[subject]; ok {
+		return t
+	}
+	return eventType{Type: subject}
+}
+
+// newEventID returns a random (version 4) UUID.
+func newEventID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate event ID: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf(
+		"%x-%x-%x-%x-%x",
+		b[0:4], b[4:6], b[6:8], b[8:10], b[10:],
+	), nil
+}
@@ -0,0 +1,112 @@
+package messaging
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEnvelope(t *testing.T) {
+	envelope, err := NewEnvelope(NodeCreated, NodeCreatedData{
+		ProfileURL: "https://ic3.dev/test.json",
+		Version:    2,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, SpecVersion, envelope.SpecVersion)
+	require.Len(t, envelope.ID, 36)
+	require.Equal(t, "network.murmurations.node.created", envelope.Type)
+	require.Equal(
+		t,
+		"urn:murmurations:events:node.created:1",
+		envelope.DataSchema,
+	)
+	require.NotEmpty(t, envelope.Source)
+	require.False(t, envelope.Time.IsZero())
+	require.JSONEq(
+		t,
+		`{"profile_url":"https://ic3.dev/test.json","version":2}`,
+		string(envelope.Data),
+	)
+
+	other, err := NewEnvelope(NodeCreated, NodeCreatedData{})
+	require.NoError(t, err)
+	require.NotEqual(t, envelope.ID, other.ID)
+}
+
+func TestDecode(t *testing.T) {
+	envelope, err := NewEnvelope(NodeValidationFailed, NodeValidationFailedData{
+		ProfileURL: "https://ic3.dev/test.json",
+		Version:    3,
+	})
+	require.NoError(t, err)
+	enveloped, err := json.Marshal(envelope)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		data string
+		id   string
+	}{
+		{"envelope", string(enveloped), envelope.ID},
+		{
+			"bare payload",
+			`{"profile_url":"https://ic3.dev/test.json","version":3}`,
+			"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var data NodeValidationFailedData
+			got, err := Decode(&nats.Msg{
+				Subject: NodeValidationFailed,
+				Data:    []byte(tt.data),
+			}, &data)
+			require.NoError(t, err)
+			require.Equal(t, tt.id, got.ID)
+			require.Equal(
+				t,
+				"network.murmurations.node.validation_failed",
+				got.Type,
+			)
+			require.Equal(t, "https://ic3.dev/test.json", data.ProfileURL)
+			require.Equal(t, int32(3), data.Version)
+		})
+	}
+
+	var data NodeCreatedData
+	_, err = Decode(&nats.Msg{Subject: NodeCreated, Data: []byte("{")}, &data)
+	require.Error(t, err)
+}
+
+func TestEncode(t *testing.T) {
+	// Payloads are wrapped in a new envelope.
+	envelope, data, err := encode(NodeCreated, NodeCreatedData{Version: 1})
+	require.NoError(t, err)
+	require.NotEmpty(t, envelope.ID)
+	parsed, err := ParseEnvelope(NodeCreated, data)
+	require.NoError(t, err)
+	require.Equal(t, envelope.ID, parsed.ID)
+
+	// Encoded envelopes are published as they are, keeping their ID.
+	again, raw, err := encode(NodeCreated, json.RawMessage(data))
+	require.NoError(t, err)
+	require.Equal(t, envelope.ID, again.ID)
+	require.Equal(t, data, raw)
+
+	// Bare JSON payloads are wrapped.
+	wrapped, raw, err := encode(
+		NodeCreated,
+		json.RawMessage(`{"profile_url":"https://ic3.dev/test.json"}`),
+	)
+	require.NoError(t, err)
+	require.NotEmpty(t, wrapped.ID)
+	require.JSONEq(
+		t,
+		`{"profile_url":"https://ic3.dev/test.json"}`,
+		string(wrapped.Data),
+	)
+	require.NotEqual(t, `{"profile_url":"https://ic3.dev/test.json"}`, raw)
+}
@@ -0,0 +1,311 @@
+package messaging
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+)
+
+// MemoryBus is a Bus delivering the messages within the process, so services
+// can be wired together without a NATS server, in integration tests and in
+// development.
+//
+// Like JetStream, it keeps the messages published to a subject nobody
+// subscribed to yet, and redelivers the messages which fail with the backoff
+// of the subscription before dead-lettering them. Subjects are matched
+// exactly, wildcards are not supported.
+type MemoryBus struct {
+	mu       sync.Mutex
+	defaults []SubscribeOption
+	// queues are the queue groups subscribed to each subject.
+	queues map[string]map[string]*memoryQueue
+	// pending are the messages of the subjects without subscribers.
+	pending    map[string][]*nats.Msg
+	broadcasts map[string][]MessageHandler
+	closed     bool
+	// inflight counts the messages which are not processed yet.
+	inflight sync.WaitGroup
+}
+
+// NewMemoryBus returns an empty MemoryBus. The options apply to every
+// subscription, before the options of the subscription itself, for example
+// to shorten the backoff in tests.
+func NewMemoryBus(defaults ...SubscribeOption) *MemoryBus {
+	return &MemoryBus{
+		defaults:   defaults,
+		queues:     make(map[string]map[string]*memoryQueue),
+		pending:    make(map[string][]*nats.Msg),
+		broadcasts: make(map[string][]MessageHandler),
+	}
+}
+
+// Publish delivers the message to the queue groups of the subject.
+func (b *MemoryBus) Publish(subject string, message any) error {
+	envelope, data, err := encode(subject, message)
+	if err != nil {
+		return err
+	}
+
+	msg := nats.NewMsg(subject)
+	msg.Header.Set(nats.MsgIdHdr, envelope.ID)
+	msg.Data = data
+	return b.publishMsg(msg)
+}
+
+// PublishSync is the same as Publish.
+func (b *MemoryBus) PublishSync(subject string, message any) error {
+	return b.Publish(subject, message)
+}
+
+func (b *MemoryBus) publishMsg(msg *nats.Msg) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return fmt.Errorf(
+			"failed to publish message to subject '%s': bus is closed",
+			msg.Subject,
+		)
+	}
+
+	queues := b.queues[msg.Subject]
+	if len(queues) == 0 {
+		b.pending[msg.Subject] = append(b.pending[msg.Subject], msg)
+		return nil
+	}
+	for _, q := range queues {
+		b.enqueue(q, memoryDelivery{msg: copyMsg(msg), deliveries: 1})
+	}
+	return nil
+}
+
+// QueueSubscribe processes the messages of the subject in the background.
+// The subscribers sharing the queue take turns processing its messages.
+func (b *MemoryBus) QueueSubscribe(
+	subject, queue string,
+	handler ProcessFunc,
+	opts ...SubscribeOption,
+) error {
+	o := newSubscribeOptions(slices.Concat(b.defaults, opts)...)
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return fmt.Errorf("failed to subscribe to queue: bus is closed")
+	}
+	if b.queues[subject] == nil {
+		b.queues[subject] = make(map[string]*memoryQueue)
+	}
+	q, ok := b.queues[subject][queue]
+	if !ok {
+		q = newMemoryQueue(queue)
+		b.queues[subject][queue] = q
+		// The first queue group gets the messages kept so far.
+		for _, msg := range b.pending[subject] {
+			b.enqueue(q, memoryDelivery{msg: msg, deliveries: 1})
+		}
+		delete(b.pending, subject)
+	}
+	b.mu.Unlock()
+
+	go func() {
+		for {
+			d, ok := q.pop()
+			if !ok {
+				return
+			}
+			b.process(q, d, handler, o.retry)
+		}
+	}()
+	return nil
+}
+
+// process runs the handler on the message, then redelivers or dead-letters
+// the message if it fails.
+func (b *MemoryBus) process(
+	q *memoryQueue,
+	d memoryDelivery,
+	handler ProcessFunc,
+	policy retryPolicy,
+) {
+	defer b.inflight.Done()
+
+	err := runHandler(handler, d.msg)
+	if err == nil || IsRejected(err) {
+		return
+	}
+
+	fields := []zap.Field{
+		zap.String("subject", d.msg.Subject),
+		zap.String("queue", q.name),
+		zap.Int("deliveries", d.deliveries),
+	}
+
+	if redeliver, delay := policy.redeliverAfter(d.deliveries, err); redeliver {
+		b.inflight.Add(1)
+		time.AfterFunc(delay, func() {
+			defer b.inflight.Done()
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			b.enqueue(q, memoryDelivery{
+				msg:        d.msg,
+				deliveries: d.deliveries + 1,
+			})
+		})
+		return
+	}
+
+	dead := newDeadLetter(d.msg, q.name, d.deliveries, err)
+	if dlqErr := b.publishMsg(dead); dlqErr != nil {
+		logger.Error("Failed to dead-letter message", dlqErr, fields...)
+		return
+	}
+	logger.Error("Message dead-lettered", err, fields...)
+}
+
+// enqueue adds the delivery to the queue. It must be called with the lock
+// held.
+func (b *MemoryBus) enqueue(q *memoryQueue, d memoryDelivery) {
+	if b.closed {
+		return
+	}
+	b.inflight.Add(1)
+	q.push(d)
+}
+
+// Broadcast passes the message to every subscriber of the subject.
+func (b *MemoryBus) Broadcast(subject string, message any) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf(
+			"error marshaling message to JSON for subject '%s': %v",
+			subject,
+			err,
+		)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, handler := range b.broadcasts[subject] {
+		msg := &nats.Msg{Subject: subject, Data: data}
+		b.inflight.Add(1)
+		go func() {
+			defer b.inflight.Done()
+			handler(msg)
+		}()
+	}
+	return nil
+}
+
+// SubscribeBroadcast receives the messages broadcast to the subject from now
+// on.
+func (b *MemoryBus) SubscribeBroadcast(
+	subject string,
+	handler MessageHandler,
+) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.broadcasts[subject] = append(b.broadcasts[subject], handler)
+	return nil
+}
+
+// Flush waits until the messages published so far, and the ones published
+// while processing them, are processed. It includes the redeliveries of the
+// messages which failed.
+func (b *MemoryBus) Flush() {
+	b.inflight.Wait()
+}
+
+// Pending returns the messages of the subject which no subscriber received,
+// such as the dead letters.
+func (b *MemoryBus) Pending(subject string) []*nats.Msg {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]*nats.Msg(nil), b.pending[subject]...)
+}
+
+// Close stops the subscriptions. The messages not processed yet are dropped.
+func (b *MemoryBus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for _, queues := range b.queues {
+		for _, q := range queues {
+			for range q.close() {
+				b.inflight.Done()
+			}
+		}
+	}
+}
+
+// memoryDelivery is a delivery of a message to a queue group.
+type memoryDelivery struct {
+	msg        *nats.Msg
+	deliveries int
+}
+
+// memoryQueue holds the deliveries of a queue group, in order.
+type memoryQueue struct {
+	name   string
+	mu     sync.Mutex
+	ready  *sync.Cond
+	items  []memoryDelivery
+	closed bool
+}
+
+func newMemoryQueue(name string) *memoryQueue {
+	q := &memoryQueue{name: name}
+	q.ready = sync.NewCond(&q.mu)
+	return q
+}
+
+func (q *memoryQueue) push(d memoryDelivery) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.items = append(q.items, d)
+	q.ready.Signal()
+}
+
+// pop waits for the next delivery. It returns false once the queue is
+// closed.
+func (q *memoryQueue) pop() (memoryDelivery, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.items) == 0 && !q.closed {
+		q.ready.Wait()
+	}
+	if q.closed {
+		return memoryDelivery{}, false
+	}
+	d := q.items[0]
+	q.items = q.items[1:]
+	return d, true
+}
+
+// close stops the queue and returns the deliveries it dropped.
+func (q *memoryQueue) close() []memoryDelivery {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.ready.Broadcast()
+	dropped := q.items
+	q.items = nil
+	return dropped
+}
+
+// copyMsg returns a copy of the message for another queue group, so their
+// handlers don't share it.
+func copyMsg(msg *nats.Msg) *nats.Msg {
+	copied := nats.NewMsg(msg.Subject)
+	copied.Data = msg.Data
+	for key, values := range msg.Header {
+		copied.Header[key] = append([]string(nil), values...)
+	}
+	return copied
+}
@@ -0,0 +1,156 @@
+package messaging_test
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/messaging"
+)
+
+func TestMemoryBus_DeliversToEachQueueGroup(t *testing.T) {
+	bus := messaging.NewMemoryBus()
+	defer bus.Close()
+
+	// Messages published before anyone subscribed are kept.
+	require.NoError(t, bus.Publish(
+		messaging.NodeCreated,
+		messaging.NodeCreatedData{ProfileURL: "https://ic3.dev/a.json"},
+	))
+
+	received := make(chan string, 2)
+	err := bus.QueueSubscribe(
+		messaging.NodeCreated,
+		"validation",
+		func(msg *nats.Msg) error {
+			var data messaging.NodeCreatedData
+			envelope, err := messaging.Decode(msg, &data)
+			require.NoError(t, err)
+			require.Equal(t, envelope.ID, msg.Header.Get(nats.MsgIdHdr))
+			received <- data.ProfileURL
+			return nil
+		},
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, bus.Publish(
+		messaging.NodeCreated,
+		messaging.NodeCreatedData{ProfileURL: "https://ic3.dev/b.json"},
+	))
+	bus.Flush()
+
+	close(received)
+	var urls []string
+	for url := range received {
+		urls = append(urls, url)
+	}
+	require.Equal(
+		t,
+		[]string{"https://ic3.dev/a.json", "https://ic3.dev/b.json"},
+		urls,
+	)
+}
+
+func TestMemoryBus_RedeliversThenDeadLetters(t *testing.T) {
+	bus := messaging.NewMemoryBus(messaging.WithBackoff(time.Millisecond))
+	defer bus.Close()
+
+	var attempts atomic.Int32
+	err := bus.QueueSubscribe(
+		messaging.NodeValidated,
+		"index",
+		func(_ *nats.Msg) error {
+			attempts.Add(1)
+			return errors.New("elasticsearch is unavailable")
+		},
+		messaging.WithMaxDeliver(3),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, bus.Publish(
+		messaging.NodeValidated,
+		messaging.NodeValidatedData{ProfileURL: "https://ic3.dev/a.json"},
+	))
+	bus.Flush()
+
+	require.Equal(t, int32(3), attempts.Load())
+	dead := bus.Pending(messaging.DeadLetter)
+	require.Len(t, dead, 1)
+	require.Equal(
+		t,
+		messaging.NodeValidated,
+		dead[0].Header.Get(messaging.HeaderSubject),
+	)
+	require.Equal(t, "3", dead[0].Header.Get(messaging.HeaderDeliveries))
+}
+
+func TestMemoryBus_PermanentAndRejectedErrors(t *testing.T) {
+	bus := messaging.NewMemoryBus(messaging.WithBackoff(time.Millisecond))
+	defer bus.Close()
+
+	var attempts atomic.Int32
+	err := bus.QueueSubscribe(
+		messaging.NodeValidated,
+		"index",
+		func(msg *nats.Msg) error {
+			attempts.Add(1)
+			var data messaging.NodeValidatedData
+			if _, err := messaging.Decode(msg, &data); err != nil {
+				return err
+			}
+			if data.Version == 0 {
+				return messaging.Permanent(errors.New("missing version"))
+			}
+			return messaging.Reject(errors.New("stale event"))
+		},
+	)
+	require.NoError(t, err)
+
+	for _, version := range []int32{0, 1} {
+		require.NoError(t, bus.Publish(
+			messaging.NodeValidated,
+			messaging.NodeValidatedData{Version: version},
+		))
+	}
+	bus.Flush()
+
+	// Neither is redelivered, only the permanent failure is dead-lettered.
+	require.Equal(t, int32(2), attempts.Load())
+	require.Len(t, bus.Pending(messaging.DeadLetter), 1)
+}
+
+func TestMemoryBus_Broadcast(t *testing.T) {
+	bus := messaging.NewMemoryBus()
+	defer bus.Close()
+
+	var received atomic.Int32
+	for range 2 {
+		err := bus.SubscribeBroadcast(
+			messaging.SchemasUpdated,
+			func(_ *nats.Msg) {
+				received.Add(1)
+			},
+		)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, bus.Broadcast(
+		messaging.SchemasUpdated,
+		messaging.SchemasUpdatedData{Schemas: []string{"people_schema-v0.1.0"}},
+	))
+	bus.Flush()
+
+	require.Equal(t, int32(2), received.Load())
+}
+
+func TestUsesNATS(t *testing.T) {
+	require.True(t, messaging.UsesNATS())
+
+	messaging.SetBus(messaging.NewMemoryBus())
+	defer messaging.SetBus(nil)
+	require.False(t, messaging.UsesNATS())
+}
@@ -5,6 +5,9 @@ import "github.com/MurmurationsNetwork/MurmurationsServices/pkg/jsonapi"
 type NodeCreatedData struct {
 	ProfileURL string `json:"profile_url"`
 	Version    int32  `json:"version"`
+	// Existing is true when the node was already accepted by the index. It
+	// keeps being accepted with the schemas sunset since then.
+	Existing bool `json:"existing,omitempty"`
 }
 
 // NodeValidatedData represents the validated data of a node.
@@ -28,6 +31,10 @@ type NodeValidatedData struct {
 	// Expires is a string representing the expiration date of the node.
 	// It's optional and can be empty if the node doesn't have an expiration date.
 	Expires *int64 `json:"expires,omitempty"`
+
+	// Notices are the notices about the linked schemas, such as the sunset
+	// of a schema the node is still accepted with.
+	Notices []string `json:"notices,omitempty"`
 }
 
 type NodeValidationFailedData struct {
@@ -35,3 +42,10 @@ type NodeValidationFailedData struct {
 	FailureReasons *[]jsonapi.Error `json:"failure_reasons"`
 	Version        int32            `json:"version"`
 }
+
+// SchemasUpdatedData represents the schemas changed by a schemaparser run.
+type SchemasUpdatedData struct {
+	// Schemas lists the names of the updated schemas. An empty list means
+	// every schema should be treated as updated.
+	Schemas []string `json:"schemas"`
+}
@@ -1,78 +1,51 @@
 package messaging
 
 import (
-	"encoding/json"
 	"fmt"
-	"sync"
 
-	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/natsclient"
-)
+	"github.com/nats-io/nats.go"
 
-var (
-	publisherInstance *Publisher
-	publisherOnce     sync.Once
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/natsclient"
 )
 
-type Publisher struct {
+// jetStreamBus is the Bus carrying the events through NATS JetStream.
+type jetStreamBus struct {
 	natsClient *natsclient.NatsClient
 }
 
-// Publish checks for an existing Publisher instance or creates one,
-// and then publishes the message to the specified subject.
-func Publish(subject string, message any) error {
-	var err error
-	publisherOnce.Do(func() {
-		publisherInstance, err = newPublisher()
-	})
-	if err != nil {
-		return fmt.Errorf("failed to initialize publisher: %v", err)
-	}
-
-	jsonMessage, err := json.Marshal(message)
-	if err != nil {
-		return fmt.Errorf(
-			"error marshaling message to JSON for subject '%s': %v",
-			subject,
-			err,
-		)
-	}
-
-	return publisherInstance.publish(subject, jsonMessage)
+// NewJetStreamBus returns a Bus using the given NATS client.
+func NewJetStreamBus(natsClient *natsclient.NatsClient) Bus {
+	return &jetStreamBus{natsClient: natsClient}
 }
 
-func PublishSync(subject string, message any) error {
-	var err error
-	publisherOnce.Do(func() {
-		publisherInstance, err = newPublisher()
-	})
+// Publish publishes the message to the subject without waiting for JetStream
+// to store it.
+func (b *jetStreamBus) Publish(subject string, message any) error {
+	envelope, data, err := encode(subject, message)
 	if err != nil {
-		return fmt.Errorf("failed to initialize publisher: %v", err)
+		return err
 	}
-
-	jsonMessage, err := json.Marshal(message)
-	if err != nil {
-		return fmt.Errorf(
-			"error marshaling message to JSON for subject '%s': %v",
-			subject,
-			err,
-		)
-	}
-
-	return publisherInstance.publishSync(subject, jsonMessage)
+	return b.publish(subject, envelope.ID, data)
 }
 
-// newPublisher creates a new Publisher instance.
-func newPublisher() (*Publisher, error) {
-	natsClient := natsclient.GetInstance()
-	if natsClient == nil {
-		return nil, fmt.Errorf("NATS client is not initialized")
+// PublishSync publishes the message to the subject and waits for JetStream to
+// store it.
+func (b *jetStreamBus) PublishSync(subject string, message any) error {
+	envelope, data, err := encode(subject, message)
+	if err != nil {
+		return err
 	}
-	return &Publisher{natsClient: natsClient}, nil
+	return b.publishSync(subject, envelope.ID, data)
 }
 
-// publish publishes a message to the given subject.
-func (p *Publisher) publish(subject string, message []byte) error {
-	_, err := p.natsClient.JsContext.PublishAsync(subject, message)
+// publish publishes a message to the given subject. JetStream drops the
+// messages published again with the same event ID within its duplicate window.
+func (b *jetStreamBus) publish(subject, id string, message []byte) error {
+	_, err := b.natsClient.JsContext.PublishAsync(
+		subject,
+		message,
+		nats.MsgId(id),
+	)
 	if err != nil {
 		return fmt.Errorf(
 			"failed to publish message to subject '%s': %v",
@@ -83,9 +56,10 @@ func (p *Publisher) publish(subject string, message []byte) error {
 	return nil
 }
 
-// publish publishes a message to the given subject.
-func (p *Publisher) publishSync(subject string, message []byte) error {
-	_, err := p.natsClient.JsContext.Publish(subject, message)
+// publishSync publishes a message to the given subject and waits for its
+// acknowledgement.
+func (b *jetStreamBus) publishSync(subject, id string, message []byte) error {
+	_, err := b.natsClient.JsContext.Publish(subject, message, nats.MsgId(id))
 	if err != nil {
 		return fmt.Errorf(
 			"failed to publish message to subject '%s': %v",
@@ -0,0 +1,123 @@
+package messaging
+
+import (
+	"errors"
+	"time"
+)
+
+// Default redelivery settings of queue subscriptions.
+var (
+	DefaultMaxDeliver = 5
+	DefaultBackoff    = []time.Duration{
+		5 * time.Second,
+		30 * time.Second,
+		2 * time.Minute,
+		10 * time.Minute,
+	}
+)
+
+// permanentError marks an error which redelivering the message won't fix.
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks the error returned by a ProcessFunc as permanent, such as
+// a message which can't be decoded, so the message is dead-lettered right away
+// instead of being redelivered.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err: err}
+}
+
+// IsPermanent reports whether the error was marked with Permanent.
+func IsPermanent(err error) bool {
+	return errors.As(err, &permanentError{})
+}
+
+// rejectedError marks a message which was deliberately not processed.
+type rejectedError struct {
+	err error
+}
+
+func (e rejectedError) Error() string {
+	return e.err.Error()
+}
+
+func (e rejectedError) Unwrap() error {
+	return e.err
+}
+
+// Reject marks the error returned by a ProcessFunc as the reason the message
+// was deliberately not processed, such as a stale or duplicate event. The
+// message is acknowledged and counted as rejected in the consumer statistics.
+func Reject(err error) error {
+	if err == nil {
+		return nil
+	}
+	return rejectedError{err: err}
+}
+
+// IsRejected reports whether the error was marked with Reject.
+func IsRejected(err error) bool {
+	return errors.As(err, &rejectedError{})
+}
+
+// retryPolicy decides whether a message which failed is redelivered.
+type retryPolicy struct {
+	// maxDeliver is the number of deliveries before a message is
+	// dead-lettered.
+	maxDeliver int
+	// backoff is the delay before each redelivery. The last delay is used
+	// for the deliveries beyond it.
+	backoff []time.Duration
+}
+
+// WithMaxDeliver sets the number of deliveries of a message before it is
+// dead-lettered.
+func WithMaxDeliver(n int) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.retry.maxDeliver = n
+	}
+}
+
+// WithBackoff sets the delays before redelivering a message which failed.
+func WithBackoff(delays ...time.Duration) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.retry.backoff = delays
+	}
+}
+
+// redeliverAfter returns whether a message which failed on its given delivery
+// is redelivered, and after which delay.
+func (p retryPolicy) redeliverAfter(
+	deliveries int,
+	err error,
+) (bool, time.Duration) {
+	if IsPermanent(err) || deliveries >= p.maxDeliver {
+		return false, 0
+	}
+	if len(p.backoff) == 0 {
+		return true, 0
+	}
+	i := min(deliveries, len(p.backoff)) - 1
+	return true, p.backoff[max(i, 0)]
+}
+
+// maxBackoff returns the longest delay before a redelivery, which is used
+// while a message can't be dead-lettered.
+func (p retryPolicy) maxBackoff() time.Duration {
+	if len(p.backoff) == 0 {
+		return 0
+	}
+	return p.backoff[len(p.backoff)-1]
+}
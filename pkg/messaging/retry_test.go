@@ -0,0 +1,93 @@
+package messaging
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicy_RedeliverAfter(t *testing.T) {
+	policy := newSubscribeOptions(
+		WithMaxDeliver(4),
+		WithBackoff(time.Second, time.Minute),
+	).retry
+	failure := errors.New("elasticsearch is unavailable")
+
+	tests := []struct {
+		name       string
+		deliveries int
+		err        error
+		redeliver  bool
+		delay      time.Duration
+	}{
+		{"first delivery", 1, failure, true, time.Second},
+		{"second delivery", 2, failure, true, time.Minute},
+		{"beyond the backoff", 3, failure, true, time.Minute},
+		{"last delivery", 4, failure, false, 0},
+		{"permanent error", 1, Permanent(failure), false, 0},
+		{
+			"wrapped permanent error",
+			1,
+			fmt.Errorf("decoding: %w", Permanent(failure)),
+			false,
+			0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redeliver, delay := policy.redeliverAfter(tt.deliveries, tt.err)
+			require.Equal(t, tt.redeliver, redeliver)
+			require.Equal(t, tt.delay, delay)
+		})
+	}
+}
+
+func TestRetryPolicy_Defaults(t *testing.T) {
+	policy := newSubscribeOptions().retry
+	require.Equal(t, DefaultMaxDeliver, policy.maxDeliver)
+
+	redeliver, delay := policy.redeliverAfter(1, errors.New("timeout"))
+	require.True(t, redeliver)
+	require.Equal(t, DefaultBackoff[0], delay)
+}
+
+func TestRetryPolicy_MaxBackoff(t *testing.T) {
+	policy := newSubscribeOptions().retry
+	require.Equal(t, DefaultBackoff[len(DefaultBackoff)-1], policy.maxBackoff())
+
+	require.Zero(t, retryPolicy{maxDeliver: 3}.maxBackoff())
+}
+
+func TestPermanent(t *testing.T) {
+	require.Nil(t, Permanent(nil))
+
+	err := Permanent(errors.New("invalid JSON"))
+	require.True(t, IsPermanent(err))
+	require.EqualError(t, err, "invalid JSON")
+	require.False(t, IsPermanent(errors.New("invalid JSON")))
+}
+
+func TestReject(t *testing.T) {
+	require.Nil(t, Reject(nil))
+
+	err := fmt.Errorf("node: %w", Reject(errors.New("stale version")))
+	require.True(t, IsRejected(err))
+	require.False(t, IsPermanent(err))
+	require.EqualError(t, err, "node: stale version")
+	require.False(t, IsRejected(errors.New("stale version")))
+}
+
+func TestRunHandler_RecoversPanics(t *testing.T) {
+	err := runHandler(func(_ *nats.Msg) error {
+		panic("nil map")
+	}, &nats.Msg{})
+	require.EqualError(
+		t,
+		err,
+		"panic occurred during message processing: nil map",
+	)
+}
@@ -16,3 +16,17 @@ const (
 	// has failed.
 	NodeValidationFailed = "NODES.validation_failed"
 )
+
+// DeadLetter is the subject where the messages which could not be processed
+// are kept, along with the reason, for inspection and replay. It belongs to
+// its own stream, so the dead letters aren't evicted by the NODES messages.
+const DeadLetter = "DLQ.nodes"
+
+// Constants for core NATS subjects. These are not part of the NODES stream and
+// are delivered to every subscriber rather than to a single queue member.
+
+const (
+	// SchemasUpdated is the subject for an event where the schemaparser has
+	// updated schemas in the library.
+	SchemasUpdated = "SCHEMAS.updated"
+)
@@ -1,73 +1,153 @@
 package messaging
 
 import (
+	"errors"
 	"fmt"
-	"strings"
-	"sync"
 
 	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
 
-	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/natsclient"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
 )
 
 type MessageHandler func(msg *nats.Msg)
 
-var (
-	subscriberInstance *Subscriber
-	subscriberOnce     sync.Once
-)
+// ProcessFunc processes a message of a queue subscription. The message is
+// acknowledged when it returns nil or an error marked with Reject. Otherwise it
+// is redelivered with a backoff, and dead-lettered once it has failed too many
+// times or when the error is Permanent.
+type ProcessFunc func(msg *nats.Msg) error
 
-type Subscriber struct {
-	natsClient *natsclient.NatsClient
+// QueueSubscribe sets up the consumer of the subject and processes its
+// messages in the background.
+func (b *jetStreamBus) QueueSubscribe(
+	subject, queue string,
+	handler ProcessFunc,
+	opts ...SubscribeOption,
+) error {
+	return b.queueSubscribe(
+		subject,
+		queue,
+		handler,
+		newSubscribeOptions(opts...),
+	)
 }
 
-// QueueSubscribe checks for an existing Subscriber instance or creates one,
-// and then subscribes to the specified queue.
-func QueueSubscribe(
+// queueSubscribe sets up a durable pull consumer of a NATS subject and
+// consumes it in the background. The instances of a service subscribing with
+// the same subject share the consumer, each message is processed by one of
+// them.
+func (b *jetStreamBus) queueSubscribe(
 	subject, queue string,
-	handler MessageHandler,
+	handler ProcessFunc,
+	o subscribeOptions,
 ) error {
-	var err error
-	subscriberOnce.Do(func() {
-		subscriberInstance, err = newSubscriber()
-	})
+	cfg := consumerConfig(subject, o)
+	if err := b.natsClient.EnsurePullConsumer(cfg); err != nil {
+		return fmt.Errorf("failed to set up consumer: %w", err)
+	}
+
+	sub, err := b.natsClient.PullSubscribe(subject, cfg.Durable)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to subscribe to queue: %w", err)
 	}
 
-	return subscriberInstance.queueSubscribe(subject, queue, handler)
+	b.natsClient.Go(func() {
+		b.consume(sub, subject, queue, handler, o)
+	})
+
+	return nil
 }
 
-// newSubscriber creates a new Subscriber instance.
-func newSubscriber() (*Subscriber, error) {
-	natsClient := natsclient.GetInstance()
-	if natsClient == nil {
-		return nil, fmt.Errorf("NATS client is not initialized")
+// process runs the handler on the message, then acknowledges, redelivers or
+// dead-letters the message depending on the outcome. It returns the number of
+// times the message was delivered and the outcome.
+func (b *jetStreamBus) process(
+	msg *nats.Msg,
+	queue string,
+	handler ProcessFunc,
+	policy retryPolicy,
+) (int, outcome) {
+	deliveries := 1
+	if meta, err := msg.Metadata(); err == nil {
+		deliveries = int(meta.NumDelivered)
 	}
-	return &Subscriber{natsClient: natsClient}, nil
-}
 
-// queueSubscribe sets up a queue subscription to a NATS subject with a durable consumer.
-func (s *Subscriber) queueSubscribe(
-	subject, queue string,
-	handler MessageHandler,
-) error {
-	consumerName := strings.Split(subject, ".")[1]
-
-	// Subscribe to the subject with a queue and a handler.
-	sub, err := s.natsClient.JsContext.QueueSubscribe(
-		subject, queue, func(msg *nats.Msg) {
-			handler(msg)
-		},
-		nats.Durable(consumerName),
-		nats.AckExplicit(),
-	)
-	if err != nil {
-		return fmt.Errorf("failed to subscribe to queue: %w", err)
+	fields := []zap.Field{
+		zap.String("subject", msg.Subject),
+		zap.String("queue", queue),
+		zap.Int("deliveries", deliveries),
+	}
+	if id := msg.Header.Get(nats.MsgIdHdr); id != "" {
+		fields = append(fields, zap.String("event_id", id))
+	}
+
+	var err error
+	if deliveries > policy.maxDeliver {
+		// The previous deliveries never completed, the handler likely
+		// crashed the service.
+		err = errors.New("message exceeded its maximum number of deliveries")
+	} else {
+		// The message waited for the ones fetched before it, restart its
+		// ack wait before processing it.
+		if err := msg.InProgress(); err != nil {
+			logger.Warn(
+				"Failed to extend the ack wait of message",
+				append(fields, zap.Error(err))...,
+			)
+		}
+		err = runHandler(handler, msg)
+	}
+	if err == nil || IsRejected(err) {
+		result := outcomeAcked
+		if err != nil {
+			logger.Info(
+				"Message rejected",
+				append(fields, zap.String("reason", err.Error()))...,
+			)
+			result = outcomeRejected
+		}
+		if ackErr := msg.AckSync(); ackErr != nil {
+			logger.Error("Error acknowledging message", ackErr, fields...)
+			return deliveries, outcomeUnacked
+		}
+		return deliveries, result
 	}
 
-	// Add the subscription to the client's tracking.
-	s.natsClient.AddSubscription(sub)
+	if redeliver, delay := policy.redeliverAfter(deliveries, err); redeliver {
+		logger.Warn(
+			"Failed to process message, redelivering it",
+			append(fields, zap.Duration("delay", delay), zap.Error(err))...,
+		)
+		if nakErr := msg.NakWithDelay(delay); nakErr != nil {
+			logger.Error("Error rejecting message", nakErr, fields...)
+			return deliveries, outcomeUnacked
+		}
+		return deliveries, outcomeRetried
+	}
 
-	return nil
+	if dlqErr := b.deadLetter(msg, queue, deliveries, err); dlqErr != nil {
+		// Leave the message to be redelivered rather than losing it.
+		logger.Error("Failed to dead-letter message", dlqErr, fields...)
+		if nakErr := msg.NakWithDelay(policy.maxBackoff()); nakErr != nil {
+			logger.Error("Error rejecting message", nakErr, fields...)
+			return deliveries, outcomeUnacked
+		}
+		return deliveries, outcomeRetried
+	}
+	logger.Error("Message dead-lettered", err, fields...)
+	if ackErr := msg.AckSync(); ackErr != nil {
+		logger.Error("Error acknowledging message", ackErr, fields...)
+	}
+	return deliveries, outcomeDeadLettered
+}
+
+// runHandler runs the handler, turning a panic into an error.
+func runHandler(handler ProcessFunc, msg *nats.Msg) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic occurred during message processing: %v", r)
+		}
+	}()
+	return handler(msg)
 }
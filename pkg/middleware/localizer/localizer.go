@@ -0,0 +1,102 @@
+package localizer
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/language"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/i18n"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/jsonapi"
+)
+
+// LanguageKey is the gin context key holding the negotiated language.
+const LanguageKey = "language"
+
+// NewLocalizer creates a Gin middleware that negotiates the response language
+// from the `Accept-Language` header and translates the titles and details of
+// JSON:API error responses into it.
+func NewLocalizer() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tag := i18n.Negotiate(c.GetHeader("Accept-Language"))
+		c.Set(LanguageKey, tag)
+		c.Writer.Header().Add("Vary", "Accept-Language")
+		c.Header("Content-Language", tag.String())
+
+		if tag != language.English {
+			c.Writer = &localizedWriter{ResponseWriter: c.Writer, tag: tag}
+		}
+
+		c.Next()
+	}
+}
+
+// Language returns the language negotiated for the request, English if the
+// middleware isn't installed.
+func Language(c *gin.Context) language.Tag {
+	if tag, ok := c.Get(LanguageKey); ok {
+		if tag, ok := tag.(language.Tag); ok {
+			return tag
+		}
+	}
+	return language.English
+}
+
+// localizedWriter translates error responses on the fly. Successful responses
+// are written untouched so large bodies such as exports are never buffered.
+type localizedWriter struct {
+	gin.ResponseWriter
+	tag language.Tag
+}
+
+// Write implements the http.ResponseWriter interface.
+func (w *localizedWriter) Write(data []byte) (int, error) {
+	if w.Status() < http.StatusBadRequest {
+		return w.ResponseWriter.Write(data)
+	}
+
+	localized, ok := localizeBody(w.tag, data)
+	if !ok {
+		return w.ResponseWriter.Write(data)
+	}
+	if _, err := w.ResponseWriter.Write(localized); err != nil {
+		return 0, err
+	}
+	// Report the original length, callers compare it with what they passed.
+	return len(data), nil
+}
+
+// localizeBody translates the errors of a JSON:API document. It reports false
+// when the body isn't a JSON:API document with errors.
+func localizeBody(tag language.Tag, data []byte) ([]byte, bool) {
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, false
+	}
+	rawErrors, ok := document["errors"]
+	if !ok {
+		return nil, false
+	}
+
+	var errors []jsonapi.Error
+	if err := json.Unmarshal(rawErrors, &errors); err != nil {
+		return nil, false
+	}
+	for i := range errors {
+		errors[i].Title = i18n.Translate(tag, errors[i].Title)
+		errors[i].Detail = i18n.Translate(tag, errors[i].Detail)
+	}
+
+	localizedErrors, err := json.Marshal(errors)
+	if err != nil {
+		return nil, false
+	}
+	document["errors"] = localizedErrors
+
+	localized, err := json.Marshal(document)
+	if err != nil {
+		return nil, false
+	}
+	return localized, true
+}
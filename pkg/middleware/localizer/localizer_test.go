@@ -0,0 +1,81 @@
+package localizer_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/jsonapi"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/middleware/localizer"
+)
+
+func newRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(localizer.NewLocalizer())
+	router.GET("/error", func(c *gin.Context) {
+		errors := jsonapi.NewError(
+			[]string{"Node Not Found"},
+			[]string{"Could not locate the following node_id in the Index: 123"},
+			nil,
+			[]int{http.StatusNotFound},
+		)
+		c.JSON(errors[0].Status, jsonapi.Response(nil, errors, nil, nil))
+	})
+	router.GET("/ok", func(c *gin.Context) {
+		meta := jsonapi.NewMeta("Node Not Found", "", "")
+		c.JSON(http.StatusOK, jsonapi.Response(nil, nil, nil, meta))
+	})
+	return router
+}
+
+func TestLocalizer(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		acceptLanguage string
+		wantLanguage   string
+		wantBody       string
+	}{
+		{
+			name:           "translates errors",
+			path:           "/error",
+			acceptLanguage: "fr-FR,fr;q=0.9",
+			wantLanguage:   "fr",
+			wantBody: `{"errors":[{"status":404,"title":"Nœud introuvable",` +
+				`"detail":"Impossible de trouver le node_id suivant dans l'Index : 123"}]}`,
+		},
+		{
+			name:         "defaults to english",
+			path:         "/error",
+			wantLanguage: "en",
+			wantBody: `{"errors":[{"status":404,"title":"Node Not Found",` +
+				`"detail":"Could not locate the following node_id in the Index: 123"}]}`,
+		},
+		{
+			name:           "leaves successful responses untouched",
+			path:           "/ok",
+			acceptLanguage: "de",
+			wantLanguage:   "de",
+			wantBody:       `{"meta":{"message":"Node Not Found"}}`,
+		},
+	}
+
+	router := newRouter()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Accept-Language", tt.acceptLanguage)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantLanguage, w.Header().Get("Content-Language"))
+			require.Equal(t, "Accept-Language", w.Header().Get("Vary"))
+			require.JSONEq(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
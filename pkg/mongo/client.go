@@ -2,6 +2,7 @@ package mongo
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -50,6 +51,24 @@ func (c *mongoClient) FindOneAndUpdate(
 	filter primitive.M,
 	update primitive.M,
 	opts ...*options.FindOneAndUpdateOptions,
+) (*mongo.SingleResult, error) {
+	return c.FindOneAndUpdateWithContext(
+		context.Background(),
+		collection,
+		filter,
+		update,
+		opts...,
+	)
+}
+
+// FindOneAndUpdateWithContext is FindOneAndUpdate running with the given
+// context, such as the one of a transaction.
+func (c *mongoClient) FindOneAndUpdateWithContext(
+	ctx context.Context,
+	collection string,
+	filter primitive.M,
+	update primitive.M,
+	opts ...*options.FindOneAndUpdateOptions,
 ) (*mongo.SingleResult, error) {
 	opts = append(
 		opts,
@@ -60,7 +79,7 @@ func (c *mongoClient) FindOneAndUpdate(
 	update["$inc"] = bson.M{"__v": 1}
 
 	result := c.db.Collection(collection).
-		FindOneAndUpdate(context.Background(), filter, update, opts...)
+		FindOneAndUpdate(ctx, filter, update, opts...)
 	if result.Err() != nil {
 		return nil, result.Err()
 	}
@@ -121,7 +140,8 @@ func (c *mongoClient) Disconnect() {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	err := c.client.Disconnect(ctx)
-	if err != nil {
+	// Services sharing the client in one process each disconnect it.
+	if err != nil && !errors.Is(err, mongo.ErrClientDisconnected) {
 		logger.Error("Error when trying to disconnect from MongoDB", err)
 	}
 }
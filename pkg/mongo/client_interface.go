@@ -25,6 +25,13 @@ type mongoClientInterface interface {
 		update primitive.M,
 		opts ...*options.FindOneAndUpdateOptions,
 	) (*mongo.SingleResult, error)
+	FindOneAndUpdateWithContext(
+		ctx context.Context,
+		collection string,
+		filter primitive.M,
+		update primitive.M,
+		opts ...*options.FindOneAndUpdateOptions,
+	) (*mongo.SingleResult, error)
 	Find(
 		collection string,
 		filter primitive.M,
@@ -32,6 +39,11 @@ type mongoClientInterface interface {
 	) (*mongo.Cursor, error)
 	DeleteOne(collection string, filter primitive.M) error
 	DeleteMany(collection string, filter primitive.M) error
+	WithTransaction(
+		ctx context.Context,
+		fn func(ctx context.Context) error,
+	) error
+	Collection(name string) *mongo.Collection
 
 	Ping() error
 	Disconnect()
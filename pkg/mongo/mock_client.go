@@ -1,6 +1,8 @@
 package mongo
 
 import (
+	"context"
+
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -41,6 +43,16 @@ func (c *mockClient) FindOneAndUpdate(
 	return &mongo.SingleResult{}, nil
 }
 
+func (c *mockClient) FindOneAndUpdateWithContext(
+	_ context.Context,
+	_ string,
+	_ primitive.M,
+	_ primitive.M,
+	_ ...*options.FindOneAndUpdateOptions,
+) (*mongo.SingleResult, error) {
+	return &mongo.SingleResult{}, nil
+}
+
 func (c *mockClient) Find(
 	_ string,
 	_ primitive.M,
@@ -78,3 +90,14 @@ func (c *mockClient) CreateUniqueIndex(
 ) error {
 	return nil
 }
+
+func (c *mockClient) WithTransaction(
+	ctx context.Context,
+	fn func(ctx context.Context) error,
+) error {
+	return fn(ctx)
+}
+
+func (c *mockClient) Collection(_ string) *mongo.Collection {
+	return nil
+}
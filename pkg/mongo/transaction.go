@@ -0,0 +1,61 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+)
+
+// illegalOperation is the error code returned when a transaction is started on
+// a standalone server.
+const illegalOperation = 20
+
+// noTransactions is set once the server turned out not to support
+// transactions.
+var noTransactions atomic.Bool
+
+// WithTransaction runs fn in a transaction, retrying it on transient errors.
+// The operations in fn must use the context passed to it to take part in the
+// transaction.
+//
+// Transactions need a replica set. On a standalone server, such as a
+// development cluster, fn runs without a transaction instead.
+func (c *mongoClient) WithTransaction(
+	ctx context.Context,
+	fn func(ctx context.Context) error,
+) error {
+	if noTransactions.Load() {
+		return fn(ctx)
+	}
+
+	err := c.client.UseSession(ctx, func(sc mongo.SessionContext) error {
+		_, err := sc.WithTransaction(
+			sc,
+			func(sc mongo.SessionContext) (interface{}, error) {
+				return nil, fn(sc)
+			},
+		)
+		return err
+	})
+
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Code == illegalOperation {
+		if noTransactions.CompareAndSwap(false, true) {
+			logger.Warn(
+				"MongoDB doesn't support transactions, writing without them",
+			)
+		}
+		return fn(ctx)
+	}
+	return err
+}
+
+// Collection returns the collection with the given name, for the operations
+// which are not wrapped by the client.
+func (c *mongoClient) Collection(name string) *mongo.Collection {
+	return c.db.Collection(name)
+}
@@ -1,3 +1,19 @@
 package natsclient
 
+import "time"
+
 const streamName = "NODES"
+
+// Limits of the stream keeping the dead letters. New dead letters are refused
+// once it is full, rather than evicting the oldest ones.
+const (
+	// DeadLetterStreamName is the name of the stream keeping the dead
+	// letters.
+	DeadLetterStreamName = "DLQ"
+	// deadLetterMaxMsgs is the number of dead letters kept.
+	deadLetterMaxMsgs = 100000
+	// deadLetterMaxBytes is the total size of the dead letters kept.
+	deadLetterMaxBytes = 1 << 30 // 1 GB
+	// deadLetterMaxAge is how long a dead letter is kept.
+	deadLetterMaxAge = 30 * 24 * time.Hour
+)
@@ -1,6 +1,7 @@
 package natsclient
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
@@ -16,6 +17,8 @@ type NatsClient struct {
 	conn          *nats.Conn
 	JsContext     nats.JetStreamContext
 	subscriptions []*nats.Subscription
+	// workers are the goroutines consuming the subscriptions.
+	workers sync.WaitGroup
 }
 
 var (
@@ -55,11 +58,27 @@ func IsConnected() bool {
 	return instance.conn.IsConnected()
 }
 
+// Conn returns the underlying core NATS connection.
+func (c *NatsClient) Conn() *nats.Conn {
+	return c.conn
+}
+
 // AddSubscription adds a subscription to the NatsClient for management.
 func (c *NatsClient) AddSubscription(sub *nats.Subscription) {
 	c.subscriptions = append(c.subscriptions, sub)
 }
 
+// Go runs the function consuming a subscription in a goroutine. Disconnect
+// waits for it to return, so the messages it already fetched are acknowledged
+// before the connection is closed.
+func (c *NatsClient) Go(fn func()) {
+	c.workers.Add(1)
+	go func() {
+		defer c.workers.Done()
+		fn()
+	}()
+}
+
 // Disconnect gracefully closes the NATS connection and drains subscriptions.
 func (c *NatsClient) Disconnect() error {
 	var errStrings []string
@@ -70,6 +89,7 @@ func (c *NatsClient) Disconnect() error {
 			fmt.Sprintf("error draining subscriptions: %v", err),
 		)
 	}
+	c.workers.Wait()
 
 	if c.conn != nil {
 		if err := c.conn.Drain(); err != nil {
@@ -105,31 +125,51 @@ func connectToNATS(natsURL string) (*nats.Conn, error) {
 	return conn, nil
 }
 
-// ensureStreamExists ensures the required stream exists in NATS.
+// ensureStreamExists ensures the required streams exist in NATS.
 func (c *NatsClient) ensureStreamExists() error {
-	_, err := c.JsContext.StreamInfo(streamName)
-	if err == nil {
-		return nil
+	streams := []*nats.StreamConfig{
+		{
+			Name:              streamName,
+			Subjects:          []string{"NODES.>"},
+			Retention:         nats.WorkQueuePolicy,
+			Discard:           nats.DiscardOld,
+			Storage:           nats.FileStorage,
+			MaxMsgsPerSubject: 1000,
+			MaxMsgSize:        1 << 20, // 1 MB
+			NoAck:             false,
+		},
+		{
+			// The dead letters are only removed explicitly, or once they
+			// expire. When the stream is full, dead-lettering fails and the
+			// message stays in its own stream.
+			Name:       DeadLetterStreamName,
+			Subjects:   []string{"DLQ.>"},
+			Retention:  nats.LimitsPolicy,
+			Discard:    nats.DiscardNew,
+			Storage:    nats.FileStorage,
+			MaxMsgs:    deadLetterMaxMsgs,
+			MaxBytes:   deadLetterMaxBytes,
+			MaxAge:     deadLetterMaxAge,
+			MaxMsgSize: 1 << 20, // 1 MB
+		},
 	}
-	if err != nats.ErrStreamNotFound {
-		return fmt.Errorf("error checking stream existence: %v", err)
+	for _, cfg := range streams {
+		_, err := c.JsContext.StreamInfo(cfg.Name)
+		if err == nil {
+			continue
+		}
+		if err != nats.ErrStreamNotFound {
+			return fmt.Errorf("error checking stream existence: %v", err)
+		}
+		if err := c.createStream(cfg); err != nil {
+			return err
+		}
 	}
-	return c.createStream()
+	return nil
 }
 
 // createStream creates a new stream in NATS JetStream.
-func (c *NatsClient) createStream() error {
-	streamConfig := &nats.StreamConfig{
-		Name:              streamName,
-		Subjects:          []string{"NODES.>"},
-		Retention:         nats.WorkQueuePolicy,
-		Discard:           nats.DiscardOld,
-		Storage:           nats.FileStorage,
-		MaxMsgsPerSubject: 1000,
-		MaxMsgSize:        1 << 20, // 1 MB
-		NoAck:             false,
-	}
-
+func (c *NatsClient) createStream(streamConfig *nats.StreamConfig) error {
 	info, err := c.JsContext.AddStream(streamConfig)
 	if err != nil {
 		return fmt.Errorf("error creating stream: %v", err)
@@ -140,6 +180,66 @@ func (c *NatsClient) createStream() error {
 	return nil
 }
 
+// DeadLetterStreamFull reports whether the dead letter stream reached one of
+// its limits, in which case new dead letters are refused.
+func (c *NatsClient) DeadLetterStreamFull() (bool, error) {
+	info, err := c.JsContext.StreamInfo(DeadLetterStreamName)
+	if err != nil {
+		return false, err
+	}
+	return info.State.Msgs >= uint64(info.Config.MaxMsgs) ||
+		info.State.Bytes >= uint64(info.Config.MaxBytes), nil
+}
+
+// EnsurePullConsumer creates the durable pull consumer of the stream, or
+// updates it with the given configuration when it exists.
+//
+// A push consumer of the same name is deleted first, since it can't be turned
+// into a pull consumer. The messages it had not acknowledged stay in the
+// stream and are delivered to the pull consumer.
+func (c *NatsClient) EnsurePullConsumer(cfg *nats.ConsumerConfig) error {
+	info, err := c.JsContext.ConsumerInfo(streamName, cfg.Durable)
+	switch {
+	case errors.Is(err, nats.ErrConsumerNotFound):
+	case err != nil:
+		return fmt.Errorf("error checking consumer existence: %v", err)
+	case info.Config.DeliverSubject != "":
+		err := c.JsContext.DeleteConsumer(streamName, cfg.Durable)
+		if err != nil && !errors.Is(err, nats.ErrConsumerNotFound) {
+			return fmt.Errorf("error deleting push consumer: %v", err)
+		}
+		logger.Info(fmt.Sprintf("Push consumer deleted: %s", cfg.Durable))
+	default:
+		if _, err := c.JsContext.UpdateConsumer(streamName, cfg); err != nil {
+			return fmt.Errorf("error updating consumer: %v", err)
+		}
+		return nil
+	}
+
+	if _, err := c.JsContext.AddConsumer(streamName, cfg); err != nil {
+		return fmt.Errorf("error creating consumer: %v", err)
+	}
+	logger.Info(fmt.Sprintf("Pull consumer created: %s", cfg.Durable))
+	return nil
+}
+
+// PullSubscribe binds a pull subscription to a durable consumer of the
+// stream created with EnsurePullConsumer.
+func (c *NatsClient) PullSubscribe(
+	subject, durable string,
+) (*nats.Subscription, error) {
+	sub, err := c.JsContext.PullSubscribe(
+		subject,
+		durable,
+		nats.Bind(streamName, durable),
+	)
+	if err != nil {
+		return nil, err
+	}
+	c.AddSubscription(sub)
+	return sub, nil
+}
+
 // drainSubscriptions drains all managed subscriptions.
 func (c *NatsClient) drainSubscriptions() error {
 	var errStrings []string
@@ -0,0 +1,60 @@
+// Package outbox implements the transactional outbox pattern: events are
+// written to MongoDB together with the data they describe, and a Relay
+// publishes them to NATS afterwards, retrying until it succeeds. An event is
+// therefore never lost when NATS is unavailable at the time of the write.
+package outbox
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/dateutil"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/messaging"
+)
+
+// Event is a message waiting in the outbox to be published.
+type Event struct {
+	ID primitive.ObjectID `bson:"_id,omitempty"`
+	// Subject is the NATS subject to publish the event to.
+	Subject string `bson:"subject"`
+	// Data is the JSON encoded envelope of the message.
+	Data string `bson:"data"`
+	// Attempts is the number of failed attempts to publish the event.
+	Attempts int `bson:"attempts"`
+	// LastError is the reason the last attempt failed.
+	LastError string `bson:"last_error,omitempty"`
+	// CreatedAt is the Unix timestamp when the event was enqueued.
+	CreatedAt int64 `bson:"created_at"`
+	// NextAttemptAt is the Unix timestamp from which the event can be
+	// published.
+	NextAttemptAt int64 `bson:"next_attempt_at"`
+}
+
+// NewEvent creates an event publishing the message to the subject. The
+// message is wrapped in its envelope right away, so every attempt publishes it
+// with the same event ID.
+func NewEvent(subject string, message any) (*Event, error) {
+	envelope, err := messaging.NewEnvelope(subject, message)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"error marshaling message to JSON for subject '%s': %v",
+			subject,
+			err,
+		)
+	}
+
+	now := dateutil.GetNowUnix()
+	return &Event{
+		ID:            primitive.NewObjectID(),
+		Subject:       subject,
+		Data:          string(data),
+		CreatedAt:     now,
+		NextAttemptAt: now,
+	}, nil
+}
@@ -0,0 +1,126 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+)
+
+const (
+	// pollInterval is how often the relay looks for due events.
+	pollInterval = 2 * time.Second
+	// lease is how long a claimed event is hidden from the other relays.
+	lease = time.Minute
+	// maxBackoff caps the delay between two attempts to publish an event.
+	maxBackoff = 5 * time.Minute
+)
+
+// PublishFunc publishes a message to a subject and returns once it is stored
+// by the message broker, such as messaging.PublishSync.
+type PublishFunc func(subject string, message any) error
+
+// Relay publishes the events of an outbox. Several relays can share an
+// outbox, each event is claimed by one of them at a time.
+//
+// An event is removed once published. If removing it fails, it is published
+// again later, so consumers must tolerate duplicates.
+type Relay struct {
+	store   Store
+	publish PublishFunc
+	now     func() time.Time
+}
+
+// NewRelay creates a Relay publishing the events of the store.
+func NewRelay(store Store, publish PublishFunc) *Relay {
+	return &Relay{
+		store:   store,
+		publish: publish,
+		now:     time.Now,
+	}
+}
+
+// Run publishes the due events until the context is canceled.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.Drain(ctx); err != nil && ctx.Err() == nil {
+			logger.Error("Failed to relay outbox events", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Drain publishes the events which are due and returns how many were
+// published. An event which fails to publish is rescheduled with an
+// exponential backoff and stops the drain.
+func (r *Relay) Drain(ctx context.Context) (int, error) {
+	published := 0
+	for ctx.Err() == nil {
+		event, err := r.store.Claim(ctx, r.now(), lease)
+		if err != nil {
+			return published, fmt.Errorf("failed to claim event: %w", err)
+		}
+		if event == nil {
+			return published, nil
+		}
+
+		err = r.publish(event.Subject, json.RawMessage(event.Data))
+		if err != nil {
+			// The broker is likely unavailable, try again on the next tick.
+			r.reschedule(ctx, event, err)
+			return published, nil
+		}
+
+		if err := r.store.Remove(ctx, event.ID); err != nil {
+			return published, fmt.Errorf(
+				"failed to remove published event %s: %w",
+				event.ID.Hex(),
+				err,
+			)
+		}
+		published++
+	}
+	return published, ctx.Err()
+}
+
+func (r *Relay) reschedule(ctx context.Context, event *Event, cause error) {
+	next := r.now().Add(Backoff(event.Attempts + 1))
+	logger.Warn(
+		"Failed to publish outbox event, retrying later",
+		zap.String("event_id", event.ID.Hex()),
+		zap.String("subject", event.Subject),
+		zap.Int("attempts", event.Attempts+1),
+		zap.Time("next_attempt_at", next),
+		zap.Error(cause),
+	)
+
+	// The claim expires on its own, so the event is retried even if this
+	// fails.
+	if err := r.store.Reschedule(ctx, event, cause, next); err != nil {
+		logger.Error("Failed to reschedule outbox event", err)
+	}
+}
+
+// Backoff returns the delay before the next attempt to publish an event which
+// failed the given number of times.
+func Backoff(attempts int) time.Duration {
+	if attempts <= 0 {
+		return 0
+	}
+	if attempts > 9 {
+		return maxBackoff
+	}
+	return min(time.Second<<(attempts-1), maxBackoff)
+}
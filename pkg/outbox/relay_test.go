@@ -0,0 +1,154 @@
+package outbox_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/messaging"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/outbox"
+)
+
+type fakeStore struct {
+	events []*outbox.Event
+}
+
+func (s *fakeStore) EnsureIndexes(_ context.Context) error { return nil }
+
+func (s *fakeStore) Enqueue(_ context.Context, event *outbox.Event) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *fakeStore) Claim(
+	_ context.Context,
+	now time.Time,
+	lease time.Duration,
+) (*outbox.Event, error) {
+	for _, event := range s.events {
+		if event.NextAttemptAt <= now.Unix() {
+			event.NextAttemptAt = now.Add(lease).Unix()
+			copied := *event
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *fakeStore) Remove(_ context.Context, id primitive.ObjectID) error {
+	for i, event := range s.events {
+		if event.ID == id {
+			s.events = append(s.events[:i], s.events[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (s *fakeStore) Reschedule(
+	_ context.Context,
+	event *outbox.Event,
+	cause error,
+	next time.Time,
+) error {
+	for _, stored := range s.events {
+		if stored.ID == event.ID {
+			stored.Attempts++
+			stored.LastError = cause.Error()
+			stored.NextAttemptAt = next.Unix()
+		}
+	}
+	return nil
+}
+
+type published struct {
+	subject string
+	data    string
+}
+
+func newEvents(t *testing.T, store outbox.Store, count int) {
+	for i := 0; i < count; i++ {
+		event, err := outbox.NewEvent("NODES.created", map[string]int{
+			"version": i,
+		})
+		require.NoError(t, err)
+		require.NoError(t, store.Enqueue(context.Background(), event))
+	}
+}
+
+func TestRelay_Drain(t *testing.T) {
+	store := &fakeStore{}
+	newEvents(t, store, 3)
+
+	var sent []published
+	relay := outbox.NewRelay(store, func(subject string, message any) error {
+		data, err := json.Marshal(message)
+		require.NoError(t, err)
+		envelope, err := messaging.ParseEnvelope(subject, data)
+		require.NoError(t, err)
+		require.NotEmpty(t, envelope.ID)
+		sent = append(sent, published{subject, string(envelope.Data)})
+		return nil
+	})
+
+	count, err := relay.Drain(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+	require.Empty(t, store.events)
+	require.Equal(t, []published{
+		{"NODES.created", `{"version":0}`},
+		{"NODES.created", `{"version":1}`},
+		{"NODES.created", `{"version":2}`},
+	}, sent)
+}
+
+func TestRelay_DrainRetriesFailures(t *testing.T) {
+	store := &fakeStore{}
+	newEvents(t, store, 2)
+
+	fail := true
+	relay := outbox.NewRelay(store, func(_ string, _ any) error {
+		if fail {
+			return errors.New("nats: no responders available for request")
+		}
+		return nil
+	})
+
+	count, err := relay.Drain(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, count)
+	require.Len(t, store.events, 2)
+	require.Equal(t, 1, store.events[0].Attempts)
+	require.Equal(
+		t,
+		"nats: no responders available for request",
+		store.events[0].LastError,
+	)
+	require.GreaterOrEqual(t, store.events[0].NextAttemptAt, time.Now().Unix())
+	// The drain stops at the first failure.
+	require.Zero(t, store.events[1].Attempts)
+
+	// Make the events due again once the broker is back.
+	fail = false
+	for _, event := range store.events {
+		event.NextAttemptAt = 0
+	}
+	count, err = relay.Drain(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	require.Empty(t, store.events)
+}
+
+func TestBackoff(t *testing.T) {
+	require.Equal(t, time.Duration(0), outbox.Backoff(0))
+	require.Equal(t, time.Second, outbox.Backoff(1))
+	require.Equal(t, 2*time.Second, outbox.Backoff(2))
+	require.Equal(t, 256*time.Second, outbox.Backoff(9))
+	require.Equal(t, 5*time.Minute, outbox.Backoff(10))
+	require.Equal(t, 5*time.Minute, outbox.Backoff(100))
+}
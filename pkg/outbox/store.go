@@ -0,0 +1,116 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	mongodb "github.com/MurmurationsNetwork/MurmurationsServices/pkg/mongo"
+)
+
+// Store keeps the events of the outbox.
+type Store interface {
+	// EnsureIndexes creates the index the due events are claimed with.
+	EnsureIndexes(ctx context.Context) error
+	// Enqueue adds the event to the outbox. Given the context of a
+	// transaction, the event is only added if the transaction commits.
+	Enqueue(ctx context.Context, event *Event) error
+	// Claim locks the next due event for the lease duration, so other relays
+	// skip it, and returns it. It returns nil when no event is due.
+	Claim(
+		ctx context.Context,
+		now time.Time,
+		lease time.Duration,
+	) (*Event, error)
+	// Remove deletes a published event.
+	Remove(ctx context.Context, id primitive.ObjectID) error
+	// Reschedule records a failed attempt to publish the event and when to
+	// try again.
+	Reschedule(
+		ctx context.Context,
+		event *Event,
+		cause error,
+		next time.Time,
+	) error
+}
+
+type store struct {
+	collection string
+}
+
+// NewStore returns a Store keeping the events in the given MongoDB collection.
+func NewStore(collection string) Store {
+	return &store{collection: collection}
+}
+
+func (s *store) coll() *mongo.Collection {
+	return mongodb.Client.Collection(s.collection)
+}
+
+// EnsureIndexes creates the index the due events are claimed with.
+func (s *store) EnsureIndexes(ctx context.Context) error {
+	_, err := s.coll().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "next_attempt_at", Value: 1}},
+	})
+	return err
+}
+
+// Enqueue adds the event to the outbox.
+func (s *store) Enqueue(ctx context.Context, event *Event) error {
+	_, err := s.coll().InsertOne(ctx, event)
+	return err
+}
+
+// Claim locks the next due event for the lease duration and returns it.
+func (s *store) Claim(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+) (*Event, error) {
+	filter := bson.M{"next_attempt_at": bson.M{"$lte": now.Unix()}}
+	update := bson.M{
+		"$set": bson.M{"next_attempt_at": now.Add(lease).Unix()},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var event Event
+	err := s.coll().FindOneAndUpdate(ctx, filter, update, opts).Decode(&event)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// Remove deletes a published event.
+func (s *store) Remove(ctx context.Context, id primitive.ObjectID) error {
+	_, err := s.coll().DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+// Reschedule records a failed attempt to publish the event.
+func (s *store) Reschedule(
+	ctx context.Context,
+	event *Event,
+	cause error,
+	next time.Time,
+) error {
+	update := bson.M{
+		"$set": bson.M{
+			"last_error":      cause.Error(),
+			"next_attempt_at": next.Unix(),
+		},
+		"$inc": bson.M{"attempts": 1},
+	}
+	_, err := s.coll().UpdateOne(ctx, bson.M{"_id": event.ID}, update)
+	return err
+}
@@ -6,6 +6,9 @@ import "fmt"
 type Builder struct {
 	// profilevalidator is the ProfileValidator instance being constructed.
 	profilevalidator *ProfileValidator
+	// schemaCache, when set, wraps the schema loader so compiled schemas are
+	// reused across validators.
+	schemaCache *SchemaCache
 }
 
 // NewBuilder creates and returns a new Builder.
@@ -49,6 +52,26 @@ func (b *Builder) WithJSONSchemas(
 	return b
 }
 
+// WithSchemaCache configures the ProfileValidator to look up compiled schemas
+// in the given cache before loading them.
+func (b *Builder) WithSchemaCache(cache *SchemaCache) *Builder {
+	b.schemaCache = cache
+	return b
+}
+
+// WithNewerVersionNotices makes the ProfileValidator look up the versions of
+// the schemas in the library at the base URL, adding a notice to the result
+// for each schema with a newer minor version. The versions are kept in the
+// cache unless it is nil.
+func (b *Builder) WithNewerVersionNotices(
+	baseURL string,
+	cache *SchemaCache,
+) *Builder {
+	b.profilevalidator.VersionsURL = baseURL
+	b.profilevalidator.VersionsCache = cache
+	return b
+}
+
 // WithStrProfile sets the data string to be validated.
 func (b *Builder) WithStrProfile(dataString string) *Builder {
 	b.profilevalidator.ProfileLoader = &StrProfileLoader{dataString: dataString}
@@ -76,6 +99,13 @@ func (b *Builder) Build() (*ProfileValidator, error) {
 		return nil, fmt.Errorf("a data loader must be provided")
 	}
 
+	if b.schemaCache != nil {
+		b.profilevalidator.SchemaLoader = &CachedSchemaLoader{
+			Loader: b.profilevalidator.SchemaLoader,
+			Cache:  b.schemaCache,
+		}
+	}
+
 	profileData, err := b.profilevalidator.ProfileLoader.Load().LoadJSON()
 	if err != nil {
 		return nil, fmt.Errorf(
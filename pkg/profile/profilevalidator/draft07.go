@@ -0,0 +1,177 @@
+package profilevalidator
+
+import (
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// draft07Schema validates profiles with gojsonschema, which supports JSON
+// Schema up to draft-07.
+type draft07Schema struct {
+	schema    *gojsonschema.Schema
+	lifecycle Lifecycle
+}
+
+// newDraft07Schema compiles the schema located at location with
+// gojsonschema.
+func newDraft07Schema(location string, data []byte) (*draft07Schema, error) {
+	// The schema is added to the pool under its location before being
+	// compiled by reference, so relative `$ref`s resolve against it instead
+	// of the working directory.
+	loader := gojsonschema.NewSchemaLoader()
+	err := loader.AddSchema(location, gojsonschema.NewBytesLoader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := loader.Compile(gojsonschema.NewReferenceLoader(location))
+	if err != nil {
+		return nil, err
+	}
+	return &draft07Schema{schema: schema}, nil
+}
+
+// Lifecycle implements the Schema interface.
+func (s *draft07Schema) Lifecycle() Lifecycle {
+	return s.lifecycle
+}
+
+// Validate implements the Schema interface.
+func (s *draft07Schema) Validate(
+	profile ProfileLoader,
+	schemaName string,
+) (*ValidationResult, error) {
+	validationResult, err := s.schema.Validate(profile.Load())
+	if err != nil {
+		return nil, err
+	}
+
+	result := NewValidationResult()
+	if validationResult.Valid() {
+		return result, nil
+	}
+
+	titles, details, sources := parseValidateError(
+		schemaName,
+		validationResult.Errors(),
+	)
+
+	// Assign the same status code for each validation error.
+	statusCodes := make([]int, len(titles))
+	for i := range statusCodes {
+		statusCodes[i] = http.StatusBadRequest
+	}
+
+	result.AppendErrors(titles, details, sources, statusCodes)
+	return result, nil
+}
+
+func parseValidateError(
+	schemaName string,
+	resultErrors []gojsonschema.ResultError,
+) ([]string, []string, [][]string) {
+	failedTitles := make([]string, 0, len(resultErrors))
+	failedDetails := make([]string, 0, len(resultErrors))
+	failedSources := make([][]string, 0, len(resultErrors))
+
+	for _, desc := range resultErrors {
+		// title
+		failedType := desc.Type()
+
+		// details
+		var expected, given, minValue, maxValue, property, pattern, failedDetail, failedField string
+		for index, value := range desc.Details() {
+			switch index {
+			case "expected":
+				expected = value.(string)
+			case "given":
+				given = value.(string)
+			case "min":
+				if bigFloat, ok := value.(*big.Float); ok {
+					minFloat64, _ := bigFloat.Float64()
+					minValue = fmt.Sprintf("%.0f", minFloat64)
+				} else {
+					minValue = fmt.Sprintf("%v", value)
+				}
+			case "max":
+				if bigFloat, ok := value.(*big.Float); ok {
+					maxFloat64, _ := bigFloat.Float64()
+					maxValue = fmt.Sprintf("%.0f", maxFloat64)
+				} else {
+					maxValue = fmt.Sprintf("%v", value)
+				}
+			case "property":
+				property = value.(string)
+			case "pattern":
+				pattern = fmt.Sprint(value)
+			}
+		}
+
+		switch failedType {
+		case "invalid_type":
+			failedType = "Invalid Type"
+			failedDetail = "Expected: " + expected + " - Given: " + given + " - Schema: " + schemaName
+		case "number_gte":
+			failedType = "Invalid Amount"
+			failedDetail = "Amount must be greater than or equal to " + minValue + " - Schema: " + schemaName
+		case "number_lte":
+			failedType = "Invalid Amount"
+			failedDetail = "Amount must be less than or equal to " + maxValue + " - Schema: " + schemaName
+		case "required":
+			failedType = "Missing Required Property"
+			// The path uses slashes like the draft 2019-09/2020-12 backend,
+			// gojsonschema separates the nested fields with dots.
+			if desc.Field() == "(root)" {
+				failedDetail = "The `" + property + "` property is required - Schema: " + schemaName
+			} else {
+				failedDetail = "The `" + strings.ReplaceAll(desc.Field(), ".", "/") + "/" + property + "` property is required - Schema: " + schemaName
+			}
+		case "array_min_items":
+			failedType = "Not Enough Items"
+			failedDetail = "There are not enough items in the array - Minimum is " + minValue + " - Schema: " + schemaName
+		case "array_max_items":
+			failedType = "Too Many Items"
+			failedDetail = "There are too many items in the array - Maximum is " + maxValue + " - Schema: " + schemaName
+		case "pattern":
+			failedType = "Pattern Mismatch"
+			failedDetail = "The submitted data does not match the required pattern: '" + pattern + "' - Schema: " + schemaName
+		case "enum":
+			failedType = "Invalid Value"
+			failedDetail = "The submitted data is not a valid value from the list of allowed values - Schema: " + schemaName
+		case "unique":
+			failedType = "Duplicate Value"
+			failedDetail = "The submitted data contains a duplicate value - Schema: " + schemaName
+		case "string_lte":
+			failedType = "Invalid Length"
+			failedDetail = "Amount must be less than or equal to " + maxValue + " - Schema: " + schemaName
+		case "string_gte":
+			failedType = "Invalid Length"
+			failedDetail = "Amount must be greater than or equal to " + minValue + " - Schema: " + schemaName
+		// condition_else and condition_then are not errors, they are conditions - no need to report them
+		case "condition_else":
+			continue
+		case "condition_then":
+			continue
+		}
+
+		// append title and detail
+		failedTitles = append(failedTitles, failedType)
+		failedDetails = append(failedDetails, failedDetail)
+
+		// sources
+		if desc.Field() == "(root)" && property != "" {
+			failedField = "/" + property
+		} else if property != "" {
+			failedField = "/" + strings.ReplaceAll(desc.Field(), ".", "/") + "/" + property
+		} else {
+			failedField = "/" + strings.ReplaceAll(desc.Field(), ".", "/")
+		}
+		failedSources = append(failedSources, []string{"pointer", failedField})
+	}
+
+	return failedTitles, failedDetails, failedSources
+}
@@ -0,0 +1,267 @@
+package profilevalidator
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// draft2020Schema validates profiles with santhosh-tekuri/jsonschema, which
+// supports JSON Schema drafts 2019-09 and 2020-12 (`$defs`,
+// `unevaluatedProperties`, `dependentRequired`, `prefixItems`, ...).
+type draft2020Schema struct {
+	schema    *jsonschema.Schema
+	lifecycle Lifecycle
+}
+
+// newDraft2020Schema compiles the schema located at location.
+func newDraft2020Schema(
+	location string,
+	data []byte,
+) (*draft2020Schema, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.UseLoader(jsonschema.SchemeURLLoader{
+		"http":  httpURLLoader{},
+		"https": httpURLLoader{},
+	})
+	if err := compiler.AddResource(location, doc); err != nil {
+		return nil, err
+	}
+
+	schema, err := compiler.Compile(location)
+	if err != nil {
+		return nil, err
+	}
+	return &draft2020Schema{schema: schema}, nil
+}
+
+// Lifecycle implements the Schema interface.
+func (s *draft2020Schema) Lifecycle() Lifecycle {
+	return s.lifecycle
+}
+
+// Validate implements the Schema interface.
+func (s *draft2020Schema) Validate(
+	profile ProfileLoader,
+	schemaName string,
+) (*ValidationResult, error) {
+	instance, err := profile.Load().LoadJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	result := NewValidationResult()
+
+	err = s.schema.Validate(instance)
+	if err == nil {
+		return result, nil
+	}
+	validationErr, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return nil, err
+	}
+
+	for _, leaf := range leafErrors(validationErr) {
+		appendDraft2020Error(result, schemaName, leaf)
+	}
+	return result, nil
+}
+
+// leafErrors flattens the error tree, only the leaves describe actual
+// failures; the inner nodes just group them.
+func leafErrors(
+	validationErr *jsonschema.ValidationError,
+) []*jsonschema.ValidationError {
+	if len(validationErr.Causes) == 0 {
+		return []*jsonschema.ValidationError{validationErr}
+	}
+	var leaves []*jsonschema.ValidationError
+	for _, cause := range validationErr.Causes {
+		leaves = append(leaves, leafErrors(cause)...)
+	}
+	return leaves
+}
+
+// appendDraft2020Error maps a single validation error into the result using
+// the same titles and details as the draft-07 backend.
+func appendDraft2020Error(
+	result *ValidationResult,
+	schemaName string,
+	validationErr *jsonschema.ValidationError,
+) {
+	field := "/" + strings.Join(validationErr.InstanceLocation, "/")
+	if field == "/" {
+		field = ""
+	}
+	suffix := " - Schema: " + schemaName
+
+	appendError := func(title, detail, pointer string) {
+		if pointer == "" {
+			pointer = "/"
+		}
+		result.AppendError(
+			title,
+			detail+suffix,
+			[]string{"pointer", pointer},
+			http.StatusBadRequest,
+		)
+	}
+
+	switch k := validationErr.ErrorKind.(type) {
+	case *kind.Type:
+		appendError(
+			"Invalid Type",
+			"Expected: "+strings.Join(k.Want, " or ")+" - Given: "+k.Got,
+			field,
+		)
+	case *kind.Minimum:
+		appendError(
+			"Invalid Amount",
+			"Amount must be greater than or equal to "+ratString(k.Want),
+			field,
+		)
+	case *kind.Maximum:
+		appendError(
+			"Invalid Amount",
+			"Amount must be less than or equal to "+ratString(k.Want),
+			field,
+		)
+	case *kind.ExclusiveMinimum:
+		appendError(
+			"Invalid Amount",
+			"Amount must be greater than "+ratString(k.Want),
+			field,
+		)
+	case *kind.ExclusiveMaximum:
+		appendError(
+			"Invalid Amount",
+			"Amount must be less than "+ratString(k.Want),
+			field,
+		)
+	case *kind.Required:
+		for _, property := range k.Missing {
+			appendError(
+				"Missing Required Property",
+				"The `"+strings.TrimPrefix(field+"/"+property, "/")+
+					"` property is required",
+				field+"/"+property,
+			)
+		}
+	case *kind.DependentRequired:
+		for _, property := range k.Missing {
+			appendError(
+				"Missing Required Property",
+				"The `"+strings.TrimPrefix(field+"/"+property, "/")+
+					"` property is required when `"+k.Prop+"` is present",
+				field+"/"+property,
+			)
+		}
+	case *kind.MinItems:
+		appendError(
+			"Not Enough Items",
+			fmt.Sprintf(
+				"There are not enough items in the array - Minimum is %d",
+				k.Want,
+			),
+			field,
+		)
+	case *kind.MaxItems:
+		appendError(
+			"Too Many Items",
+			fmt.Sprintf(
+				"There are too many items in the array - Maximum is %d",
+				k.Want,
+			),
+			field,
+		)
+	case *kind.Pattern:
+		appendError(
+			"Pattern Mismatch",
+			"The submitted data does not match the required pattern: '"+
+				k.Want+"'",
+			field,
+		)
+	case *kind.Enum, *kind.Const:
+		appendError(
+			"Invalid Value",
+			"The submitted data is not a valid value from the list of allowed values",
+			field,
+		)
+	case *kind.UniqueItems:
+		appendError(
+			"Duplicate Value",
+			"The submitted data contains a duplicate value",
+			field,
+		)
+	case *kind.MinLength:
+		appendError(
+			"Invalid Length",
+			fmt.Sprintf(
+				"Amount must be greater than or equal to %d",
+				k.Want,
+			),
+			field,
+		)
+	case *kind.MaxLength:
+		appendError(
+			"Invalid Length",
+			fmt.Sprintf("Amount must be less than or equal to %d", k.Want),
+			field,
+		)
+	case *kind.AdditionalProperties:
+		for _, property := range k.Properties {
+			appendError(
+				"Unexpected Property",
+				"The `"+strings.TrimPrefix(field+"/"+property, "/")+
+					"` property is not allowed",
+				field+"/"+property,
+			)
+		}
+	case *kind.FalseSchema:
+		// unevaluatedProperties/unevaluatedItems report the rejected value
+		// itself as failing a false schema.
+		appendError(
+			"Unexpected Property",
+			"The `"+strings.TrimPrefix(field, "/")+"` property is not allowed",
+			field,
+		)
+	default:
+		appendError(
+			"Invalid Value",
+			validationErr.ErrorKind.LocalizedString(
+				message.NewPrinter(language.English),
+			),
+			field,
+		)
+	}
+}
+
+// ratString formats a rational number without trailing zeros.
+func ratString(r *big.Rat) string {
+	f, _ := r.Float64()
+	return fmt.Sprintf("%v", f)
+}
+
+// httpURLLoader lets the 2019-09/2020-12 backend resolve remote `$ref`s.
+type httpURLLoader struct{}
+
+// Load implements the jsonschema.URLLoader interface.
+func (httpURLLoader) Load(url string) (any, error) {
+	data, err := fetchSchema(url)
+	if err != nil {
+		return nil, err
+	}
+	return jsonschema.UnmarshalJSON(bytes.NewReader(data))
+}
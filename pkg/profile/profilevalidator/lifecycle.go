@@ -0,0 +1,87 @@
+package profilevalidator
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Lifecycle is the deprecation metadata of a schema, read from the
+// `metadata.schema` object of the schema.
+type Lifecycle struct {
+	// Deprecated schemas can still be linked but the profiles using them are
+	// warned.
+	Deprecated bool `json:"deprecated"`
+	// SunsetAt is the Unix timestamp after which profiles can't be validated
+	// against the schema anymore. Zero means no sunset.
+	SunsetAt int64 `json:"sunset_at"`
+	// ReplacedBy is the name of the schema to use instead.
+	ReplacedBy string `json:"replaced_by"`
+}
+
+// parseLifecycle reads the lifecycle from the raw schema. The lifecycle is
+// left empty when the metadata can't be read, the schema stays usable.
+func parseLifecycle(data []byte) Lifecycle {
+	var schema struct {
+		Metadata struct {
+			Schema Lifecycle `json:"schema"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return Lifecycle{}
+	}
+	return schema.Metadata.Schema
+}
+
+// IsSunset reports whether the sunset of the schema has passed.
+func (l Lifecycle) IsSunset(now time.Time) bool {
+	return l.SunsetAt != 0 && now.Unix() >= l.SunsetAt
+}
+
+// sunsetDetail explains why the schema can't be used anymore.
+func (l Lifecycle) sunsetDetail(schemaName string) string {
+	return fmt.Sprintf(
+		"The `%s` schema was sunset on %s, profiles can't be posted with it "+
+			"anymore.%s",
+		schemaName,
+		formatSunset(l.SunsetAt),
+		l.replacement(),
+	)
+}
+
+// notice warns about a deprecated schema. It is empty when the schema isn't
+// deprecated.
+func (l Lifecycle) notice(schemaName string, now time.Time) string {
+	if !l.Deprecated && l.SunsetAt == 0 {
+		return ""
+	}
+	if l.IsSunset(now) {
+		return fmt.Sprintf(
+			"The `%s` schema was sunset on %s, only the profiles already "+
+				"posted with it are accepted.%s",
+			schemaName,
+			formatSunset(l.SunsetAt),
+			l.replacement(),
+		)
+	}
+
+	notice := fmt.Sprintf("The `%s` schema is deprecated.", schemaName)
+	if l.SunsetAt != 0 {
+		notice += fmt.Sprintf(
+			" Profiles can't be posted with it after %s.",
+			formatSunset(l.SunsetAt),
+		)
+	}
+	return notice + l.replacement()
+}
+
+func (l Lifecycle) replacement() string {
+	if l.ReplacedBy == "" {
+		return ""
+	}
+	return fmt.Sprintf(" Please use `%s` instead.", l.ReplacedBy)
+}
+
+func formatSunset(sunsetAt int64) string {
+	return time.Unix(sunsetAt, 0).UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,94 @@
+package profilevalidator_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/profile/profilevalidator"
+)
+
+func TestValidate_Lifecycle(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata string
+		valid    bool
+		details  []string
+		notices  []string
+		sunset   []string
+	}{
+		{
+			name:     "active",
+			metadata: `{"name": "test_schema-v1.0.0"}`,
+			valid:    true,
+			details:  []string{},
+		},
+		{
+			name: "deprecated",
+			metadata: `{"name": "test_schema-v1.0.0", "deprecated": true,
+				"replaced_by": "test_schema-v2.0.0"}`,
+			valid:   true,
+			details: []string{},
+			notices: []string{
+				"The `test_schema-v1.0.0` schema is deprecated. Please use " +
+					"`test_schema-v2.0.0` instead.",
+			},
+		},
+		{
+			name: "sunset scheduled",
+			metadata: `{"name": "test_schema-v1.0.0", "deprecated": true,
+				"sunset_at": 4102444800}`,
+			valid:   true,
+			details: []string{},
+			notices: []string{
+				"The `test_schema-v1.0.0` schema is deprecated. Profiles " +
+					"can't be posted with it after 2100-01-01T00:00:00Z.",
+			},
+		},
+		{
+			// The profiles already posted are still accepted.
+			name: "sunset passed",
+			metadata: `{"name": "test_schema-v1.0.0", "deprecated": true,
+				"sunset_at": 1700000000, "replaced_by": "test_schema-v2.0.0"}`,
+			valid:   true,
+			details: []string{},
+			notices: []string{
+				"The `test_schema-v1.0.0` schema was sunset on " +
+					"2023-11-14T22:13:20Z, only the profiles already posted " +
+					"with it are accepted. Please use `test_schema-v2.0.0` " +
+					"instead.",
+			},
+			sunset: []string{
+				"The `test_schema-v1.0.0` schema was sunset on " +
+					"2023-11-14T22:13:20Z, profiles can't be posted with it " +
+					"anymore. Please use `test_schema-v2.0.0` instead.",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := `{"type": "object", "metadata": {"schema": ` +
+				tt.metadata + `}}`
+			validator, err := profilevalidator.NewBuilder().
+				WithJSONSchemas(
+					[]string{"test_schema-v1.0.0"},
+					[]string{schema},
+				).
+				WithStrProfile(`{"name": "a"}`).
+				Build()
+			require.NoError(t, err)
+
+			result := validator.Validate()
+			require.Equal(t, tt.valid, result.Valid)
+			require.Equal(t, tt.details, result.Details)
+			require.Equal(t, tt.notices, result.Notices)
+			require.Equal(t, tt.sunset, result.Sunset)
+
+			// New profiles can't be posted after the sunset.
+			result.RejectSunset()
+			require.Equal(t, tt.valid && tt.sunset == nil, result.Valid)
+			require.Equal(t, append(tt.details, tt.sunset...), result.Details)
+		})
+	}
+}
@@ -2,17 +2,24 @@ package profilevalidator
 
 import (
 	"fmt"
-	"math/big"
 	"net/http"
-	"strings"
+	"time"
 
 	"github.com/xeipuuv/gojsonschema"
 )
 
 // Loader is the interface that wraps the Load method.
 type Loader interface {
-	// Load fetches the JSON schema from a source and returns it.
-	Load(string) (*gojsonschema.Schema, error)
+	// Load fetches the JSON schema from a source and returns it compiled.
+	Load(string) (Schema, error)
+}
+
+// NamedLoader is implemented by the loaders that also need the name of the
+// schema, such as CachedSchemaLoader which caches the schemas by name.
+type NamedLoader interface {
+	// LoadNamed fetches the JSON schema from a source and returns it
+	// compiled.
+	LoadNamed(name string, source string) (Schema, error)
 }
 
 // URLSchemaLoader is a schema loader that loads schema from a URL.
@@ -24,9 +31,13 @@ type URLSchemaLoader struct {
 // Load implements the Loader interface.
 func (ul *URLSchemaLoader) Load(
 	linkedSchema string,
-) (*gojsonschema.Schema, error) {
+) (Schema, error) {
 	schemaURL := getSchemaURL(ul.BaseURL, linkedSchema)
-	return gojsonschema.NewSchema(gojsonschema.NewReferenceLoader(schemaURL))
+	data, err := fetchSchema(schemaURL)
+	if err != nil {
+		return nil, err
+	}
+	return compileSchema(schemaURL, data)
 }
 
 // StrSchemaLoader is a schema loader that loads schema from a string.
@@ -35,8 +46,8 @@ type StrSchemaLoader struct{}
 // Load implements the Loader interface.
 func (sl *StrSchemaLoader) Load(
 	source string,
-) (*gojsonschema.Schema, error) {
-	return gojsonschema.NewSchema(gojsonschema.NewStringLoader(source))
+) (Schema, error) {
+	return compileSchema(inlineSchemaURL, []byte(source))
 }
 
 // ProfileLoader is the interface that wraps the Load method.
@@ -86,12 +97,15 @@ type ProfileValidator struct {
 	SchemaReferences []string               // For URL-based schemas (used to fetch schema content).
 	LoadedSchemas    []string               // For JSON-based schemas (actual content).
 	SchemaLoader     Loader                 // Loader for fetching schema content.
+	VersionsURL      string                 // Library URL to look up the newer versions of the schemas, if set.
+	VersionsCache    *SchemaCache           // Cache of the versions of the schemas, if set.
 }
 
 // Validate performs validation of the profile JSON against the provided schemas
 // and returns the aggregated validation result.
 func (v *ProfileValidator) Validate() *ValidationResult {
 	finalResult := NewValidationResult()
+	now := time.Now()
 
 	// Use either loaded JSON schemas or schema references (both are set during initialization).
 	schemasToValidate := v.LoadedSchemas
@@ -102,7 +116,7 @@ func (v *ProfileValidator) Validate() *ValidationResult {
 	// Iterate over each schema for validation.
 	for i, schema := range schemasToValidate {
 		// Load the schema using the SchemaLoader.
-		loadedSchema, err := v.SchemaLoader.Load(schema)
+		loadedSchema, err := v.loadSchema(v.SchemaNames[i], schema)
 		if err != nil {
 			finalResult.AppendError(
 				"Error loading schema",
@@ -117,8 +131,24 @@ func (v *ProfileValidator) Validate() *ValidationResult {
 			continue
 		}
 
+		// Whether a schema after its sunset is accepted depends on the
+		// profile being posted already, which is up to the caller.
+		lifecycle := loadedSchema.Lifecycle()
+		if lifecycle.IsSunset(now) {
+			finalResult.Sunset = append(
+				finalResult.Sunset,
+				lifecycle.sunsetDetail(v.SchemaNames[i]),
+			)
+		}
+		if notice := lifecycle.notice(v.SchemaNames[i], now); notice != "" {
+			finalResult.Notices = append(finalResult.Notices, notice)
+		}
+
 		// Validate the profile JSON against the loaded schema.
-		validationResult, err := loadedSchema.Validate(v.ProfileLoader.Load())
+		validationResult, err := loadedSchema.Validate(
+			v.ProfileLoader,
+			v.SchemaNames[i],
+		)
 		if err != nil {
 			finalResult.AppendError(
 				"Cannot Validate Document",
@@ -129,132 +159,34 @@ func (v *ProfileValidator) Validate() *ValidationResult {
 			continue
 		}
 
-		// If validation fails, collect and append the errors.
-		if !validationResult.Valid() {
-			titles, details, sources := parseValidateError(
-				v.SchemaNames[i],
-				validationResult.Errors(),
-			)
-
-			// Assign the same status code for each validation error.
-			statusCodes := make([]int, len(titles))
-			for i := range statusCodes {
-				statusCodes[i] = http.StatusBadRequest
-			}
+		// Append all validation errors to the final result.
+		finalResult.Merge(validationResult)
+	}
 
-			// Append all validation errors to the final result.
-			finalResult.AppendErrors(titles, details, sources, statusCodes)
-		}
+	if v.VersionsURL != "" {
+		finalResult.Notices = append(
+			finalResult.Notices,
+			newerVersionNotices(
+				v.VersionsURL,
+				v.VersionsCache,
+				v.SchemaNames,
+			)...,
+		)
 	}
 
 	return finalResult
 }
 
+// loadSchema loads the schema with the SchemaLoader, giving it the name of
+// the schema when it uses one.
+func (v *ProfileValidator) loadSchema(name, source string) (Schema, error) {
+	if loader, ok := v.SchemaLoader.(NamedLoader); ok {
+		return loader.LoadNamed(name, source)
+	}
+	return v.SchemaLoader.Load(source)
+}
+
 // getSchemaURL constructs the full schema URL and returns it.
 func getSchemaURL(libraryURL string, linkedSchema string) string {
 	return fmt.Sprintf("%s/v2/schemas/%s", libraryURL, linkedSchema)
 }
-
-func parseValidateError(
-	schemaName string,
-	resultErrors []gojsonschema.ResultError,
-) ([]string, []string, [][]string) {
-	failedTitles := make([]string, 0, len(resultErrors))
-	failedDetails := make([]string, 0, len(resultErrors))
-	failedSources := make([][]string, 0, len(resultErrors))
-
-	for _, desc := range resultErrors {
-		// title
-		failedType := desc.Type()
-
-		// details
-		var expected, given, minValue, maxValue, property, pattern, failedDetail, failedField string
-		for index, value := range desc.Details() {
-			switch index {
-			case "expected":
-				expected = value.(string)
-			case "given":
-				given = value.(string)
-			case "min":
-				if bigFloat, ok := value.(*big.Float); ok {
-					minFloat64, _ := bigFloat.Float64()
-					minValue = fmt.Sprintf("%.0f", minFloat64)
-				} else {
-					minValue = fmt.Sprintf("%v", value)
-				}
-			case "max":
-				if bigFloat, ok := value.(*big.Float); ok {
-					maxFloat64, _ := bigFloat.Float64()
-					maxValue = fmt.Sprintf("%.0f", maxFloat64)
-				} else {
-					maxValue = fmt.Sprintf("%v", value)
-				}
-			case "property":
-				property = value.(string)
-			case "pattern":
-				pattern = fmt.Sprint(value)
-			}
-		}
-
-		switch failedType {
-		case "invalid_type":
-			failedType = "Invalid Type"
-			failedDetail = "Expected: " + expected + " - Given: " + given + " - Schema: " + schemaName
-		case "number_gte":
-			failedType = "Invalid Amount"
-			failedDetail = "Amount must be greater than or equal to " + minValue + " - Schema: " + schemaName
-		case "number_lte":
-			failedType = "Invalid Amount"
-			failedDetail = "Amount must be less than or equal to " + maxValue + " - Schema: " + schemaName
-		case "required":
-			failedType = "Missing Required Property"
-			if desc.Field() == "(root)" {
-				failedDetail = "The `" + property + "` property is required - Schema: " + schemaName
-			} else {
-				failedDetail = "The `" + desc.Field() + "/" + property + "` property is required - Schema: " + schemaName
-			}
-		case "array_min_items":
-			failedType = "Not Enough Items"
-			failedDetail = "There are not enough items in the array - Minimum is " + minValue + " - Schema: " + schemaName
-		case "array_max_items":
-			failedType = "Too Many Items"
-			failedDetail = "There are too many items in the array - Maximum is " + maxValue + " - Schema: " + schemaName
-		case "pattern":
-			failedType = "Pattern Mismatch"
-			failedDetail = "The submitted data does not match the required pattern: '" + pattern + "' - Schema: " + schemaName
-		case "enum":
-			failedType = "Invalid Value"
-			failedDetail = "The submitted data is not a valid value from the list of allowed values - Schema: " + schemaName
-		case "unique":
-			failedType = "Duplicate Value"
-			failedDetail = "The submitted data contains a duplicate value - Schema: " + schemaName
-		case "string_lte":
-			failedType = "Invalid Length"
-			failedDetail = "Amount must be less than or equal to " + maxValue + " - Schema: " + schemaName
-		case "string_gte":
-			failedType = "Invalid Length"
-			failedDetail = "Amount must be greater than or equal to " + minValue + " - Schema: " + schemaName
-		// condition_else and condition_then are not errors, they are conditions - no need to report them
-		case "condition_else":
-			continue
-		case "condition_then":
-			continue
-		}
-
-		// append title and detail
-		failedTitles = append(failedTitles, failedType)
-		failedDetails = append(failedDetails, failedDetail)
-
-		// sources
-		if desc.Field() == "(root)" && property != "" {
-			failedField = "/" + property
-		} else if property != "" {
-			failedField = "/" + strings.ReplaceAll(desc.Field(), ".", "/") + "/" + property
-		} else {
-			failedField = "/" + strings.ReplaceAll(desc.Field(), ".", "/")
-		}
-		failedSources = append(failedSources, []string{"pointer", failedField})
-	}
-
-	return failedTitles, failedDetails, failedSources
-}
@@ -0,0 +1,89 @@
+package profilevalidator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/httputil"
+)
+
+const (
+	// Draft201909 is the meta-schema URI of JSON Schema draft 2019-09.
+	Draft201909 = "https://json-schema.org/draft/2019-09/schema"
+	// Draft202012 is the meta-schema URI of JSON Schema draft 2020-12.
+	Draft202012 = "https://json-schema.org/draft/2020-12/schema"
+
+	// inlineSchemaURL is the base URL given to schemas loaded from a string,
+	// the 2019-09/2020-12 backend needs one to resolve relative references.
+	inlineSchemaURL = "mem:///schema.json"
+)
+
+// Schema is a compiled JSON schema. Implementations wrap a specific validator
+// backend and map its errors into a ValidationResult.
+type Schema interface {
+	// Validate validates the profile against the schema. The schema name is
+	// used to give context in the error details. An error is returned only
+	// when the profile can't be validated at all.
+	Validate(profile ProfileLoader, schemaName string) (*ValidationResult, error)
+	// Lifecycle returns the deprecation metadata of the schema.
+	Lifecycle() Lifecycle
+}
+
+// compileSchema compiles the schema with the backend matching its `$schema`
+// keyword. Drafts 2019-09 and 2020-12 use the newer backend, anything else
+// (including a missing `$schema`) keeps using the draft-07 backend.
+func compileSchema(location string, data []byte) (Schema, error) {
+	var header struct {
+		Schema string `json:"$schema"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+
+	lifecycle := parseLifecycle(data)
+
+	if isDraft2020Schema(header.Schema) {
+		schema, err := newDraft2020Schema(location, data)
+		if err != nil {
+			return nil, err
+		}
+		schema.lifecycle = lifecycle
+		return schema, nil
+	}
+
+	schema, err := newDraft07Schema(location, data)
+	if err != nil {
+		return nil, err
+	}
+	schema.lifecycle = lifecycle
+	return schema, nil
+}
+
+// isDraft2020Schema reports whether the meta-schema URI refers to draft
+// 2019-09 or 2020-12.
+func isDraft2020Schema(metaSchema string) bool {
+	metaSchema = strings.TrimSuffix(metaSchema, "#")
+	return metaSchema == Draft201909 || metaSchema == Draft202012
+}
+
+// fetchSchema downloads the raw schema from the URL.
+func fetchSchema(url string) ([]byte, error) {
+	resp, err := httputil.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf(
+			"could not read schema from %s, response status is %s",
+			url,
+			resp.Status,
+		)
+	}
+
+	return io.ReadAll(resp.Body)
+}
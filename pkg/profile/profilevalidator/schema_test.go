@@ -0,0 +1,193 @@
+package profilevalidator_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/profile/profilevalidator"
+)
+
+const draft2020Schema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"$defs": {
+		"tag": {"type": "string", "minLength": 2}
+	},
+	"properties": {
+		"name": {"type": "string"},
+		"email": {"type": "string"},
+		"email_verified": {"type": "boolean"},
+		"tags": {"type": "array", "items": {"$ref": "#/$defs/tag"}}
+	},
+	"required": ["name"],
+	"dependentRequired": {"email_verified": ["email"]},
+	"unevaluatedProperties": false
+}`
+
+func validateDraft2020(
+	t *testing.T,
+	profile string,
+) *profilevalidator.ValidationResult {
+	t.Helper()
+
+	validator, err := profilevalidator.NewBuilder().
+		WithJSONSchemas([]string{"test_schema-v1.0.0"}, []string{draft2020Schema}).
+		WithStrProfile(profile).
+		Build()
+	require.NoError(t, err)
+
+	return validator.Validate()
+}
+
+func TestValidateDraft2020(t *testing.T) {
+	tests := []struct {
+		name     string
+		profile  string
+		titles   []string
+		pointers []string
+	}{
+		{
+			name:    "valid profile",
+			profile: `{"name": "a", "tags": ["ab"], "email": "a@b.c", "email_verified": true}`,
+		},
+		{
+			name:     "missing required property",
+			profile:  `{}`,
+			titles:   []string{"Missing Required Property"},
+			pointers: []string{"/name"},
+		},
+		{
+			name:     "dependent required property",
+			profile:  `{"name": "a", "email_verified": true}`,
+			titles:   []string{"Missing Required Property"},
+			pointers: []string{"/email"},
+		},
+		{
+			name:     "invalid $defs reference",
+			profile:  `{"name": "a", "tags": ["a"]}`,
+			titles:   []string{"Invalid Length"},
+			pointers: []string{"/tags/0"},
+		},
+		{
+			name:     "unevaluated property",
+			profile:  `{"name": "a", "unknown": 1}`,
+			titles:   []string{"Unexpected Property"},
+			pointers: []string{"/unknown"},
+		},
+		{
+			name:     "invalid type",
+			profile:  `{"name": 1}`,
+			titles:   []string{"Invalid Type"},
+			pointers: []string{"/name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := validateDraft2020(t, tt.profile)
+			require.Equal(t, len(tt.titles) == 0, result.Valid)
+			require.ElementsMatch(t, tt.titles, result.ErrorMessages)
+
+			pointers := make([]string, 0, len(result.Sources))
+			for _, source := range result.Sources {
+				pointers = append(pointers, source[1])
+			}
+			require.ElementsMatch(t, tt.pointers, pointers)
+		})
+	}
+}
+
+func TestValidateDraft07Unchanged(t *testing.T) {
+	validator, err := profilevalidator.NewBuilder().
+		WithJSONSchemas([]string{"test_schema-v1.0.0"}, []string{testSchema}).
+		WithStrProfile(`{}`).
+		Build()
+	require.NoError(t, err)
+
+	result := validator.Validate()
+	require.False(t, result.Valid)
+	require.Equal(t, []string{"Missing Required Property"}, result.ErrorMessages)
+	require.Equal(t, [][]string{{"pointer", "/name"}}, result.Sources)
+}
+
+func TestValidateRelativeRef(t *testing.T) {
+	const name = `{"type": "string", "minLength": 2}`
+	schemas := map[string]string{
+		"draft07": `{
+			"type": "object",
+			"properties": {"name": {"$ref": "./fields/name.json"}}
+		}`,
+		"draft2020": `{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"type": "object",
+			"properties": {"name": {"$ref": "./fields/name.json"}}
+		}`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/v2/schemas/fields/name.json" {
+				_, _ = w.Write([]byte(name))
+				return
+			}
+			schema, ok := schemas[strings.TrimPrefix(r.URL.Path, "/v2/schemas/")]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write([]byte(schema))
+		},
+	))
+	defer server.Close()
+
+	for schemaName := range schemas {
+		t.Run(schemaName, func(t *testing.T) {
+			validator, err := profilevalidator.NewBuilder().
+				WithURLSchemas(server.URL, []string{schemaName}).
+				WithStrProfile(`{"name": "a"}`).
+				Build()
+			require.NoError(t, err)
+
+			result := validator.Validate()
+			require.False(t, result.Valid)
+			require.Equal(t, []string{"Invalid Length"}, result.ErrorMessages)
+		})
+	}
+}
+
+func TestValidateRequiredDetails(t *testing.T) {
+	const properties = `
+		"type": "object",
+		"properties": {
+			"a": {
+				"type": "object",
+				"properties": {
+					"b": {"type": "object", "required": ["c"]}
+				}
+			}
+		}`
+	schemas := []string{
+		`{` + properties + `}`,
+		`{"$schema": "https://json-schema.org/draft/2020-12/schema",` +
+			properties + `}`,
+	}
+
+	for _, schema := range schemas {
+		validator, err := profilevalidator.NewBuilder().
+			WithJSONSchemas([]string{"test_schema-v1.0.0"}, []string{schema}).
+			WithStrProfile(`{"a": {"b": {}}}`).
+			Build()
+		require.NoError(t, err)
+
+		// Both backends report the nested property the same way.
+		result := validator.Validate()
+		require.Equal(t, []string{
+			"The `a/b/c` property is required - Schema: test_schema-v1.0.0",
+		}, result.Details)
+		require.Equal(t, [][]string{{"pointer", "/a/b/c"}}, result.Sources)
+	}
+}
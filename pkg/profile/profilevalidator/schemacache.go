@@ -0,0 +1,195 @@
+package profilevalidator
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemaversion"
+)
+
+// SchemaCache stores compiled schemas keyed by schema name so that repeated
+// validations don't re-fetch and recompile them from the library. It also
+// holds the versions of the families of schemas, looked up for the newer
+// version notices. Once it holds maxEntries entries, the least recently used
+// one is evicted.
+type SchemaCache struct {
+	// ttl is how long a compiled schema stays valid. Zero means forever.
+	ttl time.Duration
+	// maxEntries is the number of schemas kept. Zero means no limit.
+	maxEntries int
+
+	mu sync.Mutex
+	// order holds the entries, the most recently used first.
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+// cacheEntry holds either a compiled schema or the versions of a family.
+type cacheEntry struct {
+	key      string
+	schema   Schema
+	versions []schemaversion.Name
+	cachedAt time.Time
+}
+
+// NewSchemaCache creates an empty SchemaCache. Entries older than ttl are
+// treated as missing; a ttl of zero keeps entries until they are invalidated
+// or evicted. A maxEntries of zero doesn't limit the size of the cache.
+func NewSchemaCache(ttl time.Duration, maxEntries int) *SchemaCache {
+	return &SchemaCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// Get returns the compiled schema stored under name, if it is present and
+// has not expired.
+func (c *SchemaCache) Get(name string) (Schema, bool) {
+	entry, ok := c.get(name)
+	if !ok {
+		return nil, false
+	}
+	return entry.schema, true
+}
+
+// Set stores the compiled schema under name, evicting the least recently
+// used entry when the cache is full.
+func (c *SchemaCache) Set(name string, schema Schema) {
+	c.set(&cacheEntry{key: name, schema: schema})
+}
+
+// GetVersions returns the versions of the family of schemas, if they are
+// present and have not expired.
+func (c *SchemaCache) GetVersions(family string) ([]schemaversion.Name, bool) {
+	entry, ok := c.get(versionsKey(family))
+	if !ok {
+		return nil, false
+	}
+	return entry.versions, true
+}
+
+// SetVersions stores the versions of the family of schemas.
+func (c *SchemaCache) SetVersions(
+	family string,
+	versions []schemaversion.Name,
+) {
+	c.set(&cacheEntry{key: versionsKey(family), versions: versions})
+}
+
+// versionsKey is the key of the versions of a family, which can't be the
+// name of a schema.
+func versionsKey(family string) string {
+	return family + "/versions"
+}
+
+func (c *SchemaCache) get(key string) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	if c.ttl > 0 && time.Since(entry.cachedAt) > c.ttl {
+		c.remove(elem)
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return entry, true
+}
+
+func (c *SchemaCache) set(entry *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry.cachedAt = time.Now()
+	if elem, ok := c.entries[entry.key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[entry.key] = c.order.PushFront(entry)
+
+	if c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		c.remove(c.order.Back())
+	}
+}
+
+// Invalidate removes the given schemas from the cache, along with the
+// versions of their families as a new version may have been added. When no
+// names are given, the whole cache is cleared.
+func (c *SchemaCache) Invalidate(names ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(names) == 0 {
+		c.order.Init()
+		c.entries = make(map[string]*list.Element)
+		return
+	}
+	for _, name := range names {
+		keys := []string{name}
+		if parsed, ok := schemaversion.ParseName(name); ok {
+			keys = append(keys, versionsKey(parsed.Family))
+		}
+		for _, key := range keys {
+			if elem, ok := c.entries[key]; ok {
+				c.remove(elem)
+			}
+		}
+	}
+}
+
+// Len returns the number of entries currently held in the cache.
+func (c *SchemaCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// remove drops the entry from the cache. The caller must hold the lock.
+func (c *SchemaCache) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*cacheEntry).key)
+}
+
+// CachedSchemaLoader is a schema loader that serves compiled schemas from a
+// SchemaCache and falls back to the wrapped Loader on a miss.
+type CachedSchemaLoader struct {
+	// The loader used when the schema is not cached.
+	Loader Loader
+	// The cache holding compiled schemas.
+	Cache *SchemaCache
+}
+
+// Load implements the Loader interface. The source is used as the cache key,
+// which is only right when the source is the name of the schema.
+func (cl *CachedSchemaLoader) Load(
+	source string,
+) (Schema, error) {
+	return cl.LoadNamed(source, source)
+}
+
+// LoadNamed implements the NamedLoader interface. Schemas are cached by name,
+// so that a schema given as JSON is found again by the name it is invalidated
+// with.
+func (cl *CachedSchemaLoader) LoadNamed(
+	name string,
+	source string,
+) (Schema, error) {
+	if schema, ok := cl.Cache.Get(name); ok {
+		return schema, nil
+	}
+
+	schema, err := cl.Loader.Load(source)
+	if err != nil {
+		return nil, err
+	}
+
+	cl.Cache.Set(name, schema)
+	return schema, nil
+}
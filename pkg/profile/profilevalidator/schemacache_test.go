@@ -0,0 +1,105 @@
+package profilevalidator_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/profile/profilevalidator"
+)
+
+const testSchema = `{
+	"type": "object",
+	"properties": {"name": {"type": "string"}},
+	"required": ["name"]
+}`
+
+type countingLoader struct {
+	calls int
+}
+
+func (l *countingLoader) Load(_ string) (profilevalidator.Schema, error) {
+	l.calls++
+	return (&profilevalidator.StrSchemaLoader{}).Load(testSchema)
+}
+
+func TestCachedSchemaLoader(t *testing.T) {
+	loader := &countingLoader{}
+	cache := profilevalidator.NewSchemaCache(0, 0)
+	cached := &profilevalidator.CachedSchemaLoader{Loader: loader, Cache: cache}
+
+	_, err := cached.Load("test_schema-v1.0.0")
+	require.NoError(t, err)
+	_, err = cached.Load("test_schema-v1.0.0")
+	require.NoError(t, err)
+	require.Equal(t, 1, loader.calls)
+
+	_, err = cached.Load("other_schema-v1.0.0")
+	require.NoError(t, err)
+	require.Equal(t, 2, loader.calls)
+	require.Equal(t, 2, cache.Len())
+
+	cache.Invalidate("test_schema-v1.0.0")
+	require.Equal(t, 1, cache.Len())
+	_, err = cached.Load("test_schema-v1.0.0")
+	require.NoError(t, err)
+	require.Equal(t, 3, loader.calls)
+
+	cache.Invalidate()
+	require.Equal(t, 0, cache.Len())
+}
+
+func TestSchemaCacheTTL(t *testing.T) {
+	cache := profilevalidator.NewSchemaCache(time.Millisecond, 0)
+	schema, err := (&profilevalidator.StrSchemaLoader{}).Load(testSchema)
+	require.NoError(t, err)
+
+	cache.Set("test_schema-v1.0.0", schema)
+	_, ok := cache.Get("test_schema-v1.0.0")
+	require.True(t, ok)
+
+	time.Sleep(5 * time.Millisecond)
+	_, ok = cache.Get("test_schema-v1.0.0")
+	require.False(t, ok)
+}
+
+func TestBuilderWithSchemaCache(t *testing.T) {
+	cache := profilevalidator.NewSchemaCache(0, 0)
+
+	for _, profile := range []string{`{"name": "a"}`, `{"name": 1}`} {
+		validator, err := profilevalidator.NewBuilder().
+			WithJSONSchemas([]string{"test_schema-v1.0.0"}, []string{testSchema}).
+			WithStrProfile(profile).
+			WithSchemaCache(cache).
+			Build()
+		require.NoError(t, err)
+		validator.Validate()
+	}
+
+	// The schema given as JSON is cached under its name, not its content.
+	require.Equal(t, 1, cache.Len())
+	_, ok := cache.Get("test_schema-v1.0.0")
+	require.True(t, ok)
+}
+
+func TestSchemaCacheEviction(t *testing.T) {
+	cache := profilevalidator.NewSchemaCache(0, 2)
+	schema, err := (&profilevalidator.StrSchemaLoader{}).Load(testSchema)
+	require.NoError(t, err)
+
+	cache.Set("a-v1.0.0", schema)
+	cache.Set("b-v1.0.0", schema)
+	// Using "a" makes "b" the least recently used schema.
+	_, ok := cache.Get("a-v1.0.0")
+	require.True(t, ok)
+	cache.Set("c-v1.0.0", schema)
+
+	require.Equal(t, 2, cache.Len())
+	_, ok = cache.Get("b-v1.0.0")
+	require.False(t, ok)
+	_, ok = cache.Get("a-v1.0.0")
+	require.True(t, ok)
+	_, ok = cache.Get("c-v1.0.0")
+	require.True(t, ok)
+}
@@ -1,5 +1,7 @@
 package profilevalidator
 
+import "net/http"
+
 // ValidationResult is the results from a schema validation operation.
 type ValidationResult struct {
 	// Whether the validation passed.
@@ -12,6 +14,13 @@ type ValidationResult struct {
 	Sources [][]string
 	// HTTP status codes associated with each error.
 	ErrorStatus []int
+	// Notices about the schemas which don't prevent the validation, such as
+	// the newer versions of the linked schemas.
+	Notices []string
+	// Sunset holds the details of the linked schemas whose sunset has
+	// passed. The profiles already posted with them are still accepted,
+	// RejectSunset turns them into errors for the new ones.
+	Sunset []string
 }
 
 // NewValidationResult initializes a new ValidationResult object with default values.
@@ -38,6 +47,19 @@ func (vr *ValidationResult) AppendError(
 	vr.Valid = false
 }
 
+// RejectSunset adds an error for each linked schema whose sunset has passed,
+// when the profile is posted for the first time.
+func (vr *ValidationResult) RejectSunset() {
+	for _, detail := range vr.Sunset {
+		vr.AppendError(
+			"Schema Sunset",
+			detail,
+			[]string{"pointer", "/linked_schemas"},
+			http.StatusBadRequest,
+		)
+	}
+}
+
 // AppendErrors adds multiple errors to the ValidationResult.
 func (vr *ValidationResult) AppendErrors(
 	errorMessages, details []string,
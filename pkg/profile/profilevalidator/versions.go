@@ -0,0 +1,116 @@
+package profilevalidator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemaversion"
+)
+
+// versionsClient fetches the versions of the schemas. Its timeout is short,
+// the notices aren't worth delaying the validation for.
+var versionsClient = &http.Client{Timeout: 2 * time.Second}
+
+// newerVersionNotices returns a notice for each schema which has a newer
+// minor version in the library, with the same major version so the profile
+// is expected to stay valid with it. The versions are kept in the cache, if
+// any. The schemas whose versions can't be fetched are skipped, the notices
+// are only informative.
+func newerVersionNotices(
+	libraryURL string,
+	cache *SchemaCache,
+	schemaNames []string,
+) []string {
+	var notices []string
+	for _, schemaName := range schemaNames {
+		name, ok := schemaversion.ParseName(schemaName)
+		if !ok {
+			continue
+		}
+
+		versions, err := getVersions(libraryURL, cache, name.Family)
+		if err != nil {
+			continue
+		}
+
+		newest := name
+		for _, version := range versions {
+			if version.Version.Major == name.Version.Major &&
+				version.Version.Minor > name.Version.Minor &&
+				version.Version.Compare(newest.Version) > 0 {
+				newest = version
+			}
+		}
+		if newest != name {
+			notices = append(notices, fmt.Sprintf(
+				"A newer version of the `%s` schema is available: `%s`.",
+				schemaName,
+				newest,
+			))
+		}
+	}
+	return notices
+}
+
+// getVersions returns the versions of a family of schemas from the cache, or
+// from the library when they aren't cached.
+func getVersions(
+	libraryURL string,
+	cache *SchemaCache,
+	family string,
+) ([]schemaversion.Name, error) {
+	if cache != nil {
+		if versions, ok := cache.GetVersions(family); ok {
+			return versions, nil
+		}
+	}
+
+	versions, err := fetchVersions(libraryURL, family)
+	if err != nil {
+		return nil, err
+	}
+	if cache != nil {
+		cache.SetVersions(family, versions)
+	}
+	return versions, nil
+}
+
+// fetchVersions returns the versions of a family of schemas in the library.
+func fetchVersions(
+	libraryURL string,
+	family string,
+) ([]schemaversion.Name, error) {
+	url := fmt.Sprintf("%s/v2/schemas/%s/versions", libraryURL, family)
+	resp, err := versionsClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf(
+			"could not read the versions from %s, response status is %s",
+			url,
+			resp.Status,
+		)
+	}
+
+	var body struct {
+		Data []struct {
+			Name string `json:"name"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	versions := make([]schemaversion.Name, 0, len(body.Data))
+	for _, schema := range body.Data {
+		if name, ok := schemaversion.ParseName(schema.Name); ok {
+			versions = append(versions, name)
+		}
+	}
+	return versions, nil
+}
@@ -0,0 +1,106 @@
+package profilevalidator_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/profile/profilevalidator"
+)
+
+func TestNewerVersionNotices(t *testing.T) {
+	library := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/v2/schemas/test_schema/versions" {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write([]byte(`{"data": [
+				{"name": "test_schema-v1.0.0"},
+				{"name": "test_schema-v1.1.0"},
+				{"name": "test_schema-v1.2.1"},
+				{"name": "test_schema-v2.0.0"}
+			]}`))
+		}),
+	)
+	defer library.Close()
+
+	tests := []struct {
+		name    string
+		schema  string
+		notices []string
+	}{
+		{
+			name:   "newer minor version",
+			schema: "test_schema-v1.0.0",
+			notices: []string{
+				"A newer version of the `test_schema-v1.0.0` schema is " +
+					"available: `test_schema-v1.2.1`.",
+			},
+		},
+		{
+			// The next major version may not be compatible.
+			name:   "latest minor version",
+			schema: "test_schema-v1.2.1",
+		},
+		{
+			name:   "unknown family",
+			schema: "other_schema-v1.0.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator, err := profilevalidator.NewBuilder().
+				WithJSONSchemas([]string{tt.schema}, []string{draft2020Schema}).
+				WithNewerVersionNotices(library.URL, nil).
+				WithStrProfile(`{"name": "a"}`).
+				Build()
+			require.NoError(t, err)
+
+			result := validator.Validate()
+			require.True(t, result.Valid)
+			require.Equal(t, tt.notices, result.Notices)
+		})
+	}
+}
+
+func TestNewerVersionNotices_Cache(t *testing.T) {
+	var requests atomic.Int32
+	library := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			requests.Add(1)
+			_, _ = w.Write([]byte(`{"data": [
+				{"name": "test_schema-v1.0.0"},
+				{"name": "test_schema-v1.1.0"}
+			]}`))
+		}),
+	)
+	defer library.Close()
+
+	cache := profilevalidator.NewSchemaCache(0, 0)
+	validate := func() []string {
+		validator, err := profilevalidator.NewBuilder().
+			WithJSONSchemas(
+				[]string{"test_schema-v1.0.0"},
+				[]string{draft2020Schema},
+			).
+			WithNewerVersionNotices(library.URL, cache).
+			WithStrProfile(`{"name": "a"}`).
+			Build()
+		require.NoError(t, err)
+		return validator.Validate().Notices
+	}
+
+	require.Len(t, validate(), 1)
+	require.Len(t, validate(), 1)
+	require.Equal(t, int32(1), requests.Load())
+
+	// Updating a schema of the family drops its versions.
+	cache.Invalidate("test_schema-v1.1.0")
+	require.Len(t, validate(), 1)
+	require.Equal(t, int32(2), requests.Load())
+}
@@ -0,0 +1,60 @@
+package redis
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// MemoryURL is the URL of an in-memory Redis. NewClient returns a client
+// keeping the values within the process for it, to run the services without
+// a Redis server in development.
+const MemoryURL = "memory://"
+
+type memoryEntry struct {
+	value     string
+	expiresAt time.Time
+}
+
+type memoryClient struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+func newMemoryClient() *memoryClient {
+	return &memoryClient{entries: make(map[string]memoryEntry)}
+}
+
+func (*memoryClient) Ping() error {
+	return nil
+}
+
+func (m *memoryClient) Set(
+	key string,
+	value interface{},
+	expiration time.Duration,
+) error {
+	entry := memoryEntry{value: fmt.Sprint(value)}
+	if expiration > 0 {
+		entry.expiresAt = time.Now().Add(expiration)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = entry
+	return nil
+}
+
+func (m *memoryClient) Get(key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.entries[key]
+	if !ok {
+		return "", nil
+	}
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		delete(m.entries, key)
+		return "", nil
+	}
+	return entry.value, nil
+}
@@ -17,6 +17,9 @@ func NewClient(url string) Redis {
 	if os.Getenv("APP_ENV") == "test" {
 		return &redismock{}
 	}
+	if url == MemoryURL {
+		return newMemoryClient()
+	}
 	return &redisImpl{
 		client: redis.NewClient(&redis.Options{
 			Addr:         url,
@@ -0,0 +1,345 @@
+// Package schemacompat compares two versions of a composed schema to tell
+// whether the profiles valid against the older one stay valid against the
+// newer one.
+package schemacompat
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Kinds of changes between two versions of a schema.
+const (
+	RequiredAdded   = "required_added"
+	RequiredRemoved = "required_removed"
+	PropertyAdded   = "property_added"
+	PropertyRemoved = "property_removed"
+	TypeNarrowed    = "type_narrowed"
+	TypeWidened     = "type_widened"
+	EnumNarrowed    = "enum_narrowed"
+	EnumWidened     = "enum_widened"
+	// The fields which aren't in `properties` are no longer allowed, or are
+	// allowed again, with `"additionalProperties": false`.
+	AdditionalClosed = "additional_closed"
+	AdditionalOpened = "additional_opened"
+)
+
+// Change is a difference between two versions of a schema.
+type Change struct {
+	// Path is the JSON pointer to the changed schema, such as
+	// "/properties/tags/items".
+	Path string `json:"path"`
+	// Kind is one of the kinds of changes, such as "required_added".
+	Kind string `json:"kind"`
+	// Breaking is set when profiles valid against the older version may be
+	// invalid against the newer one.
+	Breaking bool   `json:"breaking"`
+	Detail   string `json:"detail"`
+}
+
+// Compare returns the changes from the schema from to the schema to, ordered
+// by path. The schemas are JSON documents decoded into maps, with their
+// references resolved.
+func Compare(from, to map[string]interface{}) []Change {
+	changes := compareSchemas("", from, to)
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+// IsCompatible reports whether none of the changes is breaking.
+func IsCompatible(changes []Change) bool {
+	for _, change := range changes {
+		if change.Breaking {
+			return false
+		}
+	}
+	return true
+}
+
+func compareSchemas(path string, from, to map[string]interface{}) []Change {
+	var changes []Change
+	changes = append(changes, compareRequired(path, from, to)...)
+	changes = append(changes, compareTypes(path, from, to)...)
+	changes = append(changes, compareEnums(path, from, to)...)
+	changes = append(changes, compareProperties(path, from, to)...)
+
+	fromItems, fromOK := from["items"].(map[string]interface{})
+	toItems, toOK := to["items"].(map[string]interface{})
+	if fromOK && toOK {
+		changes = append(
+			changes,
+			compareSchemas(path+"/items", fromItems, toItems)...,
+		)
+	}
+	return changes
+}
+
+func compareRequired(path string, from, to map[string]interface{}) []Change {
+	fromRequired := stringSet(from["required"])
+	toRequired := stringSet(to["required"])
+
+	var changes []Change
+	for _, name := range sortedKeys(toRequired) {
+		if !fromRequired[name] {
+			changes = append(changes, Change{
+				Path:     path + "/properties/" + name,
+				Kind:     RequiredAdded,
+				Breaking: true,
+				Detail: fmt.Sprintf(
+					"The `%s` field is now required.",
+					name,
+				),
+			})
+		}
+	}
+	for _, name := range sortedKeys(fromRequired) {
+		if !toRequired[name] {
+			changes = append(changes, Change{
+				Path: path + "/properties/" + name,
+				Kind: RequiredRemoved,
+				Detail: fmt.Sprintf(
+					"The `%s` field is no longer required.",
+					name,
+				),
+			})
+		}
+	}
+	return changes
+}
+
+func compareProperties(
+	path string,
+	from, to map[string]interface{},
+) []Change {
+	fromProperties, _ := from["properties"].(map[string]interface{})
+	toProperties, _ := to["properties"].(map[string]interface{})
+	// Without additional properties, the removed fields can't be kept.
+	closed := to["additionalProperties"] == false
+
+	var changes []Change
+	switch wasClosed := from["additionalProperties"] == false; {
+	case closed && !wasClosed:
+		changes = append(changes, Change{
+			Path:     path + "/additionalProperties",
+			Kind:     AdditionalClosed,
+			Breaking: true,
+			Detail:   "The fields which aren't listed are no longer allowed.",
+		})
+	case !closed && wasClosed:
+		changes = append(changes, Change{
+			Path:   path + "/additionalProperties",
+			Kind:   AdditionalOpened,
+			Detail: "The fields which aren't listed are now allowed.",
+		})
+	}
+	for _, name := range sortedMapKeys(fromProperties) {
+		propertyPath := path + "/properties/" + name
+		toProperty, ok := toProperties[name]
+		if !ok {
+			changes = append(changes, Change{
+				Path:     propertyPath,
+				Kind:     PropertyRemoved,
+				Breaking: closed,
+				Detail: fmt.Sprintf(
+					"The `%s` field was removed.",
+					name,
+				),
+			})
+			continue
+		}
+
+		fromSchema, fromOK := fromProperties[name].(map[string]interface{})
+		toSchema, toOK := toProperty.(map[string]interface{})
+		if fromOK && toOK {
+			changes = append(
+				changes,
+				compareSchemas(propertyPath, fromSchema, toSchema)...,
+			)
+		}
+	}
+	for _, name := range sortedMapKeys(toProperties) {
+		if _, ok := fromProperties[name]; !ok {
+			changes = append(changes, Change{
+				Path:   path + "/properties/" + name,
+				Kind:   PropertyAdded,
+				Detail: fmt.Sprintf("The `%s` field was added.", name),
+			})
+		}
+	}
+	return changes
+}
+
+func compareTypes(path string, from, to map[string]interface{}) []Change {
+	fromTypes := stringSet(from["type"])
+	toTypes := stringSet(to["type"])
+	// No type allows any type.
+	if len(toTypes) == 0 {
+		if len(fromTypes) == 0 {
+			return nil
+		}
+		return []Change{{
+			Path:   path,
+			Kind:   TypeWidened,
+			Detail: "The type is no longer restricted.",
+		}}
+	}
+	if len(fromTypes) == 0 {
+		return []Change{{
+			Path:     path,
+			Kind:     TypeNarrowed,
+			Breaking: true,
+			Detail: fmt.Sprintf(
+				"The type is now restricted to %s.",
+				quote(sortedKeys(toTypes)),
+			),
+		}}
+	}
+
+	// An integer is also a number.
+	if toTypes["number"] {
+		toTypes["integer"] = true
+	}
+
+	var removed, added []string
+	for _, t := range sortedKeys(fromTypes) {
+		if !toTypes[t] {
+			removed = append(removed, t)
+		}
+	}
+	for _, t := range sortedKeys(toTypes) {
+		if !fromTypes[t] && !(t == "integer" && fromTypes["number"]) {
+			added = append(added, t)
+		}
+	}
+
+	var changes []Change
+	if len(removed) > 0 {
+		changes = append(changes, Change{
+			Path:     path,
+			Kind:     TypeNarrowed,
+			Breaking: true,
+			Detail: fmt.Sprintf(
+				"The %s type is no longer allowed.",
+				quote(removed),
+			),
+		})
+	}
+	if len(added) > 0 {
+		changes = append(changes, Change{
+			Path:   path,
+			Kind:   TypeWidened,
+			Detail: fmt.Sprintf("The %s type is now allowed.", quote(added)),
+		})
+	}
+	return changes
+}
+
+func compareEnums(path string, from, to map[string]interface{}) []Change {
+	fromEnum, fromOK := from["enum"].([]interface{})
+	toEnum, toOK := to["enum"].([]interface{})
+	switch {
+	case !fromOK && !toOK:
+		return nil
+	case !toOK:
+		return []Change{{
+			Path:   path,
+			Kind:   EnumWidened,
+			Detail: "The values are no longer restricted.",
+		}}
+	case !fromOK:
+		return []Change{{
+			Path:     path,
+			Kind:     EnumNarrowed,
+			Breaking: true,
+			Detail:   "The values are now restricted.",
+		}}
+	}
+
+	removed := missingValues(fromEnum, toEnum)
+	added := missingValues(toEnum, fromEnum)
+
+	var changes []Change
+	if len(removed) > 0 {
+		changes = append(changes, Change{
+			Path:     path,
+			Kind:     EnumNarrowed,
+			Breaking: true,
+			Detail: fmt.Sprintf(
+				"The %s values are no longer allowed.",
+				quote(removed),
+			),
+		})
+	}
+	if len(added) > 0 {
+		changes = append(changes, Change{
+			Path: path,
+			Kind: EnumWidened,
+			Detail: fmt.Sprintf(
+				"The %s values are now allowed.",
+				quote(added),
+			),
+		})
+	}
+	return changes
+}
+
+// missingValues returns the values of a which aren't in b.
+func missingValues(a, b []interface{}) []string {
+	var missing []string
+	for _, value := range a {
+		found := false
+		for _, other := range b {
+			if reflect.DeepEqual(value, other) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, fmt.Sprint(value))
+		}
+	}
+	return missing
+}
+
+// stringSet returns the strings of a value which is either a string or an
+// array of strings, such as the `type` and `required` keywords.
+func stringSet(value interface{}) map[string]bool {
+	set := make(map[string]bool)
+	switch v := value.(type) {
+	case string:
+		set[v] = true
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				set[s] = true
+			}
+		}
+	}
+	return set
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedMapKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func quote(values []string) string {
+	return "`" + strings.Join(values, "`, `") + "`"
+}
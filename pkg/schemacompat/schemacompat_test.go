@@ -0,0 +1,183 @@
+package schemacompat_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemacompat"
+)
+
+func schema(t *testing.T, s string) map[string]interface{} {
+	var m map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(s), &m))
+	return m
+}
+
+func TestCompare(t *testing.T) {
+	from := `{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"status": {"type": "string", "enum": ["active", "inactive"]},
+			"age": {"type": "number"},
+			"tags": {"type": "array", "items": {"type": ["string", "number"]}},
+			"nickname": {"type": "string"}
+		},
+		"required": ["name", "nickname"]
+	}`
+
+	tests := []struct {
+		name       string
+		to         string
+		changes    []schemacompat.Change
+		compatible bool
+	}{
+		{
+			name:       "same schema",
+			to:         from,
+			compatible: true,
+		},
+		{
+			name: "compatible changes",
+			to: `{
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"},
+					"status": {"type": "string",
+						"enum": ["active", "inactive", "paused"]},
+					"age": {"type": "number"},
+					"tags": {"type": "array", "items": {}},
+					"nickname": {"type": "string"},
+					"email": {"type": "string"}
+				},
+				"required": ["name"]
+			}`,
+			changes: []schemacompat.Change{
+				{
+					Path:   "/properties/email",
+					Kind:   schemacompat.PropertyAdded,
+					Detail: "The `email` field was added.",
+				},
+				{
+					Path:   "/properties/nickname",
+					Kind:   schemacompat.RequiredRemoved,
+					Detail: "The `nickname` field is no longer required.",
+				},
+				{
+					Path:   "/properties/status",
+					Kind:   schemacompat.EnumWidened,
+					Detail: "The `paused` values are now allowed.",
+				},
+				{
+					Path:   "/properties/tags/items",
+					Kind:   schemacompat.TypeWidened,
+					Detail: "The type is no longer restricted.",
+				},
+			},
+			compatible: true,
+		},
+		{
+			name: "breaking changes",
+			to: `{
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"},
+					"status": {"type": "string", "enum": ["active"]},
+					"age": {"type": "integer"},
+					"tags": {"type": "array", "items": {"type": "string"}},
+					"email": {"type": "string"}
+				},
+				"required": ["email", "name"],
+				"additionalProperties": false
+			}`,
+			changes: []schemacompat.Change{
+				{
+					Path:     "/additionalProperties",
+					Kind:     schemacompat.AdditionalClosed,
+					Breaking: true,
+					Detail: "The fields which aren't listed are no " +
+						"longer allowed.",
+				},
+				{
+					Path:     "/properties/age",
+					Kind:     schemacompat.TypeNarrowed,
+					Breaking: true,
+					Detail:   "The `number` type is no longer allowed.",
+				},
+				{
+					Path:     "/properties/email",
+					Kind:     schemacompat.RequiredAdded,
+					Breaking: true,
+					Detail:   "The `email` field is now required.",
+				},
+				{
+					Path:   "/properties/email",
+					Kind:   schemacompat.PropertyAdded,
+					Detail: "The `email` field was added.",
+				},
+				{
+					Path:   "/properties/nickname",
+					Kind:   schemacompat.RequiredRemoved,
+					Detail: "The `nickname` field is no longer required.",
+				},
+				{
+					Path:     "/properties/nickname",
+					Kind:     schemacompat.PropertyRemoved,
+					Breaking: true,
+					Detail:   "The `nickname` field was removed.",
+				},
+				{
+					Path:     "/properties/status",
+					Kind:     schemacompat.EnumNarrowed,
+					Breaking: true,
+					Detail:   "The `inactive` values are no longer allowed.",
+				},
+				{
+					Path:     "/properties/tags/items",
+					Kind:     schemacompat.TypeNarrowed,
+					Breaking: true,
+					Detail:   "The `number` type is no longer allowed.",
+				},
+			},
+			compatible: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := schemacompat.Compare(
+				schema(t, from),
+				schema(t, tt.to),
+			)
+			require.Equal(t, tt.changes, changes)
+			require.Equal(t, tt.compatible, schemacompat.IsCompatible(changes))
+		})
+	}
+}
+
+func TestCompare_AdditionalProperties(t *testing.T) {
+	open := `{"type": "object", "properties": {"name": {"type": "string"}}}`
+	closed := `{"type": "object", "properties": {"name": {"type": "string"}},
+		"additionalProperties": false}`
+
+	// The profiles with fields which aren't listed become invalid, even
+	// though no field was removed.
+	changes := schemacompat.Compare(schema(t, open), schema(t, closed))
+	require.Equal(t, []schemacompat.Change{{
+		Path:     "/additionalProperties",
+		Kind:     schemacompat.AdditionalClosed,
+		Breaking: true,
+		Detail:   "The fields which aren't listed are no longer allowed.",
+	}}, changes)
+	require.False(t, schemacompat.IsCompatible(changes))
+
+	changes = schemacompat.Compare(schema(t, closed), schema(t, open))
+	require.Equal(t, []schemacompat.Change{{
+		Path:   "/additionalProperties",
+		Kind:   schemacompat.AdditionalOpened,
+		Detail: "The fields which aren't listed are now allowed.",
+	}}, changes)
+	require.True(t, schemacompat.IsCompatible(changes))
+}
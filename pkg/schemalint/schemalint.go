@@ -0,0 +1,355 @@
+// Package schemalint checks the schemas of the library against the JSON
+// Schema meta-schemas and the Murmurations conventions before they are
+// published.
+package schemalint
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemaversion"
+)
+
+// Severities of the issues. Only the errors prevent a schema from being
+// published.
+const (
+	Error   = "error"
+	Warning = "warning"
+)
+
+// Rules checked by the linter.
+const (
+	// RuleReference is broken when a `$ref` can't be resolved.
+	RuleReference = "reference"
+	// RuleMetaSchema is broken when the schema isn't valid JSON Schema.
+	RuleMetaSchema    = "meta_schema"
+	RuleTitle         = "title"
+	RuleSchemaName    = "schema_name"
+	RuleFileName      = "file_name"
+	RuleLinkedSchemas = "linked_schemas"
+	RuleFieldTitle    = "field_title"
+)
+
+// Issue is a problem found in a schema or a field.
+type Issue struct {
+	// Path is the JSON pointer to the problem, such as "/properties/name".
+	Path     string `json:"path"`
+	Severity string `json:"severity"`
+	Rule     string `json:"rule"`
+	Message  string `json:"message"`
+}
+
+// FileReport lists the issues of a file.
+type FileReport struct {
+	File   string  `json:"file"`
+	Issues []Issue `json:"issues"`
+}
+
+// Report lists the issues of the files of a library, ordered by file.
+type Report struct {
+	Files []FileReport `json:"files"`
+}
+
+// Add appends the issues of a file, if any, to the report.
+func (r *Report) Add(file string, issues ...Issue) {
+	if len(issues) == 0 {
+		return
+	}
+	for i := range r.Files {
+		if r.Files[i].File == file {
+			r.Files[i].Issues = append(r.Files[i].Issues, issues...)
+			return
+		}
+	}
+	r.Files = append(r.Files, FileReport{File: file, Issues: issues})
+	sort.SliceStable(r.Files, func(i, j int) bool {
+		return r.Files[i].File < r.Files[j].File
+	})
+}
+
+// HasErrors reports whether any issue is an error.
+func (r *Report) HasErrors() bool {
+	return len(r.Errors()) > 0
+}
+
+// Errors returns the errors, formatted as "file: path: message".
+func (r *Report) Errors() []string {
+	var errs []string
+	for _, file := range r.Files {
+		for _, issue := range file.Issues {
+			if issue.Severity == Error {
+				errs = append(errs, file.File+": "+issue.String())
+			}
+		}
+	}
+	return errs
+}
+
+// String formats the issue as "path: message".
+func (i Issue) String() string {
+	if i.Path == "" {
+		return i.Message
+	}
+	return i.Path + ": " + i.Message
+}
+
+// Errors returns the issues which are errors.
+func Errors(issues []Issue) []Issue {
+	var errs []Issue
+	for _, issue := range issues {
+		if issue.Severity == Error {
+			errs = append(errs, issue)
+		}
+	}
+	return errs
+}
+
+// ReferenceIssue reports a schema or a field which couldn't be parsed, such
+// as when a `$ref` is broken.
+func ReferenceIssue(err error) Issue {
+	return Issue{
+		Severity: Error,
+		Rule:     RuleReference,
+		Message:  fmt.Sprintf("The file could not be parsed: %s.", err),
+	}
+}
+
+// schemaDoc holds the parts of a schema checked by the conventions.
+type schemaDoc struct {
+	Title      string                     `json:"title"`
+	Properties map[string]json.RawMessage `json:"properties"`
+	Metadata   struct {
+		Schema struct {
+			Name string `json:"name"`
+		} `json:"schema"`
+	} `json:"metadata"`
+}
+
+// Schema lints a schema with its references resolved. fileName is the name
+// of its file, such as "organizations_schema-v1.0.0.json", or empty when the
+// schema has no file.
+func Schema(fileName string, data []byte) []Issue {
+	var doc schemaDoc
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return []Issue{{
+			Severity: Error,
+			Rule:     RuleMetaSchema,
+			Message:  fmt.Sprintf("The schema is not valid JSON: %s.", err),
+		}}
+	}
+
+	issues := metaSchema(data)
+	if doc.Title == "" {
+		issues = append(issues, Issue{
+			Path:     "/title",
+			Severity: Error,
+			Rule:     RuleTitle,
+			Message:  "The `title` of the schema is missing.",
+		})
+	}
+	issues = append(issues, schemaName(fileName, doc.Metadata.Schema.Name)...)
+	if _, ok := doc.Properties["linked_schemas"]; !ok {
+		issues = append(issues, Issue{
+			Path:     "/properties/linked_schemas",
+			Severity: Error,
+			Rule:     RuleLinkedSchemas,
+			Message:  "The schema must have the `linked_schemas` property.",
+		})
+	}
+	return append(issues, fieldTitles("", doc.Properties)...)
+}
+
+// Field lints a field with its references resolved.
+func Field(data []byte) []Issue {
+	var doc struct {
+		Title      string                     `json:"title"`
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return []Issue{{
+			Severity: Error,
+			Rule:     RuleMetaSchema,
+			Message:  fmt.Sprintf("The field is not valid JSON: %s.", err),
+		}}
+	}
+
+	issues := metaSchema(data)
+	if doc.Title == "" {
+		issues = append(issues, Issue{
+			Path:     "/title",
+			Severity: Warning,
+			Rule:     RuleFieldTitle,
+			Message:  "The `title` of the field is missing.",
+		})
+	}
+	return append(issues, fieldTitles("", doc.Properties)...)
+}
+
+func schemaName(fileName, name string) []Issue {
+	if name == "" {
+		return []Issue{{
+			Path:     "/metadata/schema/name",
+			Severity: Error,
+			Rule:     RuleSchemaName,
+			Message:  "The `metadata.schema.name` of the schema is missing.",
+		}}
+	}
+
+	var issues []Issue
+	if _, ok := schemaversion.ParseName(name); !ok {
+		issues = append(issues, Issue{
+			Path:     "/metadata/schema/name",
+			Severity: Error,
+			Rule:     RuleSchemaName,
+			Message: "The `metadata.schema.name` must end with the version " +
+				"of the schema, such as `my_schema-v1.0.0`.",
+		})
+	}
+	if fileName != "" && path.Base(fileName) != name+".json" {
+		issues = append(issues, Issue{
+			Path:     "/metadata/schema/name",
+			Severity: Error,
+			Rule:     RuleFileName,
+			Message: fmt.Sprintf(
+				"The file should be named `%s.json` after the schema.",
+				name,
+			),
+		})
+	}
+	return issues
+}
+
+// fieldTitles warns about the properties without a title, which the forms
+// built from the schemas use as labels.
+func fieldTitles(
+	pointer string,
+	properties map[string]json.RawMessage,
+) []Issue {
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var issues []Issue
+	for _, name := range names {
+		var property struct {
+			Title      string                     `json:"title"`
+			Properties map[string]json.RawMessage `json:"properties"`
+			Items      struct {
+				Properties map[string]json.RawMessage `json:"properties"`
+			} `json:"items"`
+		}
+		// The meta-schema reports the properties which aren't objects.
+		if json.Unmarshal(properties[name], &property) != nil {
+			continue
+		}
+
+		propertyPointer := pointer + "/properties/" + name
+		if property.Title == "" {
+			issues = append(issues, Issue{
+				Path:     propertyPointer,
+				Severity: Warning,
+				Rule:     RuleFieldTitle,
+				Message:  fmt.Sprintf("The `%s` field has no `title`.", name),
+			})
+		}
+		issues = append(
+			issues,
+			fieldTitles(propertyPointer, property.Properties)...,
+		)
+		issues = append(
+			issues,
+			fieldTitles(propertyPointer+"/items", property.Items.Properties)...,
+		)
+	}
+	return issues
+}
+
+// metaSchema validates the schema against the meta-schema of its `$schema`,
+// draft-07 when it is missing like the validators of the profiles.
+func metaSchema(data []byte) []Issue {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return []Issue{{
+			Severity: Error,
+			Rule:     RuleMetaSchema,
+			Message:  fmt.Sprintf("The schema is not valid JSON: %s.", err),
+		}}
+	}
+
+	const location = "mem:///schema.json"
+	compiler := jsonschema.NewCompiler()
+	compiler.DefaultDraft(jsonschema.Draft7)
+	if err := compiler.AddResource(location, doc); err != nil {
+		return []Issue{metaSchemaIssue("", err.Error())}
+	}
+	_, err = compiler.Compile(location)
+	if err == nil {
+		return nil
+	}
+
+	var schemaErr *jsonschema.SchemaValidationError
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &schemaErr) ||
+		!errors.As(schemaErr.Err, &validationErr) {
+		return []Issue{metaSchemaIssue("", err.Error())}
+	}
+
+	printer := message.NewPrinter(language.English)
+	var issues []Issue
+	seen := make(map[string]bool)
+	for _, leaf := range leafErrors(validationErr) {
+		pointer := ""
+		if len(leaf.InstanceLocation) > 0 {
+			pointer = "/" + strings.Join(leaf.InstanceLocation, "/")
+		}
+		// The alternatives of an `anyOf` fail at the same place, the first
+		// one explains enough.
+		if seen[pointer] {
+			continue
+		}
+		seen[pointer] = true
+		issues = append(
+			issues,
+			metaSchemaIssue(pointer, leaf.ErrorKind.LocalizedString(printer)),
+		)
+	}
+	return issues
+}
+
+func metaSchemaIssue(pointer, detail string) Issue {
+	return Issue{
+		Path:     pointer,
+		Severity: Error,
+		Rule:     RuleMetaSchema,
+		Message: fmt.Sprintf(
+			"The schema is not valid JSON Schema: %s.",
+			strings.TrimSuffix(detail, "."),
+		),
+	}
+}
+
+// leafErrors flattens the error tree, only the leaves describe actual
+// failures.
+func leafErrors(
+	validationErr *jsonschema.ValidationError,
+) []*jsonschema.ValidationError {
+	if len(validationErr.Causes) == 0 {
+		return []*jsonschema.ValidationError{validationErr}
+	}
+	var leaves []*jsonschema.ValidationError
+	for _, cause := range validationErr.Causes {
+		leaves = append(leaves, leafErrors(cause)...)
+	}
+	return leaves
+}
@@ -0,0 +1,152 @@
+package schemalint_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemalint"
+)
+
+func TestSchema(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileName string
+		schema   string
+		issues   []schemalint.Issue
+	}{
+		{
+			name:     "valid schema",
+			fileName: "test_schema-v1.0.0.json",
+			schema: `{
+				"$schema": "https://json-schema.org/draft-07/schema#",
+				"title": "Test Schema",
+				"type": "object",
+				"properties": {
+					"linked_schemas": {"title": "Linked Schemas",
+						"type": "array"},
+					"name": {"title": "Name", "type": "string"}
+				},
+				"metadata": {"schema": {"name": "test_schema-v1.0.0"}}
+			}`,
+		},
+		{
+			name:     "broken conventions",
+			fileName: "schemas/test.json",
+			schema: `{
+				"type": "object",
+				"properties": {
+					"tags": {"type": "array", "items": {"type": "object",
+						"properties": {"tag": {"type": "string"}}}}
+				},
+				"metadata": {"schema": {"name": "test_schema"}}
+			}`,
+			issues: []schemalint.Issue{
+				{
+					Path:     "/title",
+					Severity: schemalint.Error,
+					Rule:     schemalint.RuleTitle,
+					Message:  "The `title` of the schema is missing.",
+				},
+				{
+					Path:     "/metadata/schema/name",
+					Severity: schemalint.Error,
+					Rule:     schemalint.RuleSchemaName,
+					Message: "The `metadata.schema.name` must end with the " +
+						"version of the schema, such as `my_schema-v1.0.0`.",
+				},
+				{
+					Path:     "/metadata/schema/name",
+					Severity: schemalint.Error,
+					Rule:     schemalint.RuleFileName,
+					Message: "The file should be named `test_schema.json` " +
+						"after the schema.",
+				},
+				{
+					Path:     "/properties/linked_schemas",
+					Severity: schemalint.Error,
+					Rule:     schemalint.RuleLinkedSchemas,
+					Message: "The schema must have the `linked_schemas` " +
+						"property.",
+				},
+				{
+					Path:     "/properties/tags",
+					Severity: schemalint.Warning,
+					Rule:     schemalint.RuleFieldTitle,
+					Message:  "The `tags` field has no `title`.",
+				},
+				{
+					Path:     "/properties/tags/items/properties/tag",
+					Severity: schemalint.Warning,
+					Rule:     schemalint.RuleFieldTitle,
+					Message:  "The `tag` field has no `title`.",
+				},
+			},
+		},
+		{
+			name: "invalid JSON Schema",
+			schema: `{
+				"title": "Test",
+				"type": "object",
+				"properties": {"linked_schemas": {"title": "Linked Schemas",
+					"type": "list"}},
+				"metadata": {"schema": {"name": "test_schema-v1.0.0"}}
+			}`,
+			issues: []schemalint.Issue{
+				{
+					Path:     "/properties/linked_schemas/type",
+					Severity: schemalint.Error,
+					Rule:     schemalint.RuleMetaSchema,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := schemalint.Schema(tt.fileName, []byte(tt.schema))
+			// The messages of the meta-schema come from the validator.
+			for i := range issues {
+				if issues[i].Rule == schemalint.RuleMetaSchema {
+					require.Contains(
+						t,
+						issues[i].Message,
+						"The schema is not valid JSON Schema: ",
+					)
+					issues[i].Message = ""
+				}
+			}
+			require.Equal(t, tt.issues, issues)
+		})
+	}
+}
+
+func TestField(t *testing.T) {
+	require.Empty(t, schemalint.Field([]byte(
+		`{"title": "Name", "type": "string"}`,
+	)))
+	require.Equal(t, []schemalint.Issue{{
+		Path:     "/title",
+		Severity: schemalint.Warning,
+		Rule:     schemalint.RuleFieldTitle,
+		Message:  "The `title` of the field is missing.",
+	}}, schemalint.Field([]byte(`{"type": "string"}`)))
+}
+
+func TestReport(t *testing.T) {
+	var report schemalint.Report
+	report.Add("b.json", schemalint.Issue{
+		Severity: schemalint.Warning,
+		Message:  "A warning.",
+	})
+	report.Add("a.json")
+	require.False(t, report.HasErrors())
+
+	report.Add("a.json", schemalint.ReferenceIssue(errors.New("not found")))
+	require.True(t, report.HasErrors())
+	require.Equal(t, []string{
+		"a.json: The file could not be parsed: not found.",
+	}, report.Errors())
+	require.Equal(t, "a.json", report.Files[0].File)
+}
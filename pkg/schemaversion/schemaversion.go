@@ -0,0 +1,80 @@
+// Package schemaversion parses the versions embedded in the names of the
+// schemas, such as "organizations_schema-v1.0.0", where "organizations_schema"
+// is the family of the schema and "1.0.0" its version.
+package schemaversion
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+var nameRegex = regexp.MustCompile(`^(.+)-v(\d+)\.(\d+)\.(\d+)$`)
+
+// Version is the semantic version of a schema.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// String returns the version without the "v" prefix, such as "1.0.0".
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Compare returns -1, 0 or 1 when v is older than, the same as or newer than
+// other.
+func (v Version) Compare(other Version) int {
+	for _, diff := range []int{
+		v.Major - other.Major,
+		v.Minor - other.Minor,
+		v.Patch - other.Patch,
+	} {
+		if diff < 0 {
+			return -1
+		}
+		if diff > 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+// Name is the name of a schema split into its family and its version.
+type Name struct {
+	Family  string
+	Version Version
+}
+
+// String returns the name of the schema.
+func (n Name) String() string {
+	return n.Family + "-v" + n.Version.String()
+}
+
+// ParseName splits the name of a schema into its family and its version. It
+// returns false when the name has no version.
+func ParseName(name string) (Name, bool) {
+	matches := nameRegex.FindStringSubmatch(name)
+	if matches == nil {
+		return Name{}, false
+	}
+
+	var numbers [3]int
+	for i, match := range matches[2:] {
+		number, err := strconv.Atoi(match)
+		if err != nil {
+			return Name{}, false
+		}
+		numbers[i] = number
+	}
+
+	return Name{
+		Family: matches[1],
+		Version: Version{
+			Major: numbers[0],
+			Minor: numbers[1],
+			Patch: numbers[2],
+		},
+	}, true
+}
@@ -0,0 +1,63 @@
+package schemaversion_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemaversion"
+)
+
+func TestParseName(t *testing.T) {
+	tests := []struct {
+		name   string
+		parsed schemaversion.Name
+		ok     bool
+	}{
+		{
+			name: "organizations_schema-v1.0.0",
+			parsed: schemaversion.Name{
+				Family:  "organizations_schema",
+				Version: schemaversion.Version{Major: 1},
+			},
+			ok: true,
+		},
+		{
+			name: "karte_von_morgen-v12.3.45",
+			parsed: schemaversion.Name{
+				Family: "karte_von_morgen",
+				Version: schemaversion.Version{
+					Major: 12,
+					Minor: 3,
+					Patch: 45,
+				},
+			},
+			ok: true,
+		},
+		{name: "test_schema-v1.0", ok: false},
+		{name: "-v1.0.0", ok: false},
+		{name: "default", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, ok := schemaversion.ParseName(tt.name)
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.parsed, parsed)
+			if ok {
+				require.Equal(t, tt.name, parsed.String())
+			}
+		})
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	v := func(major, minor, patch int) schemaversion.Version {
+		return schemaversion.Version{Major: major, Minor: minor, Patch: patch}
+	}
+
+	require.Equal(t, 0, v(1, 2, 3).Compare(v(1, 2, 3)))
+	require.Equal(t, -1, v(1, 2, 3).Compare(v(1, 10, 0)))
+	require.Equal(t, 1, v(2, 0, 0).Compare(v(1, 10, 10)))
+	require.Equal(t, 1, v(1, 2, 4).Compare(v(1, 2, 3)))
+}
@@ -51,8 +51,8 @@ make deploy-nats DEPLOY_ENV=$DEPLOY_ENV
 
 # Deployment logic for each service.
 declare -a services=("index" "library" "validation" "dataproxy"
-                     "nodecleaner" "revalidatenode" "schemaparser"
-                     "dataproxyupdater" "dataproxyrefresher")
+                     "nodecleaner" "nodereconciler" "revalidatenode"
+                     "schemaparser" "dataproxyupdater" "dataproxyrefresher")
 
 # Remove maintenance service from the list of services to deploy
 exclude_services=("${exclude_services[@]/maintenance}")
@@ -10,11 +10,12 @@ AFTER_COMMIT=$2
 # Associative array mapping services to their directory patterns.
 # Each service is associated with directories and files it depends on.
 declare -A SERVICE_PATHS=(
-    ["index"]="go.mod pkg/ cmd/index/ services/index/"
+    ["index"]="go.mod pkg/ cmd/index/ cmd/reindex/ cmd/dlq/ services/index/"
     ["library"]="go.mod pkg/ cmd/library/ services/library/"
     ["validation"]="go.mod pkg/ cmd/validation/ services/validation/"
     ["dataproxy"]="go.mod pkg/ cmd/dataproxy/ services/dataproxy/"
     ["nodecleaner"]="go.mod pkg/ cmd/nodecleaner/ services/nodecleaner/"
+    ["nodereconciler"]="go.mod pkg/ cmd/nodereconciler/ services/nodereconciler/"
     ["revalidatenode"]="go.mod pkg/ cmd/revalidatenode/ services/revalidatenode/"
     ["schemaparser"]="go.mod pkg/ cmd/schemaparser/ services/schemaparser/"
     ["dataproxyupdater"]="go.mod pkg/ cmd/dataproxyupdater/ services/dataproxyupdater/"
@@ -28,7 +28,8 @@ type indexConf struct {
 }
 
 type libraryConf struct {
-	InternalURL string `env:"LIBRARY_URL,required"`
+	InternalURL   string `env:"LIBRARY_URL,required"`
+	DefaultSchema string `env:"DEFAULT_SCHEMA"      envDefault:"default-v2.1.0"`
 }
 
 type dataProxyConf struct {
@@ -14,14 +14,14 @@ type SchemasResponse struct {
 	SchemaNames []string
 }
 
-// DefaultSchema defines the default schema version to be fetched.
-const DefaultSchema = "default-v2.1.0"
-
 // ParseSchemas fetches and returns the JSON content for a list of schema names,
-// including a default schema.
+// including the default schema.
 func ParseSchemas(schemas []string) (*SchemasResponse, error) {
 	// Include default schema and append provided schemas.
-	schemaNames := append([]string{DefaultSchema}, schemas...)
+	schemaNames := append(
+		[]string{config.Values.Library.DefaultSchema},
+		schemas...,
+	)
 	jsonSchemas := make([]string, len(schemaNames))
 	baseURL := fmt.Sprintf("%s/v2/schemas", config.Values.Library.InternalURL)
 
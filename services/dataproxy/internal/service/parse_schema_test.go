@@ -40,6 +40,7 @@ func TestParseSchemas(t *testing.T) {
 
 	// Mock the config to use the test server's URL.
 	config.Values.Library.InternalURL = mockServer.URL
+	config.Values.Library.DefaultSchema = "default-v2.1.0"
 
 	// Define test cases.
 	tests := []struct {
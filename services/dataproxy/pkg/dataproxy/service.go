@@ -16,6 +16,7 @@ import (
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/core"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/middleware/limiter"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/middleware/localizer"
 	midlogger "github.com/MurmurationsNetwork/MurmurationsServices/pkg/middleware/logger"
 	mongodb "github.com/MurmurationsNetwork/MurmurationsServices/pkg/mongo"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/dataproxy/config"
@@ -116,6 +117,7 @@ func (s *Service) middlewares() []gin.HandlerFunc {
 			Method: "GET",
 		}),
 		midlogger.NewLogger(),
+		localizer.NewLocalizer(),
 		s.cors(),
 	}
 }
@@ -124,12 +126,17 @@ func (s *Service) middlewares() []gin.HandlerFunc {
 func (s *Service) cors() gin.HandlerFunc {
 	// CORS for all origins, allowing:
 	// - GET and POST methods
-	// - Origin, Authorization and Content-Type header
+	// - Origin, Authorization, Content-Type and Accept-Language header
 	// - Credentials share
 	// - Preflight requests cached for 12 hours
 	return corslib.New(corslib.Config{
-		AllowOrigins:     []string{"*"},
-		AllowHeaders:     []string{"Origin", "Authorization", "Content-Type"},
+		AllowOrigins: []string{"*"},
+		AllowHeaders: []string{
+			"Origin",
+			"Authorization",
+			"Content-Type",
+			"Accept-Language",
+		},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
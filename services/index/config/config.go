@@ -20,10 +20,14 @@ type config struct {
 	Mongo mongoConf
 	// Elasticsearch configuration
 	ES esConf
+	// Search configuration
+	Search searchConf
 	// NATS configuration
 	Nats natsConf
 	// TTL configuration
 	TTL ttlConf
+	// Peering configuration
+	Peering peeringConf
 	// FeatureToggles
 	FeatureToggles map[string]bool
 }
@@ -54,6 +58,10 @@ type serverConf struct {
 type libraryConf struct {
 	// URL for the internal library
 	InternalURL string `env:"LIBRARY_URL,required"`
+	// Schema every profile is validated against
+	DefaultSchema string `env:"DEFAULT_SCHEMA" envDefault:"default-v2.1.0"`
+	// How long the versions of the schemas are cached for the notices
+	VersionsCacheTTL time.Duration `env:"SCHEMA_VERSIONS_CACHE_TTL" envDefault:"5m"`
 }
 
 // mongoConf contains MongoDB configuration details.
@@ -74,6 +82,12 @@ type esConf struct {
 	URL string `env:"ELASTICSEARCH_URL,required"`
 }
 
+// searchConf contains the configuration for the search index of the nodes.
+type searchConf struct {
+	// Backend storing the search index, "elasticsearch" or "mongo"
+	Backend string `env:"SEARCH_BACKEND,required"`
+}
+
 // natsConf contains the configuration for the NATS service.
 type natsConf struct {
 	// NATS cluster ID
@@ -82,6 +96,10 @@ type natsConf struct {
 	ClientID string `env:"NATS_CLIENT_ID,required"`
 	// NATS service URL
 	URL string `env:"NATS_URL,required"`
+	// Number of messages fetched at once
+	BatchSize int `env:"NATS_BATCH_SIZE,required"`
+	// How long a message may go unacknowledged before it is redelivered
+	AckWait time.Duration `env:"NATS_ACK_WAIT,required"`
 }
 
 // ttlConf contains the configuration for the TTL (Time To Live) settings.
@@ -89,3 +107,14 @@ type ttlConf struct {
 	// Time To Live for deleted items.
 	DeletedTTL int64 `env:"DELETED_TTL,required"`
 }
+
+// peeringConf contains the configuration of the federation with other indices.
+type peeringConf struct {
+	// Public URL of the index, recorded as the source of the nodes posted here
+	IndexURL string `env:"PEERING_INDEX_URL,required"`
+	// Bearer token of the requests registering and removing peers, the
+	// requests are refused when it is empty
+	Token string `env:"PEERING_TOKEN"`
+	// How often the nodes of the upstream peers are replicated
+	Interval time.Duration `env:"PEERING_INTERVAL,required"`
+}
@@ -1,7 +1,6 @@
 package event
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -10,6 +9,7 @@ import (
 
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/messaging"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/config"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/index"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/model"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/service"
@@ -23,12 +23,16 @@ type NodeHandler interface {
 
 // nodeHandler handles node-related events.
 type nodeHandler struct {
-	svc service.NodeService
+	svc    service.NodeService
+	events service.EventService
 }
 
 // NewNodeHandler creates a new handler for node-related events.
-func NewNodeHandler(nodeService service.NodeService) NodeHandler {
-	return &nodeHandler{svc: nodeService}
+func NewNodeHandler(
+	nodeService service.NodeService,
+	eventService service.EventService,
+) NodeHandler {
+	return &nodeHandler{svc: nodeService, events: eventService}
 }
 
 // Validated sets up a listener for validated node events and processes them.
@@ -37,6 +41,7 @@ func (handler *nodeHandler) Validated() error {
 		messaging.NodeValidated,
 		index.QueueGroup,
 		handler.processValidatedNode,
+		subscribeOptions()...,
 	)
 	if err != nil {
 		return fmt.Errorf(
@@ -56,6 +61,7 @@ func (handler *nodeHandler) ValidationFailed() error {
 		messaging.NodeValidationFailed,
 		index.QueueGroup,
 		handler.processInvalidNode,
+		subscribeOptions()...,
 	)
 	if err != nil {
 		return fmt.Errorf(
@@ -67,71 +73,125 @@ func (handler *nodeHandler) ValidationFailed() error {
 	return nil
 }
 
-// processValidatedNode handles the processing of validated nodes.
-func (handler *nodeHandler) processValidatedNode(msg *natsio.Msg) {
-	defer safeAcknowledgeMessage(msg)
+// subscribeOptions returns the configured consumer settings.
+func subscribeOptions() []messaging.SubscribeOption {
+	return []messaging.SubscribeOption{
+		messaging.WithBatchSize(config.Values.Nats.BatchSize),
+		messaging.WithAckWait(config.Values.Nats.AckWait),
+	}
+}
 
+// processValidatedNode handles the processing of validated nodes.
+func (handler *nodeHandler) processValidatedNode(msg *natsio.Msg) error {
 	var data messaging.NodeValidatedData
-	err := json.Unmarshal(msg.Data, &data)
+	envelope, err := messaging.Decode(msg, &data)
 	if err != nil {
-		logger.Error("Failed to unmarshal validated node data", err)
-		return
-	}
-
-	if err = handler.svc.SetNodeValid(&model.Node{
-		ProfileURL:  data.ProfileURL,
-		ProfileHash: &data.ProfileHash,
-		ProfileStr:  data.ProfileStr,
-		LastUpdated: &data.LastUpdated,
-		Version:     &data.Version,
-		Expires:     data.Expires,
-	}); err != nil {
-		logger.Error(
-			"Failed to set node valid",
-			err,
-			zap.String("ProfileURL", data.ProfileURL),
-			zap.String("ProfileStr", data.ProfileStr),
+		return messaging.Permanent(
+			fmt.Errorf("failed to unmarshal validated node data: %w", err),
 		)
 	}
+
+	return handler.processOnce(envelope, func() error {
+		err := handler.svc.SetNodeValid(&model.Node{
+			ProfileURL:  data.ProfileURL,
+			ProfileHash: &data.ProfileHash,
+			ProfileStr:  data.ProfileStr,
+			LastUpdated: &data.LastUpdated,
+			Version:     &data.Version,
+			Expires:     data.Expires,
+			Notices:     notices(data.Notices),
+			LastEvent:   lastEvent(envelope),
+		})
+		if err != nil && !errors.As(err, &index.VersionConflictError{}) {
+			logger.Error(
+				"Failed to set node valid",
+				err,
+				zap.String("ProfileURL", data.ProfileURL),
+				zap.String("ProfileStr", data.ProfileStr),
+			)
+		}
+		return err
+	})
 }
 
 // processInvalidNode handles the processing of invalid nodes.
-func (handler *nodeHandler) processInvalidNode(msg *natsio.Msg) {
-	defer safeAcknowledgeMessage(msg)
-
+func (handler *nodeHandler) processInvalidNode(msg *natsio.Msg) error {
 	var data messaging.NodeValidationFailedData
-	err := json.Unmarshal(msg.Data, &data)
+	envelope, err := messaging.Decode(msg, &data)
 	if err != nil {
-		logger.Error("Failed to unmarshal invalid node data", err)
-		return
+		return messaging.Permanent(
+			fmt.Errorf("failed to unmarshal invalid node data: %w", err),
+		)
 	}
 
-	if err = handler.svc.SetNodeInvalid(&model.Node{
-		ProfileURL:     data.ProfileURL,
-		FailureReasons: data.FailureReasons,
-		Version:        &data.Version,
-	}); err != nil {
-		logger.Error(
-			"Failed to set node invalid",
-			err,
-			zap.String("ProfileURL", data.ProfileURL),
-		)
+	return handler.processOnce(envelope, func() error {
+		err := handler.svc.SetNodeInvalid(&model.Node{
+			ProfileURL:     data.ProfileURL,
+			FailureReasons: data.FailureReasons,
+			Version:        &data.Version,
+			LastEvent:      lastEvent(envelope),
+		})
+		if err != nil && !errors.As(err, &index.VersionConflictError{}) {
+			logger.Error(
+				"Failed to set node invalid",
+				err,
+				zap.String("ProfileURL", data.ProfileURL),
+			)
+		}
+		return err
+	})
+}
+
+// lastEvent returns the event to record on the node it changes. Events
+// published without an envelope have no ID and can't be resumed.
+func lastEvent(envelope *messaging.Envelope) *model.NodeEvent {
+	if envelope.ID == "" {
+		return nil
 	}
+	return &model.NodeEvent{ID: envelope.ID}
 }
 
-// safeAcknowledgeMessage safely acknowledges a message and should be called with
-// defer. It recovers from any panics that occurred during message processing and
-// then acknowledges the message.
-func safeAcknowledgeMessage(msg *natsio.Msg) {
-	if err := recover(); err != nil {
-		logger.Error(
-			"Panic occurred during message processing",
-			errors.New("panic"),
-			zap.Any("error", err),
+// notices returns the notices to store on the node. They are never nil, so
+// that the notices of the previous version of the node are cleared.
+func notices(data []string) *[]string {
+	notices := append([]string{}, data...)
+	return &notices
+}
+
+// processOnce runs process unless the event was already processed. The events
+// which were processed before, or whose node has changed since, are rejected.
+// An event which failed after changing its node isn't stale, the node records
+// it and its processing resumes.
+func (handler *nodeHandler) processOnce(
+	envelope *messaging.Envelope,
+	process func() error,
+) error {
+	processed, err := handler.events.IsProcessed(envelope)
+	if err != nil {
+		return err
+	}
+	if processed {
+		return messaging.Reject(
+			fmt.Errorf("event %s was already processed", envelope.ID),
 		)
 	}
 
-	if err := msg.Ack(); err != nil {
-		logger.Error("Error acknowledging message", err)
+	err = process()
+	if errors.As(err, &index.VersionConflictError{}) {
+		return messaging.Reject(fmt.Errorf("stale event: %w", err))
+	}
+	if err != nil {
+		return err
+	}
+
+	// The event was processed, failing to record it only means a redelivery
+	// finds the node already changed by it.
+	if err := handler.events.MarkProcessed(envelope); err != nil {
+		logger.Error(
+			"Failed to record processed event",
+			err,
+			zap.String("event_id", envelope.ID),
+		)
 	}
+	return nil
 }
@@ -0,0 +1,146 @@
+package event_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/messaging"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/controller/event"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/index"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/service"
+)
+
+// fakeNodeService keeps the version of each node, like the repository does.
+type fakeNodeService struct {
+	service.NodeService
+
+	mu       sync.Mutex
+	versions map[string]int32
+	valid    []string
+	notices  map[string][]string
+}
+
+func (s *fakeNodeService) SetNodeValid(node *model.Node) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.versions[node.ProfileURL] != *node.Version {
+		return index.VersionConflictError{Version: *node.Version}
+	}
+	s.versions[node.ProfileURL]++
+	s.valid = append(s.valid, node.ProfileURL)
+	if s.notices != nil {
+		s.notices[node.ProfileURL] = *node.Notices
+	}
+	return nil
+}
+
+type fakeEventService struct {
+	mu        sync.Mutex
+	processed map[string]bool
+}
+
+func (s *fakeEventService) IsProcessed(
+	envelope *messaging.Envelope,
+) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.processed[envelope.ID], nil
+}
+
+func (s *fakeEventService) MarkProcessed(envelope *messaging.Envelope) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.processed[envelope.ID] = true
+	return nil
+}
+
+func TestValidated(t *testing.T) {
+	bus := messaging.NewMemoryBus()
+	messaging.SetBus(bus)
+	t.Cleanup(func() {
+		bus.Close()
+		messaging.SetBus(nil)
+	})
+
+	nodes := &fakeNodeService{versions: map[string]int32{
+		"https://ic3.dev/a.json": 1,
+	}}
+	events := &fakeEventService{processed: map[string]bool{}}
+	require.NoError(t, event.NewNodeHandler(nodes, events).Validated())
+
+	validated, err := messaging.NewEnvelope(
+		messaging.NodeValidated,
+		messaging.NodeValidatedData{
+			ProfileURL: "https://ic3.dev/a.json",
+			Version:    1,
+		},
+	)
+	require.NoError(t, err)
+	stale, err := messaging.NewEnvelope(
+		messaging.NodeValidated,
+		messaging.NodeValidatedData{
+			ProfileURL: "https://ic3.dev/a.json",
+			Version:    1,
+		},
+	)
+	require.NoError(t, err)
+
+	// The event is delivered twice, then another one for the same version.
+	require.NoError(t, messaging.Publish(messaging.NodeValidated, validated))
+	require.NoError(t, messaging.Publish(messaging.NodeValidated, validated))
+	require.NoError(t, messaging.Publish(messaging.NodeValidated, stale))
+	bus.Flush()
+
+	require.Equal(t, []string{"https://ic3.dev/a.json"}, nodes.valid)
+	require.True(t, events.processed[validated.ID])
+	require.False(t, events.processed[stale.ID])
+	// Rejected events are not dead-lettered.
+	require.Empty(t, bus.Pending(messaging.DeadLetter))
+}
+
+func TestValidated_Notices(t *testing.T) {
+	bus := messaging.NewMemoryBus()
+	messaging.SetBus(bus)
+	t.Cleanup(func() {
+		bus.Close()
+		messaging.SetBus(nil)
+	})
+
+	nodes := &fakeNodeService{
+		versions: map[string]int32{
+			"https://ic3.dev/a.json": 1,
+			"https://ic3.dev/b.json": 1,
+		},
+		notices: map[string][]string{},
+	}
+	events := &fakeEventService{processed: map[string]bool{}}
+	require.NoError(t, event.NewNodeHandler(nodes, events).Validated())
+
+	sunset := "The `test_schema-v1.0.0` schema was sunset on " +
+		"2023-11-14T22:13:20Z, only the profiles already posted with it " +
+		"are accepted."
+	require.NoError(t, messaging.Publish(
+		messaging.NodeValidated,
+		messaging.NodeValidatedData{
+			ProfileURL: "https://ic3.dev/a.json",
+			Version:    1,
+			Notices:    []string{sunset},
+		},
+	))
+	require.NoError(t, messaging.Publish(
+		messaging.NodeValidated,
+		messaging.NodeValidatedData{
+			ProfileURL: "https://ic3.dev/b.json",
+			Version:    1,
+		},
+	))
+	bus.Flush()
+
+	require.Equal(t, []string{sunset}, nodes.notices["https://ic3.dev/a.json"])
+	// The notices of the previous version of the node are cleared.
+	require.NotNil(t, nodes.notices["https://ic3.dev/b.json"])
+	require.Empty(t, nodes.notices["https://ic3.dev/b.json"])
+}
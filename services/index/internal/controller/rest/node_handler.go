@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
@@ -21,7 +22,7 @@ import (
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/config"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/index"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/model"
-	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/es"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/search"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/service"
 )
 
@@ -44,18 +45,30 @@ type NodeHandler interface {
 	Export(c *gin.Context)
 }
 
+// versionsCacheSize is the number of families of schemas whose versions are
+// cached.
+const versionsCacheSize = 500
+
 type nodeHandler struct {
 	svc service.NodeService
+	// versions caches the versions of the schemas, looked up by
+	// /v2/validate for the newer version notices.
+	versions *profilevalidator.SchemaCache
 }
 
 func NewNodeHandler(nodeService service.NodeService) NodeHandler {
 	return &nodeHandler{
 		svc: nodeService,
+		versions: profilevalidator.NewSchemaCache(
+			config.Values.Library.VersionsCacheTTL,
+			versionsCacheSize,
+		),
 	}
 }
 
 var validationFields = []string{
 	"name",
+	"lang",
 	"schema",
 	"last_updated",
 	"lat",
@@ -187,7 +200,7 @@ func (handler *nodeHandler) AddSync(c *gin.Context) {
 				ToGetNodeResponse(nodeInfo),
 				nil,
 				nil,
-				nil,
+				noticesMeta(nodeInfo),
 			)
 			c.JSON(http.StatusOK, res)
 			return
@@ -237,10 +250,26 @@ func (handler *nodeHandler) Get(c *gin.Context) {
 		return
 	}
 
-	res := jsonapi.Response(ToGetNodeResponse(node), nil, nil, nil)
+	res := jsonapi.Response(
+		ToGetNodeResponse(node),
+		nil,
+		nil,
+		noticesMeta(node),
+	)
 	c.JSON(http.StatusOK, res)
 }
 
+// noticesMeta returns the meta holding the notices of the node, or nil when
+// the node has none.
+func noticesMeta(node *model.Node) *jsonapi.Meta {
+	if node.Notices == nil || len(*node.Notices) == 0 {
+		return nil
+	}
+	meta := jsonapi.NewMeta("", "", "")
+	meta.Notices = *node.Notices
+	return meta
+}
+
 func (handler *nodeHandler) Search(c *gin.Context) {
 	errs := checkInputIsValid(c, validationFields, "GET")
 	if errs != nil {
@@ -249,7 +278,7 @@ func (handler *nodeHandler) Search(c *gin.Context) {
 		return
 	}
 
-	var esQuery es.Query
+	var esQuery search.Query
 	if err := c.ShouldBindQuery(&esQuery); err != nil {
 		errs = jsonapi.NewError(
 			[]string{"JSON Error"},
@@ -262,6 +291,18 @@ func (handler *nodeHandler) Search(c *gin.Context) {
 		return
 	}
 
+	if errs = checkLangIsValid(esQuery.Lang); errs != nil {
+		res := jsonapi.Response(nil, errs, nil, nil)
+		c.JSON(errs[0].Status, res)
+		return
+	}
+
+	if errs = checkRangeIsValid(esQuery.Range); errs != nil {
+		res := jsonapi.Response(nil, errs, nil, nil)
+		c.JSON(errs[0].Status, res)
+		return
+	}
+
 	if esQuery.Page*esQuery.PageSize > 10000 {
 		errMsgs := []string{"Max Results Exceeded"}
 		detailMsgs := []string{
@@ -420,11 +461,18 @@ func (handler *nodeHandler) Validate(c *gin.Context) {
 	}
 
 	// Validate against the default schema.
-	linkedSchemas = append(linkedSchemas, "default-v2.1.0")
+	linkedSchemas = append(
+		linkedSchemas,
+		config.Values.Library.DefaultSchema,
+	)
 
 	// Validate against schemes specify inside the profile data.
 	validator, err := profilevalidator.NewBuilder().
 		WithURLSchemas(config.Values.Library.InternalURL, linkedSchemas).
+		WithNewerVersionNotices(
+			config.Values.Library.InternalURL,
+			handler.versions,
+		).
 		WithStrProfile(string(jsonString)).
 		Build()
 	if err != nil {
@@ -445,6 +493,9 @@ func (handler *nodeHandler) Validate(c *gin.Context) {
 	}
 
 	result := validator.Validate()
+	// The profile is validated as a new one, which can't be posted with a
+	// schema after its sunset.
+	result.RejectSunset()
 	if !result.Valid {
 		message := "Failed to validate against schemas: " + strings.Join(
 			result.ErrorMessages,
@@ -499,6 +550,7 @@ func (handler *nodeHandler) Validate(c *gin.Context) {
 		"",
 		"",
 	)
+	meta.Notices = result.Notices
 	res := jsonapi.Response(nil, nil, nil, meta)
 	c.JSON(http.StatusOK, res)
 }
@@ -514,7 +566,7 @@ func (handler *nodeHandler) Export(c *gin.Context) {
 		return
 	}
 
-	var esQuery es.BlockQuery
+	var esQuery search.BlockQuery
 	if err := c.ShouldBindJSON(&esQuery); err != nil {
 		fmt.Println(err)
 		errs = jsonapi.NewError(
@@ -577,7 +629,7 @@ func (handler *nodeHandler) GetNodes(c *gin.Context) {
 		return
 	}
 
-	var esQuery es.Query
+	var esQuery search.Query
 	if err := c.ShouldBindQuery(&esQuery); err != nil {
 		errs = jsonapi.NewError(
 			[]string{"JSON Error"},
@@ -590,6 +642,18 @@ func (handler *nodeHandler) GetNodes(c *gin.Context) {
 		return
 	}
 
+	if errs = checkLangIsValid(esQuery.Lang); errs != nil {
+		res := jsonapi.Response(nil, errs, nil, nil)
+		c.JSON(errs[0].Status, res)
+		return
+	}
+
+	if errs = checkRangeIsValid(esQuery.Range); errs != nil {
+		res := jsonapi.Response(nil, errs, nil, nil)
+		c.JSON(errs[0].Status, res)
+		return
+	}
+
 	if esQuery.Page*esQuery.PageSize > 10000 {
 		msg := "No more than 10,000 results can be returned. " +
 			"Refine your query so it will return less " +
@@ -664,6 +728,56 @@ func getLinkedSchemas(data interface{}) ([]string, bool) {
 	return linkedSchemas, true
 }
 
+// checkLangIsValid makes sure the `lang` query parameter, if any, is one of
+// the languages that can be searched.
+func checkLangIsValid(lang *string) []jsonapi.Error {
+	if lang == nil {
+		return nil
+	}
+	if _, ok := model.SearchLanguages[*lang]; ok {
+		return nil
+	}
+
+	languages := make([]string, 0, len(model.SearchLanguages))
+	for language := range model.SearchLanguages {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+
+	return jsonapi.NewError(
+		[]string{"Invalid Query Parameter"},
+		[]string{
+			fmt.Sprintf(
+				"The `lang` query parameter must be one of: %s.",
+				strings.Join(languages, ", "),
+			),
+		},
+		[][]string{{"parameter", "lang"}},
+		[]int{http.StatusBadRequest},
+	)
+}
+
+// checkRangeIsValid rejects the distances the search backends can't read,
+// such as "10 furlongs".
+func checkRangeIsValid(distance *string) []jsonapi.Error {
+	if distance == nil {
+		return nil
+	}
+	if _, err := search.ParseDistance(*distance); err == nil {
+		return nil
+	}
+
+	return jsonapi.NewError(
+		[]string{"Invalid Query Parameter"},
+		[]string{
+			"The `range` query parameter must be a distance, such as " +
+				"`10km` or `5mi`.",
+		},
+		[][]string{{"parameter", "range"}},
+		[]int{http.StatusBadRequest},
+	)
+}
+
 func checkInputIsValid(
 	c *gin.Context,
 	fields []string,
@@ -0,0 +1,132 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/jsonapi"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/index"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/service"
+)
+
+type PeerHandler interface {
+	// Add registers a peer.
+	Add(c *gin.Context)
+	// GetAll lists the peers.
+	GetAll(c *gin.Context)
+	// Delete removes a peer.
+	Delete(c *gin.Context)
+}
+
+type peerHandler struct {
+	svc service.PeerService
+}
+
+func NewPeerHandler(peerService service.PeerService) PeerHandler {
+	return &peerHandler{
+		svc: peerService,
+	}
+}
+
+func (handler *peerHandler) Add(c *gin.Context) {
+	var req PeerCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors := jsonapi.NewError(
+			[]string{"JSON Error"},
+			[]string{"The JSON document submitted could not be parsed."},
+			nil,
+			[]int{http.StatusBadRequest},
+		)
+		res := jsonapi.Response(nil, errors, nil, nil)
+		c.JSON(errors[0].Status, res)
+		return
+	}
+
+	peer, err := handler.svc.AddPeer(&model.Peer{
+		URL:       req.URL,
+		Direction: req.Direction,
+	})
+	if err != nil {
+		handlePeerErrors(c, err, "Error while trying to add a peer.")
+		return
+	}
+
+	res := jsonapi.Response(ToPeerResponse(peer), nil, nil, nil)
+	c.JSON(http.StatusOK, res)
+}
+
+func (handler *peerHandler) GetAll(c *gin.Context) {
+	peers, err := handler.svc.GetPeers()
+	if err != nil {
+		handlePeerErrors(c, err, "Error while trying to get the peers.")
+		return
+	}
+
+	data := make([]PeerResponse, len(peers))
+	for i, peer := range peers {
+		data[i] = ToPeerResponse(peer)
+	}
+	res := jsonapi.Response(data, nil, nil, nil)
+	c.JSON(http.StatusOK, res)
+}
+
+func (handler *peerHandler) Delete(c *gin.Context) {
+	peerID := c.Param("peerID")
+
+	err := handler.svc.DeletePeer(peerID)
+	if err != nil {
+		handlePeerErrors(c, err, "Error while trying to delete a peer.")
+		return
+	}
+
+	meta := jsonapi.NewMeta("The peer was removed from the index.", "", "")
+	res := jsonapi.Response(nil, nil, nil, meta)
+	c.JSON(http.StatusOK, res)
+}
+
+func handlePeerErrors(c *gin.Context, err error, detail string) {
+	var validationError index.ValidationError
+	var notFoundError index.NotFoundError
+	var databaseError index.DatabaseError
+	var jsonErr []jsonapi.Error
+
+	switch {
+	case errors.As(err, &validationError):
+		jsonErr = jsonapi.NewError(
+			[]string{"Validation Error"},
+			[]string{validationError.Reason},
+			nil,
+			[]int{http.StatusBadRequest},
+		)
+	case errors.As(err, &notFoundError):
+		jsonErr = jsonapi.NewError(
+			[]string{"Peer Not Found"},
+			[]string{"Could not locate the peer in the Index."},
+			nil,
+			[]int{http.StatusNotFound},
+		)
+	case errors.As(err, &databaseError):
+		logger.Error("Failed to manage the peers", err)
+		jsonErr = jsonapi.NewError(
+			[]string{databaseError.Message},
+			[]string{detail},
+			nil,
+			[]int{http.StatusInternalServerError},
+		)
+	default:
+		logger.Error("Failed to manage the peers", err)
+		jsonErr = jsonapi.NewError(
+			[]string{"Unknown Error"},
+			[]string{"An unexpected error occurred. Please try again later."},
+			nil,
+			[]int{http.StatusInternalServerError},
+		)
+	}
+
+	res := jsonapi.Response(nil, jsonErr, nil, nil)
+	c.JSON(jsonErr[0].Status, res)
+}
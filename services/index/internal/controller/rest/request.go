@@ -69,3 +69,9 @@ func toDTO(node *model.Node) *NodeCreateRequest {
 		FailureReasons: node.FailureReasons,
 	}
 }
+
+// PeerCreateRequest is the request registering a peer.
+type PeerCreateRequest struct {
+	URL       string `json:"url"`
+	Direction string `json:"direction"`
+}
@@ -33,6 +33,15 @@ type SearchNodeResponse struct {
 	LastUpdated *int64 `json:"last_updated,omitempty"`
 }
 
+// PeerResponse struct is used to format the peers.
+type PeerResponse struct {
+	ID         string `json:"peer_id"`
+	URL        string `json:"url"`
+	Direction  string `json:"direction"`
+	LastSynced *int64 `json:"last_synced,omitempty"`
+	LastError  string `json:"last_error,omitempty"`
+}
+
 // ToAddNodeResponse converts the node model to AddNodeResponse format.
 func ToAddNodeResponse(node *model.Node) interface{} {
 	return AddNodeResponse{
@@ -72,3 +81,14 @@ func ToSearchNodeResponse(nodes model.Nodes) interface{} {
 	}
 	return Respond{Data: data}
 }
+
+// ToPeerResponse converts the peer model to PeerResponse format.
+func ToPeerResponse(peer *model.Peer) PeerResponse {
+	return PeerResponse{
+		ID:         peer.ID,
+		URL:        peer.URL,
+		Direction:  peer.Direction,
+		LastSynced: peer.LastSynced,
+		LastError:  peer.LastError,
+	}
+}
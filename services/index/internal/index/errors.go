@@ -56,6 +56,25 @@ func (e ValidationError) Error() string {
 	return fmt.Sprintf("Validation failed on field '%s': %s", e.Field, e.Reason)
 }
 
+// VersionConflictError struct represents a custom error type for updates of a
+// node which changed since the version they were based on, such as an event
+// delivered late or twice.
+type VersionConflictError struct {
+	// ID of the node.
+	NodeID string
+	// Version the update expected.
+	Version int32
+}
+
+// Error conforms to go conventions.
+func (e VersionConflictError) Error() string {
+	return fmt.Sprintf(
+		"Node '%s' is no longer at version %d",
+		e.NodeID,
+		e.Version,
+	)
+}
+
 const (
 	// HTTP request failure.
 	ErrorHTTPRequestFailed = 1
@@ -77,3 +77,8 @@ func TestDeleteNodeError(t *testing.T) {
 		"DeleteNodeError.Error() does not match expected",
 	)
 }
+
+func TestVersionConflictError(t *testing.T) {
+	err := index.VersionConflictError{NodeID: "abc", Version: 3}
+	require.Equal(t, "Node 'abc' is no longer at version 3", err.Error())
+}
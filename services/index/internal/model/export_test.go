@@ -36,3 +36,9 @@ func (s *TestProfile) FilterTags() error {
 func (s *TestProfile) SetDefaultStatus() {
 	s.setDefaultStatus()
 }
+
+// NormalizeLocalizedFields is a wrapper around the unexported
+// normalizeLocalizedFields method in the Profile type.
+func (s *TestProfile) NormalizeLocalizedFields() {
+	s.normalizeLocalizedFields()
+}
@@ -33,12 +33,44 @@ type Node struct {
 	// CreatedAt stores the Unix timestamp when the node was created.
 	CreatedAt int64 `bson:"createdAt,omitempty"`
 
-	// ProfileStr stores the node's profile in string format.
-	// It won't be stored in MongoDB.
-	ProfileStr string `bson:"-"`
+	// ProfileStr stores the node's profile in string format, as validated.
+	// It is kept so that the search index can be rebuilt from MongoDB.
+	ProfileStr string `bson:"profile_str,omitempty"`
 
 	// Expires stores the Unix timestamp when the node expires.
 	Expires *int64 `bson:"expires,omitempty"`
+
+	// SourceIndex is the URL of the index the node was posted to, when it
+	// was replicated from a peer. It is empty for the nodes posted here.
+	SourceIndex string `bson:"source_index,omitempty"`
+
+	// Notices stores the notices about the linked schemas of the node, such
+	// as the sunset of a schema it is still accepted with.
+	Notices *[]string `bson:"notices,omitempty"`
+
+	// LastEvent is the event which made the last change to the node.
+	LastEvent *NodeEvent `bson:"last_event,omitempty"`
+}
+
+// NodeEvent records the event which changed a node, so that the processing of
+// the event can resume when it is delivered again after a partial failure.
+type NodeEvent struct {
+	// ID is the ID of the event.
+	ID string `bson:"id"`
+	// Version is the version of the node after the change. Once the node is
+	// changed by anything else, its version no longer matches.
+	Version int32 `bson:"version"`
+}
+
+// ResumesEvent reports whether the node was last changed by the event of the
+// given node, and not changed since. The event can then carry on from the
+// stored version instead of being stale.
+func (n *Node) ResumesEvent(stored *Node) bool {
+	return n.LastEvent != nil && n.LastEvent.ID != "" &&
+		stored != nil && stored.LastEvent != nil &&
+		stored.LastEvent.ID == n.LastEvent.ID &&
+		stored.Version != nil &&
+		*stored.Version == stored.LastEvent.Version
 }
 
 func (n *Node) SetStatusValidated() {
@@ -0,0 +1,28 @@
+package model
+
+// Peer is another index of the federation.
+type Peer struct {
+	// ID is the hash of the URL of the peer.
+	ID string `bson:"_id,omitempty"`
+
+	// URL is the base URL of the peer, such as
+	// https://index.murmurations.network.
+	URL string `bson:"url"`
+
+	// Direction is upstream when the nodes of the peer are replicated into
+	// the index, downstream when the peer replicates the index.
+	Direction string `bson:"direction"`
+
+	// Cursor is the search_after cursor of the last node replicated from the
+	// peer.
+	Cursor []interface{} `bson:"cursor,omitempty"`
+
+	// LastSynced stores the Unix timestamp of the last replication.
+	LastSynced *int64 `bson:"last_synced,omitempty"`
+
+	// LastError is the error of the last replication, if it failed.
+	LastError string `bson:"last_error,omitempty"`
+
+	// CreatedAt stores the Unix timestamp when the peer was registered.
+	CreatedAt int64 `bson:"createdAt,omitempty"`
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"go.uber.org/zap"
+	"golang.org/x/text/language"
 
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/countries"
@@ -27,6 +28,7 @@ var AllowedFields = map[string]bool{
 	"linked_schemas": true,
 	"locality":       true,
 	"name":           true,
+	"name_i18n":      true,
 	"primary_url":    true,
 	"profile_url":    true,
 	"region":         true,
@@ -35,6 +37,21 @@ var AllowedFields = map[string]bool{
 	"expires":        true,
 }
 
+// LocalizedFields lists the fields holding language-tagged variants of another
+// field, e.g. `"name_i18n": {"fr": "...", "en": "..."}`.
+var LocalizedFields = []string{"name_i18n"}
+
+// SearchLanguages maps the languages with a dedicated Elasticsearch analyzer
+// to that analyzer. Variants in other languages are indexed but can't be
+// searched by language.
+var SearchLanguages = map[string]string{
+	"de": "german",
+	"en": "english",
+	"es": "spanish",
+	"fr": "french",
+	"pt": "portuguese",
+}
+
 // Profile represents the profile data for a node.
 type Profile struct {
 	// Original profile string.
@@ -86,6 +103,8 @@ func (p *Profile) Update(
 		return err
 	}
 
+	p.normalizeLocalizedFields()
+
 	p.setDefaultStatus()
 
 	return nil
@@ -188,6 +207,44 @@ func (p *Profile) filterTags() error {
 	return nil
 }
 
+// normalizeLocalizedFields keys the language-tagged variants by their base
+// language (e.g. "fr-CA" becomes "fr") and drops the variants that are not
+// non-empty strings or whose key isn't a valid language tag.
+func (p *Profile) normalizeLocalizedFields() {
+	for _, field := range LocalizedFields {
+		variants, ok := p.json[field].(map[string]interface{})
+		if !ok {
+			delete(p.json, field)
+			continue
+		}
+
+		normalized := make(map[string]interface{})
+		for key, value := range variants {
+			text, ok := value.(string)
+			if !ok || strings.TrimSpace(text) == "" {
+				continue
+			}
+			tag, err := language.Parse(key)
+			if err != nil {
+				continue
+			}
+			base, _ := tag.Base()
+			// An exact base language key wins over a regional variant.
+			if _, exists := normalized[base.String()]; exists &&
+				key != base.String() {
+				continue
+			}
+			normalized[base.String()] = text
+		}
+
+		if len(normalized) == 0 {
+			delete(p.json, field)
+			continue
+		}
+		p.json[field] = normalized
+	}
+}
+
 // setDefaultStatus sets the default status of the profile.
 func (p *Profile) setDefaultStatus() {
 	p.json["status"] = constant.NodeStatus.Posted
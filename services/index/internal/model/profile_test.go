@@ -234,3 +234,54 @@ func TestSetDefaultStatus(t *testing.T) {
 		profile.GetJSON(),
 	)
 }
+
+func TestNormalizeLocalizedFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected map[string]interface{}
+	}{
+		{
+			name:  "variants keyed by base language",
+			input: `{"name_i18n": {"fr-CA": "Bonjour", "en": "Hello"}}`,
+			expected: map[string]interface{}{
+				"name_i18n": map[string]interface{}{
+					"fr": "Bonjour",
+					"en": "Hello",
+				},
+			},
+		},
+		{
+			name:  "exact language wins over regional variant",
+			input: `{"name_i18n": {"pt-BR": "Olá", "pt": "Olá!"}}`,
+			expected: map[string]interface{}{
+				"name_i18n": map[string]interface{}{"pt": "Olá!"},
+			},
+		},
+		{
+			name:  "invalid variants are dropped",
+			input: `{"name_i18n": {"not a tag": "x", "de": 1, "es": " ", "en": "Hi"}}`,
+			expected: map[string]interface{}{
+				"name_i18n": map[string]interface{}{"en": "Hi"},
+			},
+		},
+		{
+			name:     "no valid variants",
+			input:    `{"name_i18n": {"de": ""}}`,
+			expected: map[string]interface{}{},
+		},
+		{
+			name:     "not an object",
+			input:    `{"name_i18n": "Hello"}`,
+			expected: map[string]interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := model.NewTestProfile(tt.input)
+			profile.NormalizeLocalizedFields()
+			require.Equal(t, tt.expected, profile.GetJSON())
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package es
+
+import (
+	"encoding/json"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/elastic"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/index"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/search"
+)
+
+// IndexRepository builds a new version of an index next to the live one and
+// swaps it in once it is complete.
+type IndexRepository interface {
+	// Live returns the concrete indices currently served under the alias.
+	Live() ([]string, error)
+	// Exists tells whether the new version has already been created.
+	Exists() (bool, error)
+	// Create creates the new version, without serving it.
+	Create() error
+	// Delete deletes a concrete index.
+	Delete(name string) error
+	// GetLiveDocuments returns the documents of the live index by ID.
+	GetLiveDocuments(ids []string) (map[string]search.QueryResult, error)
+	// IndexDocuments writes the documents into the new version.
+	IndexDocuments(docs []elastic.Document) error
+	// DeleteDocument removes a document from the new version.
+	DeleteDocument(id string) error
+	// Publish points the alias at the new version.
+	Publish() error
+}
+
+// NewIndexRepository returns an IndexRepository building the target version.
+func NewIndexRepository(target elastic.Index) IndexRepository {
+	return &indexRepository{target: target}
+}
+
+type indexRepository struct {
+	target elastic.Index
+}
+
+func (r *indexRepository) Live() ([]string, error) {
+	indices, err := elastic.Client.IndicesByAlias(r.target.Name)
+	if err != nil {
+		return nil, index.DatabaseError{Err: err}
+	}
+	return indices, nil
+}
+
+func (r *indexRepository) Exists() (bool, error) {
+	indices, err := elastic.Client.IndicesByAlias(r.target.VersionedName())
+	if err != nil {
+		return false, index.DatabaseError{Err: err}
+	}
+	return len(indices) > 0, nil
+}
+
+func (r *indexRepository) Create() error {
+	if err := elastic.Client.CreateIndex(r.target); err != nil {
+		return index.DatabaseError{Err: err}
+	}
+	return nil
+}
+
+func (r *indexRepository) Delete(name string) error {
+	if err := elastic.Client.DeleteIndex(name); err != nil {
+		return index.DatabaseError{Err: err}
+	}
+	return nil
+}
+
+func (r *indexRepository) GetLiveDocuments(
+	ids []string,
+) (map[string]search.QueryResult, error) {
+	sources, err := elastic.Client.GetSources(r.target.Name, ids)
+	if err != nil {
+		return nil, index.DatabaseError{Err: err}
+	}
+
+	docs := make(map[string]search.QueryResult, len(sources))
+	for id, source := range sources {
+		var doc search.QueryResult
+		if err := json.Unmarshal(source, &doc); err != nil {
+			return nil, index.DatabaseError{Err: err}
+		}
+		docs[id] = doc
+	}
+	return docs, nil
+}
+
+func (r *indexRepository) IndexDocuments(docs []elastic.Document) error {
+	err := elastic.Client.BulkIndex(r.target.VersionedName(), docs)
+	if err != nil {
+		return index.DatabaseError{Err: err}
+	}
+	return nil
+}
+
+func (r *indexRepository) DeleteDocument(id string) error {
+	err := elastic.Client.Delete(r.target.VersionedName(), id)
+	if err != nil {
+		return index.DatabaseError{Err: err}
+	}
+	return nil
+}
+
+func (r *indexRepository) Publish() error {
+	if err := elastic.Client.Refresh(r.target.VersionedName()); err != nil {
+		return index.DatabaseError{Err: err}
+	}
+	err := elastic.Client.SwapAlias(r.target.Name, r.target.VersionedName())
+	if err != nil {
+		return index.DatabaseError{Err: err}
+	}
+	return nil
+}
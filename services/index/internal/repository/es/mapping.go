@@ -0,0 +1,109 @@
+package es
+
+import (
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/elastic"
+)
+
+// NodeIndex is the index holding the searchable part of the node profiles.
+//
+// Bump Version whenever the mappings change and run the reindex command: it
+// builds the new version next to the live one and swaps the alias once done.
+var NodeIndex = elastic.Index{
+	Name:    constant.ESIndex.Node,
+	Version: 3,
+	Body: `{
+		"mappings": {
+			"dynamic": "false",
+			"_source": {
+				"includes": [
+					"name",
+					"name_i18n",
+					"geolocation",
+					"last_updated",
+					"linked_schemas",
+					"country",
+					"locality",
+					"region",
+					"profile_url",
+					"status",
+					"tags",
+					"primary_url",
+					"expires",
+					"profile_hash",
+					"source_index"
+				]
+			},
+			"properties": {
+				"name": {
+					"type": "text"
+				},
+				"name_i18n": {
+					"properties": {
+						"de": {
+							"type": "text",
+							"analyzer": "german"
+						},
+						"en": {
+							"type": "text",
+							"analyzer": "english"
+						},
+						"es": {
+							"type": "text",
+							"analyzer": "spanish"
+						},
+						"fr": {
+							"type": "text",
+							"analyzer": "french"
+						},
+						"pt": {
+							"type": "text",
+							"analyzer": "portuguese"
+						}
+					}
+				},
+				"geolocation": {
+					"type": "geo_point"
+				},
+				"last_updated": {
+					"type": "date",
+					"format": "epoch_second"
+				},
+				"linked_schemas": {
+					"type": "keyword"
+				},
+				"country": {
+					"type": "text"
+				},
+				"locality": {
+					"type": "text"
+				},
+				"region": {
+					"type": "text"
+				},
+				"profile_url": {
+					"type": "keyword"
+				},
+				"status": {
+					"type": "keyword"
+				},
+				"tags": {
+					"type": "text"
+				},
+				"primary_url": {
+					"type": "keyword"
+				},
+				"expires": {
+					"type": "date",
+					"format": "epoch_second"
+				},
+				"profile_hash": {
+					"type": "keyword"
+				},
+				"source_index": {
+					"type": "keyword"
+				}
+			}
+		}
+	}`,
+}
@@ -5,21 +5,14 @@ import (
 
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/elastic"
-	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/pagination"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/index"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/search"
 )
 
-type NodeRepository interface {
-	IndexByID(id string, json interface{}) error
-	GetNodes(q *Query) (*MapQueryResults, error)
-	Search(q *Query) (*QueryResults, error)
-	DeleteByID(id string) error
-	SoftDelete(node *model.Node) error
-	Export(q *BlockQuery) (*BlockQueryResults, error)
-}
-
-func NewNodeRepository() NodeRepository {
+// NewNodeRepository returns a search.NodeRepository storing the profiles in
+// Elasticsearch.
+func NewNodeRepository() search.NodeRepository {
 	return &nodeRepository{}
 }
 
@@ -27,16 +20,20 @@ type nodeRepository struct {
 }
 
 func (r *nodeRepository) IndexByID(id string, json interface{}) error {
-	_, err := elastic.Client.IndexWithID(
+	return elastic.Client.IndexWithID(
 		constant.ESIndex.Node,
 		id,
 		json,
 	)
-	return err
 }
 
-func (r *nodeRepository) GetNodes(q *Query) (*MapQueryResults, error) {
-	result, err := elastic.Client.GetNodes(constant.ESIndex.Node, q.Build(true))
+func (r *nodeRepository) GetNodes(
+	q *search.Query,
+) (*search.MapQueryResults, error) {
+	result, err := elastic.Client.GetNodes(
+		constant.ESIndex.Node,
+		buildQuery(q, true),
+	)
 	if err != nil {
 		return nil, index.DatabaseError{
 			Err: err,
@@ -44,63 +41,47 @@ func (r *nodeRepository) GetNodes(q *Query) (*MapQueryResults, error) {
 	}
 
 	queryResults := make([][]interface{}, 0)
-	for _, hit := range result.Hits.Hits {
-		bytes, _ := hit.Source.MarshalJSON()
+	for _, hit := range result.Hits {
 		var result map[string]interface{}
-		if err := json.Unmarshal(bytes, &result); err != nil {
+		if err := json.Unmarshal(hit.Source, &result); err != nil {
 			return nil, index.DatabaseError{
 				Err: err,
 			}
 		}
-		// create specific format for map (issue-405)
-		// [lon, lat, profile_url]
-		geolocation := result["geolocation"].(map[string]interface{})
-		mapResult := []interface{}{
-			geolocation["lon"],
-			geolocation["lat"],
-			result["profile_url"],
-		}
-		queryResults = append(queryResults, mapResult)
+		queryResults = append(queryResults, search.MapResult(result))
 	}
 
-	return &MapQueryResults{
-		Result:          queryResults,
-		NumberOfResults: result.Hits.TotalHits.Value,
-		TotalPages: pagination.TotalPages(
-			result.Hits.TotalHits.Value,
-			q.PageSize,
-		),
-	}, nil
+	return search.NewMapQueryResults(queryResults, result.TotalHits, q), nil
 }
 
-func (r *nodeRepository) Search(q *Query) (*QueryResults, error) {
-	result, err := elastic.Client.Search(constant.ESIndex.Node, q.Build(false))
+func (r *nodeRepository) Search(
+	q *search.Query,
+) (*search.QueryResults, error) {
+	result, err := elastic.Client.Search(
+		constant.ESIndex.Node,
+		buildQuery(q, false),
+	)
 	if err != nil {
 		return nil, index.DatabaseError{
 			Err: err,
 		}
 	}
 
-	queryResults := make([]QueryResult, 0)
-	for _, hit := range result.Hits.Hits {
-		bytes, _ := hit.Source.MarshalJSON()
-		var result QueryResult
-		if err := json.Unmarshal(bytes, &result); err != nil {
+	queryResults := make([]search.QueryResult, 0)
+	for _, hit := range result.Hits {
+		var result search.QueryResult
+		if err := json.Unmarshal(hit.Source, &result); err != nil {
 			return nil, index.DatabaseError{
 				Err: err,
 			}
 		}
+		if q.Lang != nil {
+			result.Localize(*q.Lang)
+		}
 		queryResults = append(queryResults, result)
 	}
 
-	return &QueryResults{
-		Result:          queryResults,
-		NumberOfResults: result.Hits.TotalHits.Value,
-		TotalPages: pagination.TotalPages(
-			result.Hits.TotalHits.Value,
-			q.PageSize,
-		),
-	}, nil
+	return search.NewQueryResults(queryResults, result.TotalHits, q), nil
 }
 
 func (r *nodeRepository) DeleteByID(id string) error {
@@ -124,10 +105,12 @@ func (r *nodeRepository) SoftDelete(node *model.Node) error {
 	return nil
 }
 
-func (r *nodeRepository) Export(q *BlockQuery) (*BlockQueryResults, error) {
+func (r *nodeRepository) Export(
+	q *search.BlockQuery,
+) (*search.BlockQueryResults, error) {
 	result, err := elastic.Client.Export(
 		constant.ESIndex.Node,
-		q.BuildBlock(),
+		buildBlockQuery(q),
 		q.SearchAfter,
 	)
 	if err != nil {
@@ -136,13 +119,12 @@ func (r *nodeRepository) Export(q *BlockQuery) (*BlockQueryResults, error) {
 		}
 	}
 
-	queryResults := make([]QueryResult, 0)
-	hitLength := len(result.Hits.Hits)
+	queryResults := make([]search.QueryResult, 0)
+	hitLength := len(result.Hits)
 	var sort []interface{}
-	for i, hit := range result.Hits.Hits {
-		bytes, _ := hit.Source.MarshalJSON()
-		var result QueryResult
-		if err := json.Unmarshal(bytes, &result); err != nil {
+	for i, hit := range result.Hits {
+		var result search.QueryResult
+		if err := json.Unmarshal(hit.Source, &result); err != nil {
 			return nil, index.DatabaseError{
 				Err: err,
 			}
@@ -154,7 +136,7 @@ func (r *nodeRepository) Export(q *BlockQuery) (*BlockQueryResults, error) {
 		}
 	}
 
-	return &BlockQueryResults{
+	return &search.BlockQueryResults{
 		Result: queryResults,
 		Sort:   sort,
 	}, nil
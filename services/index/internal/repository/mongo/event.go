@@ -0,0 +1,102 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/index"
+)
+
+// processedEventRetention is how long processed events are remembered. It
+// outlasts the redeliveries of an event, the replays from the dead letter
+// queue are protected by the node versions.
+const processedEventRetention = 7 * 24 * time.Hour
+
+// EventRepository records the events which were processed, so that they are
+// skipped when they are delivered again.
+type EventRepository interface {
+	// EnsureIndexes creates the index expiring the processed events.
+	EnsureIndexes(ctx context.Context) error
+	IsProcessed(id string) (bool, error)
+	MarkProcessed(id, subject string) error
+}
+
+// NewEventRepository returns a new EventRepository.
+func NewEventRepository() EventRepository {
+	return &eventRepository{}
+}
+
+type eventRepository struct {
+}
+
+// processedEvent is the record of a processed event.
+type processedEvent struct {
+	ID          string    `bson:"_id"`
+	Subject     string    `bson:"subject"`
+	ProcessedAt time.Time `bson:"processed_at"`
+}
+
+func (r *eventRepository) coll() *mongodriver.Collection {
+	return mongo.Client.Collection(constant.MongoIndex.ProcessedEvent)
+}
+
+// EnsureIndexes creates the index expiring the processed events.
+func (r *eventRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coll().Indexes().CreateOne(ctx, mongodriver.IndexModel{
+		Keys: bson.M{"processed_at": 1},
+		Options: options.Index().SetExpireAfterSeconds(
+			int32(processedEventRetention.Seconds()),
+		),
+	})
+	if err != nil {
+		return index.DatabaseError{
+			Message: "Error when trying to create the processed events index",
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// IsProcessed returns whether the event was processed.
+func (r *eventRepository) IsProcessed(id string) (bool, error) {
+	count, err := mongo.Client.Count(
+		constant.MongoIndex.ProcessedEvent,
+		bson.M{"_id": id},
+	)
+	if err != nil {
+		return false, index.DatabaseError{
+			Message: "Error when trying to find a processed event",
+			Err:     err,
+		}
+	}
+	return count > 0, nil
+}
+
+// MarkProcessed records that the event was processed.
+func (r *eventRepository) MarkProcessed(id, subject string) error {
+	_, err := mongo.Client.InsertOne(
+		constant.MongoIndex.ProcessedEvent,
+		processedEvent{
+			ID:          id,
+			Subject:     subject,
+			ProcessedAt: time.Now().UTC(),
+		},
+	)
+	// Another instance processed the event at the same time.
+	if mongodriver.IsDuplicateKeyError(err) {
+		return nil
+	}
+	if err != nil {
+		return index.DatabaseError{
+			Message: "Error when trying to record a processed event",
+			Err:     err,
+		}
+	}
+	return nil
+}
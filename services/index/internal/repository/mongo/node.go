@@ -1,6 +1,8 @@
 package mongo
 
 import (
+	"context"
+	"errors"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -15,11 +17,22 @@ import (
 
 // NodeMongo interface represents a set of methods required for node database operations.
 type NodeRepository interface {
-	Add(node *model.Node) error
+	// WithTransaction runs fn in a transaction, the repository methods given
+	// its context take part in it.
+	WithTransaction(fn func(ctx context.Context) error) error
+	Add(ctx context.Context, node *model.Node) error
 	GetByID(nodeID string) (*model.Node, error)
 	Update(node *model.Node) error
 	Delete(node *model.Node) error
 	SoftDelete(node *model.Node) error
+	// ForEachBatch calls handle with the nodes in one of the statuses, in
+	// batches of at most batchSize nodes, stopping at the first error.
+	ForEachBatch(
+		ctx context.Context,
+		statuses []string,
+		batchSize int,
+		handle func(nodes []*model.Node) error,
+	) error
 }
 
 // NewRepository function returns a new NodeRepository.
@@ -31,13 +44,25 @@ func NewNodeRepository() NodeRepository {
 type nodeRepository struct {
 }
 
+// WithTransaction runs fn in a transaction.
+func (r *nodeRepository) WithTransaction(
+	fn func(ctx context.Context) error,
+) error {
+	return mongo.Client.WithTransaction(context.Background(), fn)
+}
+
 // Add method adds or updates a node in the database.
-func (r *nodeRepository) Add(node *model.Node) error {
+func (r *nodeRepository) Add(ctx context.Context, node *model.Node) error {
 	filter := bson.M{"_id": node.ID}
 	update := bson.M{"$set": node}
+	// A node replicated from a peer and then posted here belongs here.
+	if node.SourceIndex == "" {
+		update["$unset"] = bson.M{"source_index": ""}
+	}
 	opt := options.FindOneAndUpdate().SetUpsert(true)
 
-	result, err := mongo.Client.FindOneAndUpdate(
+	result, err := mongo.Client.FindOneAndUpdateWithContext(
+		ctx,
 		constant.MongoIndex.Node,
 		filter,
 		update,
@@ -95,27 +120,37 @@ func (r *nodeRepository) GetByID(
 	return &node, nil
 }
 
+// Update saves the node. When the node has a version, it is only saved if the
+// stored node is still at this version, otherwise a VersionConflictError is
+// returned. Once saved, the node gets its new version, so that saving it again
+// also fails if it changed in the meantime.
 func (r *nodeRepository) Update(node *model.Node) error {
 	filter := bson.M{"_id": node.ID}
 
-	if node.Version != nil {
-		filter["__v"] = node.Version
-		// Unset the version to prevent setting it.
-		node.Version = nil
+	version := node.Version
+	if version != nil {
+		filter["__v"] = *version
 	}
+	// Unset the version to prevent setting it, it is incremented instead.
+	node.Version = nil
 
 	update := bson.M{"$set": node}
 
-	_, err := mongo.Client.FindOneAndUpdate(
+	result, err := mongo.Client.FindOneAndUpdate(
 		constant.MongoIndex.Node,
 		filter,
 		update,
 	)
 	if err != nil {
-		// Update the document only if the version matches.
-		// If the version does not match, it's an expected concurrent issue.
-		if err == mongo.ErrNoDocuments {
-			return nil
+		node.Version = version
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			if version == nil {
+				return nil
+			}
+			return index.VersionConflictError{
+				NodeID:  node.ID,
+				Version: *version,
+			}
 		}
 		return index.DatabaseError{
 			Message: "Error when trying to update a node",
@@ -123,6 +158,15 @@ func (r *nodeRepository) Update(node *model.Node) error {
 		}
 	}
 
+	var updated model.Node
+	if err := result.Decode(&updated); err != nil {
+		return index.DatabaseError{
+			Message: "Error occurred during decoding of updated node",
+			Err:     err,
+		}
+	}
+	node.Version = updated.Version
+
 	return nil
 }
 
@@ -170,3 +214,54 @@ func (r *nodeRepository) setDeleted(node *model.Node) error {
 
 	return nil
 }
+
+func (r *nodeRepository) ForEachBatch(
+	ctx context.Context,
+	statuses []string,
+	batchSize int,
+	handle func(nodes []*model.Node) error,
+) error {
+	filter := bson.M{"status": bson.M{"$in": statuses}}
+	opts := options.Find().
+		SetBatchSize(int32(batchSize)).
+		SetSort(bson.M{"_id": 1})
+
+	cursor, err := mongo.Client.Find(constant.MongoIndex.Node, filter, opts)
+	if err != nil {
+		return index.DatabaseError{
+			Message: "Error when trying to find a node",
+			Err:     err,
+		}
+	}
+	defer cursor.Close(ctx)
+
+	batch := make([]*model.Node, 0, batchSize)
+	for cursor.Next(ctx) {
+		var node model.Node
+		if err := cursor.Decode(&node); err != nil {
+			return index.DatabaseError{
+				Message: "Error when trying to find a node",
+				Err:     err,
+			}
+		}
+		batch = append(batch, &node)
+		if len(batch) < batchSize {
+			continue
+		}
+		if err := handle(batch); err != nil {
+			return err
+		}
+		batch = make([]*model.Node, 0, batchSize)
+	}
+	if err := cursor.Err(); err != nil {
+		return index.DatabaseError{
+			Message: "Error when trying to find a node",
+			Err:     err,
+		}
+	}
+
+	if len(batch) > 0 {
+		return handle(batch)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/index"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/model"
+)
+
+// PeerRepository stores the peers of the index.
+type PeerRepository interface {
+	// Add registers the peer, or changes its direction if it was already
+	// registered. The replication cursor is kept.
+	Add(peer *model.Peer) error
+	GetAll() ([]*model.Peer, error)
+	Delete(peerID string) error
+	// UpdateSync records the outcome of a replication from the peer.
+	UpdateSync(peer *model.Peer) error
+}
+
+// NewPeerRepository returns a new PeerRepository.
+func NewPeerRepository() PeerRepository {
+	return &peerRepository{}
+}
+
+type peerRepository struct {
+}
+
+func (r *peerRepository) Add(peer *model.Peer) error {
+	filter := bson.M{"_id": peer.ID}
+	update := bson.M{
+		"$set": bson.M{
+			"url":       peer.URL,
+			"direction": peer.Direction,
+		},
+		"$setOnInsert": bson.M{"createdAt": peer.CreatedAt},
+	}
+	opt := options.FindOneAndUpdate().
+		SetUpsert(true).
+		SetReturnDocument(options.After)
+
+	result, err := mongo.Client.FindOneAndUpdate(
+		constant.MongoIndex.Peer,
+		filter,
+		update,
+		opt,
+	)
+	if err != nil {
+		return index.DatabaseError{
+			Message: "Error when trying to add a peer",
+			Err:     err,
+		}
+	}
+	if err := result.Decode(peer); err != nil {
+		return index.DatabaseError{
+			Message: "Error when trying to add a peer",
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+func (r *peerRepository) GetAll() ([]*model.Peer, error) {
+	opts := options.Find().SetSort(bson.M{"createdAt": 1})
+
+	cursor, err := mongo.Client.Find(
+		constant.MongoIndex.Peer,
+		bson.M{},
+		opts,
+	)
+	if err != nil {
+		return nil, index.DatabaseError{
+			Message: "Error when trying to find the peers",
+			Err:     err,
+		}
+	}
+
+	peers := make([]*model.Peer, 0)
+	if err := cursor.All(context.Background(), &peers); err != nil {
+		return nil, index.DatabaseError{
+			Message: "Error when trying to find the peers",
+			Err:     err,
+		}
+	}
+	return peers, nil
+}
+
+func (r *peerRepository) Delete(peerID string) error {
+	filter := bson.M{"_id": peerID}
+
+	result := mongo.Client.FindOne(constant.MongoIndex.Peer, filter)
+	if err := result.Err(); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return index.NotFoundError{
+				Err: err,
+			}
+		}
+		return index.DatabaseError{
+			Message: "Error when trying to find a peer",
+			Err:     err,
+		}
+	}
+
+	err := mongo.Client.DeleteOne(constant.MongoIndex.Peer, filter)
+	if err != nil {
+		return index.DatabaseError{
+			Message: "Error when trying to delete a peer",
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+func (r *peerRepository) UpdateSync(peer *model.Peer) error {
+	set := bson.M{"last_synced": peer.LastSynced}
+	update := bson.M{"$set": set}
+	if len(peer.Cursor) > 0 {
+		set["cursor"] = peer.Cursor
+	}
+	if peer.LastError != "" {
+		set["last_error"] = peer.LastError
+	} else {
+		update["$unset"] = bson.M{"last_error": ""}
+	}
+
+	_, err := mongo.Client.FindOneAndUpdate(
+		constant.MongoIndex.Peer,
+		bson.M{"_id": peer.ID},
+		update,
+	)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return index.DatabaseError{
+			Message: "Error when trying to update a peer",
+			Err:     err,
+		}
+	}
+	return nil
+}
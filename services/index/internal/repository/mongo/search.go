@@ -0,0 +1,424 @@
+package mongo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/pagination"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/index"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/search"
+)
+
+// geoField holds the [lon, lat] pair of the geolocation, the format of the
+// geospatial queries of MongoDB.
+const geoField = "_geo"
+
+// earthRadius is the radius of the Earth in meters, to convert distances to
+// the radians of $centerSphere.
+const earthRadius = 6378100
+
+// NewSearchRepository returns a search.NodeRepository storing the profiles in
+// MongoDB, so that small deployments don't need Elasticsearch.
+//
+// It supports the same queries as Elasticsearch, with a few differences: the
+// text matches are case-insensitive substring matches without fuzziness, the
+// tags are matched as whole words and the results are ordered by primary URL
+// since there is no relevance score.
+func NewSearchRepository() search.NodeRepository {
+	return &searchRepository{}
+}
+
+type searchRepository struct {
+}
+
+func (r *searchRepository) coll() *mongodriver.Collection {
+	return mongo.Client.Collection(constant.MongoIndex.SearchNode)
+}
+
+func (r *searchRepository) IndexByID(id string, profile interface{}) error {
+	doc, err := toSearchDocument(profile)
+	if err != nil {
+		return index.DatabaseError{
+			Message: "Error when trying to convert the profile to a document",
+			Err:     err,
+		}
+	}
+
+	_, err = r.coll().ReplaceOne(
+		context.Background(),
+		bson.M{"_id": id},
+		doc,
+		options.Replace().SetUpsert(true),
+	)
+	if err != nil {
+		return index.DatabaseError{
+			Message: "Error when trying to index a profile",
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+func (r *searchRepository) GetNodes(
+	q *search.Query,
+) (*search.MapQueryResults, error) {
+	docs, total, err := r.find(q, true, pagination.MaximumSize(q.PageSize))
+	if err != nil {
+		return nil, err
+	}
+
+	queryResults := make([][]interface{}, 0, len(docs))
+	for _, doc := range docs {
+		queryResults = append(queryResults, search.MapResult(doc))
+	}
+	return search.NewMapQueryResults(queryResults, total, q), nil
+}
+
+func (r *searchRepository) Search(
+	q *search.Query,
+) (*search.QueryResults, error) {
+	docs, total, err := r.find(q, false, pagination.Size(q.PageSize))
+	if err != nil {
+		return nil, err
+	}
+
+	queryResults := make([]search.QueryResult, 0, len(docs))
+	for _, doc := range docs {
+		if q.Lang != nil {
+			doc.Localize(*q.Lang)
+		}
+		queryResults = append(queryResults, doc)
+	}
+	return search.NewQueryResults(queryResults, total, q), nil
+}
+
+// find returns the page of the documents matching the query, and the number
+// of documents matching it.
+func (r *searchRepository) find(
+	q *search.Query,
+	isMap bool,
+	size int64,
+) ([]search.QueryResult, int64, error) {
+	filter, err := buildSearchFilter(q, isMap)
+	if err != nil {
+		return nil, 0, index.DatabaseError{
+			Message: "Invalid search query",
+			Err:     err,
+		}
+	}
+
+	total, err := mongo.Client.Count(constant.MongoIndex.SearchNode, filter)
+	if err != nil {
+		return nil, 0, index.DatabaseError{
+			Message: "Error when trying to count the profiles",
+			Err:     err,
+		}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "primary_url", Value: 1}}).
+		SetSkip(pagination.From(q.Page, q.PageSize)).
+		SetLimit(size)
+	docs, err := r.decodeAll(filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	return docs, total, nil
+}
+
+func (r *searchRepository) DeleteByID(id string) error {
+	_, err := r.coll().DeleteOne(context.Background(), bson.M{"_id": id})
+	if err != nil {
+		return index.DatabaseError{
+			Message: "Error when trying to delete a profile",
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+func (r *searchRepository) SoftDelete(node *model.Node) error {
+	_, err := r.coll().UpdateOne(
+		context.Background(),
+		bson.M{"_id": node.ID},
+		bson.M{"$set": bson.M{
+			"status":       constant.NodeStatus.Deleted,
+			"last_updated": node.LastUpdated,
+		}},
+	)
+	if err != nil {
+		return index.DatabaseError{
+			Message: "Error when trying to mark a profile as deleted",
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+func (r *searchRepository) Export(
+	q *search.BlockQuery,
+) (*search.BlockQueryResults, error) {
+	filter, err := buildExportFilter(q)
+	if err != nil {
+		return nil, index.DatabaseError{
+			Message: "Invalid search_after cursor",
+			Err:     err,
+		}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{
+			{Key: "last_updated", Value: 1},
+			{Key: "profile_url", Value: 1},
+		}).
+		SetLimit(pagination.Size(q.PageSize))
+	docs, err := r.decodeAll(filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var sort []interface{}
+	if len(docs) > 0 {
+		last := docs[len(docs)-1]
+		sort = []interface{}{last["last_updated"], last["profile_url"]}
+	}
+	return &search.BlockQueryResults{Result: docs, Sort: sort}, nil
+}
+
+// decodeAll returns the documents matching the filter as they were indexed.
+func (r *searchRepository) decodeAll(
+	filter bson.M,
+	opts *options.FindOptions,
+) ([]search.QueryResult, error) {
+	cursor, err := mongo.Client.Find(
+		constant.MongoIndex.SearchNode,
+		filter,
+		opts.SetProjection(bson.M{"_id": 0, geoField: 0}),
+	)
+	if err != nil {
+		return nil, index.DatabaseError{
+			Message: "Error when trying to search the profiles",
+			Err:     err,
+		}
+	}
+	defer cursor.Close(context.Background())
+
+	docs := make([]search.QueryResult, 0)
+	for cursor.Next(context.Background()) {
+		// Going through JSON gives the same values as Elasticsearch, such as
+		// maps for the nested objects.
+		data, err := bson.MarshalExtJSON(cursor.Current, false, false)
+		if err != nil {
+			return nil, index.DatabaseError{
+				Message: "Error when trying to decode a profile",
+				Err:     err,
+			}
+		}
+		var doc search.QueryResult
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, index.DatabaseError{
+				Message: "Error when trying to decode a profile",
+				Err:     err,
+			}
+		}
+		docs = append(docs, doc)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, index.DatabaseError{
+			Message: "Error when trying to search the profiles",
+			Err:     err,
+		}
+	}
+	return docs, nil
+}
+
+// toSearchDocument keeps the searchable fields of the profile and adds the
+// coordinates of its geolocation.
+func toSearchDocument(profile interface{}) (bson.M, error) {
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	doc := bson.M{}
+	for _, field := range search.Fields {
+		if value, ok := fields[field]; ok {
+			doc[field] = value
+		}
+	}
+	if geolocation, ok := fields["geolocation"].(map[string]interface{}); ok {
+		lat, latOK := geolocation["lat"].(float64)
+		lon, lonOK := geolocation["lon"].(float64)
+		if latOK && lonOK {
+			doc[geoField] = bson.A{lon, lat}
+		}
+	}
+	return doc, nil
+}
+
+// buildSearchFilter returns the MongoDB filter matching the query.
+func buildSearchFilter(q *search.Query, isMap bool) (bson.M, error) {
+	var conditions bson.A
+
+	if q.Name != nil {
+		name := bson.M{"name": containsRegex(*q.Name)}
+		if q.Lang != nil {
+			name = bson.M{"$or": bson.A{
+				name,
+				bson.M{"name_i18n." + *q.Lang: containsRegex(*q.Name)},
+			}}
+		}
+		conditions = append(conditions, name)
+	}
+	if q.Schema != nil {
+		conditions = append(conditions, bson.M{
+			"linked_schemas": prefixRegex(*q.Schema),
+		})
+	}
+	if q.LastUpdated != nil {
+		conditions = append(conditions, bson.M{
+			"last_updated": bson.M{"$gte": *q.LastUpdated},
+		})
+	}
+	for _, field := range []struct {
+		name  string
+		value *string
+	}{
+		{"locality", q.Locality},
+		{"region", q.Region},
+		{"country", q.Country},
+	} {
+		if field.value != nil {
+			conditions = append(conditions, bson.M{
+				field.name: containsRegex(*field.value),
+			})
+		}
+	}
+	if q.Status != nil {
+		conditions = append(conditions, bson.M{"status": *q.Status})
+	}
+	if q.PrimaryURL != nil {
+		conditions = append(conditions, bson.M{"primary_url": *q.PrimaryURL})
+	}
+	geo, err := buildGeoFilter(q.Lat, q.Lon, q.Range)
+	if err != nil {
+		return nil, err
+	}
+	if geo != nil {
+		conditions = append(conditions, geo)
+	}
+	if q.Expires != nil {
+		conditions = append(conditions, bson.M{
+			"expires": bson.M{"$lte": *q.Expires},
+		})
+	}
+	if tags := buildTagsFilter(q); tags != nil {
+		conditions = append(conditions, tags)
+	}
+	if isMap {
+		conditions = append(conditions, bson.M{
+			"geolocation": bson.M{"$exists": true},
+		})
+	}
+
+	if len(conditions) == 0 {
+		return bson.M{}, nil
+	}
+	return bson.M{"$and": conditions}, nil
+}
+
+// buildGeoFilter matches the profiles within the distance of the location.
+func buildGeoFilter(lat, lon *float64, distance *string) (bson.M, error) {
+	if lat == nil || lon == nil || distance == nil {
+		return nil, nil
+	}
+	meters, err := search.ParseDistance(*distance)
+	if err != nil {
+		return nil, err
+	}
+	return bson.M{geoField: bson.M{"$geoWithin": bson.M{
+		"$centerSphere": bson.A{bson.A{*lon, *lat}, meters / earthRadius},
+	}}}, nil
+}
+
+// buildTagsFilter matches the profiles with any of the tags of the query, or
+// all of them when the tags filter is "and".
+func buildTagsFilter(q *search.Query) bson.M {
+	if q.Tags == nil {
+		return nil
+	}
+	words := strings.FieldsFunc(*q.Tags, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	if len(words) == 0 {
+		return nil
+	}
+
+	tags := make(bson.A, 0, len(words))
+	for _, word := range words {
+		tags = append(tags, primitive.Regex{
+			Pattern: `\b` + regexp.QuoteMeta(word) + `\b`,
+			Options: "i",
+		})
+	}
+	if q.TagsFilter != nil && *q.TagsFilter == "and" {
+		return bson.M{"tags": bson.M{"$all": tags}}
+	}
+	return bson.M{"tags": bson.M{"$in": tags}}
+}
+
+// buildExportFilter returns the MongoDB filter matching the block query,
+// after its cursor.
+func buildExportFilter(q *search.BlockQuery) (bson.M, error) {
+	var conditions bson.A
+	if q.Schema != nil {
+		conditions = append(conditions, bson.M{
+			"linked_schemas": prefixRegex(*q.Schema),
+		})
+	}
+	if len(q.SearchAfter) > 0 {
+		if len(q.SearchAfter) != 2 {
+			return nil, fmt.Errorf(
+				"expected [last_updated, profile_url], got %v",
+				q.SearchAfter,
+			)
+		}
+		lastUpdated, profileURL := q.SearchAfter[0], q.SearchAfter[1]
+		conditions = append(conditions, bson.M{"$or": bson.A{
+			bson.M{"last_updated": bson.M{"$gt": lastUpdated}},
+			bson.M{
+				"last_updated": lastUpdated,
+				"profile_url":  bson.M{"$gt": profileURL},
+			},
+		}})
+	}
+
+	if len(conditions) == 0 {
+		return bson.M{}, nil
+	}
+	return bson.M{"$and": conditions}, nil
+}
+
+func containsRegex(value string) primitive.Regex {
+	return primitive.Regex{Pattern: regexp.QuoteMeta(value), Options: "i"}
+}
+
+func prefixRegex(value string) primitive.Regex {
+	return primitive.Regex{Pattern: "^" + regexp.QuoteMeta(value), Options: "i"}
+}
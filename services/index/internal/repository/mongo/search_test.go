@@ -0,0 +1,95 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/search"
+)
+
+func TestBuildSearchFilter(t *testing.T) {
+	name, lang, schema, status := "Ic3", "fr", "org_schema", "posted"
+	tags, tagsFilter := "open, food", "and"
+	lat, lon, distance := 48.85, 2.35, "10km"
+
+	filter, err := buildSearchFilter(&search.Query{
+		Name:       &name,
+		Lang:       &lang,
+		Schema:     &schema,
+		Status:     &status,
+		Tags:       &tags,
+		TagsFilter: &tagsFilter,
+		Lat:        &lat,
+		Lon:        &lon,
+		Range:      &distance,
+	}, true)
+	require.NoError(t, err)
+
+	require.Equal(t, bson.M{"$and": bson.A{
+		bson.M{"$or": bson.A{
+			bson.M{"name": primitive.Regex{Pattern: "Ic3", Options: "i"}},
+			bson.M{"name_i18n.fr": primitive.Regex{Pattern: "Ic3", Options: "i"}},
+		}},
+		bson.M{"linked_schemas": primitive.Regex{
+			Pattern: "^org_schema",
+			Options: "i",
+		}},
+		bson.M{"status": "posted"},
+		bson.M{geoField: bson.M{"$geoWithin": bson.M{
+			"$centerSphere": bson.A{bson.A{2.35, 48.85}, 10000.0 / earthRadius},
+		}}},
+		bson.M{"tags": bson.M{"$all": bson.A{
+			primitive.Regex{Pattern: `\bopen\b`, Options: "i"},
+			primitive.Regex{Pattern: `\bfood\b`, Options: "i"},
+		}}},
+		bson.M{"geolocation": bson.M{"$exists": true}},
+	}}, filter)
+
+	filter, err = buildSearchFilter(&search.Query{}, false)
+	require.NoError(t, err)
+	require.Equal(t, bson.M{}, filter)
+
+	distance = "far"
+	_, err = buildSearchFilter(&search.Query{
+		Lat:   &lat,
+		Lon:   &lon,
+		Range: &distance,
+	}, false)
+	require.Error(t, err)
+}
+
+func TestBuildExportFilter(t *testing.T) {
+	filter, err := buildExportFilter(&search.BlockQuery{
+		SearchAfter: []interface{}{float64(1700000000), "https://ic3.dev"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, bson.M{"$and": bson.A{bson.M{"$or": bson.A{
+		bson.M{"last_updated": bson.M{"$gt": float64(1700000000)}},
+		bson.M{
+			"last_updated": float64(1700000000),
+			"profile_url":  bson.M{"$gt": "https://ic3.dev"},
+		},
+	}}}}, filter)
+
+	_, err = buildExportFilter(&search.BlockQuery{
+		SearchAfter: []interface{}{float64(1700000000)},
+	})
+	require.Error(t, err)
+}
+
+func TestToSearchDocument(t *testing.T) {
+	doc, err := toSearchDocument(map[string]interface{}{
+		"name":        "Ic3",
+		"profile_url": "https://ic3.dev/profile.json",
+		"geolocation": map[string]interface{}{"lat": 48.85, "lon": 2.35},
+		"description": "Not searchable",
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, "Ic3", doc["name"])
+	require.NotContains(t, doc, "description")
+	require.Equal(t, bson.A{2.35, 48.85}, doc[geoField])
+}
@@ -0,0 +1,45 @@
+package search
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// distanceUnits are the units of the distances in meters, longest first so
+// that "mi" is not read as "m".
+var distanceUnits = []struct {
+	suffix string
+	meters float64
+}{
+	{"nmi", 1852},
+	{"km", 1000},
+	{"mi", 1609.344},
+	{"yd", 0.9144},
+	{"ft", 0.3048},
+	{"cm", 0.01},
+	{"mm", 0.001},
+	{"in", 0.0254},
+	{"NM", 1852},
+	{"m", 1},
+}
+
+// ParseDistance returns the distance in meters of a distance in the format of
+// Elasticsearch, such as "10km" or "5mi". Distances without unit are in
+// meters.
+func ParseDistance(distance string) (float64, error) {
+	distance = strings.TrimSpace(distance)
+	multiplier := 1.0
+	for _, unit := range distanceUnits {
+		if strings.HasSuffix(distance, unit.suffix) {
+			distance = strings.TrimSuffix(distance, unit.suffix)
+			multiplier = unit.meters
+			break
+		}
+	}
+	value, err := strconv.ParseFloat(strings.TrimSpace(distance), 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid distance: %s", distance)
+	}
+	return value * multiplier, nil
+}
@@ -0,0 +1,35 @@
+package search_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/search"
+)
+
+func TestParseDistance(t *testing.T) {
+	tests := []struct {
+		distance string
+		meters   float64
+	}{
+		{"100", 100},
+		{"100m", 100},
+		{"10km", 10000},
+		{"2mi", 3218.688},
+		{"1nmi", 1852},
+		{" 1.5 km ", 1500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.distance, func(t *testing.T) {
+			meters, err := search.ParseDistance(tt.distance)
+			require.NoError(t, err)
+			require.InDelta(t, tt.meters, meters, 1e-9)
+		})
+	}
+
+	_, err := search.ParseDistance("far")
+	require.Error(t, err)
+	_, err = search.ParseDistance("-1km")
+	require.Error(t, err)
+}
@@ -0,0 +1,139 @@
+package search
+
+import (
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/pagination"
+)
+
+// Query defines the parameters that can be used to filter and search profiles.
+type Query struct {
+	// Name is used to match profiles based on the "name" field.
+	Name *string `form:"name"`
+
+	// Lang, if set, also matches Name against the variant of "name_i18n" in
+	// that language and returns that variant as the "name" of the results.
+	Lang *string `form:"lang"`
+
+	// Schema is used to match profiles linked to a specific schema pattern.
+	Schema *string `form:"schema"`
+
+	// LastUpdated is used to filter profiles based on when they were last updated.
+	LastUpdated *int64 `form:"last_updated,default=0"`
+
+	// Lat and Lon, along with Range, are used for geo-based queries.
+	Lat   *float64 `form:"lat"`
+	Lon   *float64 `form:"lon"`
+	Range *string  `form:"range"`
+
+	// Locality, Region, and Country are used to filter profiles based on
+	// their associated geographical metadata.
+	Locality *string `form:"locality"`
+	Region   *string `form:"region"`
+	Country  *string `form:"country"`
+
+	// Status is used to match profiles based on their "status" field.
+	Status *string `form:"status"`
+
+	// Tags, TagsFilter and TagsExact are used to filter profiles based on
+	// the "tags" field.
+	Tags *string `form:"tags"`
+	// TagsFilter, if set to "and", indicates that the "tags" field filter should
+	// perform an "AND" operation (i.e., match all supplied tags).
+	TagsFilter *string `form:"tags_filter"`
+	// TagsExact, if set to true, indicates that the "tags" field filter should perform
+	// an exact match.
+	TagsExact *string `form:"tags_exact"`
+
+	// PrimaryURL is used to match profiles based on the "primary_url" field.
+	PrimaryURL *string `form:"primary_url"`
+
+	// Expires is used to filter profiles based on the "expires" field.
+	Expires *int64 `form:"expires"`
+
+	// Page and PageSize are used to control the pagination of the search
+	// results.
+	Page     int64 `form:"page,default=0"`
+	PageSize int64 `form:"page_size,default=30"`
+}
+
+type QueryResult map[string]interface{}
+
+// Localize replaces the name with its variant in the given language, if the
+// profile has one.
+func (r QueryResult) Localize(lang string) {
+	variants, ok := r["name_i18n"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	if name, ok := variants[lang].(string); ok {
+		r["name"] = name
+	}
+}
+
+type QueryResults struct {
+	Result          []QueryResult
+	NumberOfResults int64
+	TotalPages      int64
+}
+
+// BlockQuery defines the parameters that can be used to export blocks of
+// profiles.
+type BlockQuery struct {
+	// Schema is used to match blocks linked to a specific schema.
+	Schema *string `json:"schema,omitempty"`
+
+	// PageSize controls the number of results per page.
+	PageSize int64 `json:"page_size"`
+
+	// SearchAfter defines the cursor for pagination and is used in conjunction with PageSize.
+	SearchAfter []interface{} `json:"search_after,omitempty"`
+}
+
+type BlockQueryResults struct {
+	Result []QueryResult
+	Sort   []interface{}
+}
+
+type MapQueryResults struct {
+	Result          [][]interface{}
+	NumberOfResults int64
+	TotalPages      int64
+}
+
+// NewMapQueryResults returns the page of the map results with the paging
+// information of the query.
+func NewMapQueryResults(
+	results [][]interface{},
+	total int64,
+	q *Query,
+) *MapQueryResults {
+	return &MapQueryResults{
+		Result:          results,
+		NumberOfResults: total,
+		TotalPages:      pagination.TotalPages(total, q.PageSize),
+	}
+}
+
+// NewQueryResults returns the page of the results with the paging information
+// of the query.
+func NewQueryResults(
+	results []QueryResult,
+	total int64,
+	q *Query,
+) *QueryResults {
+	return &QueryResults{
+		Result:          results,
+		NumberOfResults: total,
+		TotalPages:      pagination.TotalPages(total, q.PageSize),
+	}
+}
+
+// MapResult returns the map format of the profile (issue-405):
+// [lon, lat, profile_url].
+func MapResult(profile map[string]interface{}) []interface{} {
+	geolocation, _ := profile["geolocation"].(map[string]interface{})
+	return []interface{}{
+		geolocation["lon"],
+		geolocation["lat"],
+		profile["profile_url"],
+	}
+}
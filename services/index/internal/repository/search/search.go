@@ -0,0 +1,45 @@
+// Package search defines the search index of the nodes independently of the
+// backend storing it, such as Elasticsearch or MongoDB.
+package search
+
+import (
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/model"
+)
+
+// Fields are the fields of the profiles which are stored to be searched and
+// returned in the results.
+var Fields = []string{
+	"name",
+	"name_i18n",
+	"geolocation",
+	"last_updated",
+	"linked_schemas",
+	"country",
+	"locality",
+	"region",
+	"profile_url",
+	"status",
+	"tags",
+	"primary_url",
+	"expires",
+	"profile_hash",
+	"source_index",
+}
+
+// NodeRepository stores the profiles of the nodes so they can be searched.
+type NodeRepository interface {
+	// IndexByID stores the profile of the node, replacing the previous one.
+	IndexByID(id string, json interface{}) error
+	// GetNodes returns the location of the nodes matching the query.
+	GetNodes(q *Query) (*MapQueryResults, error)
+	// Search returns a page of the nodes matching the query.
+	Search(q *Query) (*QueryResults, error)
+	// DeleteByID removes the node. Removing a missing node is not an error.
+	DeleteByID(id string) error
+	// SoftDelete marks the node as deleted, so it is still found with the
+	// deleted status until it is removed.
+	SoftDelete(node *model.Node) error
+	// Export returns the nodes matching the query after the cursor of the
+	// query, ordered by last update and profile URL.
+	Export(q *BlockQuery) (*BlockQueryResults, error)
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/messaging"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/mongo"
+)
+
+// EventService keeps track of the events which were processed, so that the
+// events delivered again are skipped.
+type EventService interface {
+	IsProcessed(envelope *messaging.Envelope) (bool, error)
+	MarkProcessed(envelope *messaging.Envelope) error
+}
+
+type eventService struct {
+	repo mongo.EventRepository
+}
+
+// NewEventService creates a new instance of EventService.
+func NewEventService(repo mongo.EventRepository) EventService {
+	return &eventService{repo: repo}
+}
+
+// IsProcessed returns whether the event was processed. Events published
+// without an envelope have no ID and are never considered processed.
+func (s *eventService) IsProcessed(envelope *messaging.Envelope) (bool, error) {
+	if envelope.ID == "" {
+		return false, nil
+	}
+	return s.repo.IsProcessed(envelope.ID)
+}
+
+// MarkProcessed records that the event was processed.
+func (s *eventService) MarkProcessed(envelope *messaging.Envelope) error {
+	if envelope.ID == "" {
+		return nil
+	}
+	return s.repo.MarkProcessed(envelope.ID, envelope.Type)
+}
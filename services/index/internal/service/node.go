@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,12 +13,13 @@ import (
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/httputil"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/messaging"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/outbox"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/profile/profilehasher"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/config"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/index"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/model"
-	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/es"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/search"
 )
 
 // NodeService is an interface that defines operations on nodes.
@@ -26,26 +28,31 @@ type NodeService interface {
 	GetNode(nodeID string) (*model.Node, error)
 	SetNodeValid(node *model.Node) error
 	SetNodeInvalid(node *model.Node) error
-	Search(query *es.Query) (*es.QueryResults, error)
+	Search(query *search.Query) (*search.QueryResults, error)
 	Delete(nodeID string) (string, error)
-	Export(query *es.BlockQuery) (*es.BlockQueryResults, error)
-	GetNodes(query *es.Query) (*es.MapQueryResults, error)
+	Export(query *search.BlockQuery) (*search.BlockQueryResults, error)
+	GetNodes(query *search.Query) (*search.MapQueryResults, error)
+	// ReplicateNode updates the copy of a node replicated from a peer.
+	ReplicateNode(node *model.Node) error
 }
 
 type nodeService struct {
-	mongoRepo   mongo.NodeRepository
-	elasticRepo es.NodeRepository
+	mongoRepo  mongo.NodeRepository
+	searchRepo search.NodeRepository
+	outbox     outbox.Store
 }
 
-// NewNodeService creates a new instance of NodeService.
+// NewNodeService creates a new instance of NodeService. The events it emits
+// are written to the outbox, along with the nodes they are about.
 func NewNodeService(
 	mongoRepo mongo.NodeRepository,
-	elasticRepo es.NodeRepository,
-
+	searchRepo search.NodeRepository,
+	outboxStore outbox.Store,
 ) NodeService {
 	return &nodeService{
-		mongoRepo:   mongoRepo,
-		elasticRepo: elasticRepo,
+		mongoRepo:  mongoRepo,
+		searchRepo: searchRepo,
+		outbox:     outboxStore,
 	}
 }
 
@@ -63,6 +70,15 @@ func (s *nodeService) SetNodeValid(node *model.Node) error {
 		return err
 	}
 
+	// The event was delivered again after it changed the node, but before
+	// it was fully processed.
+	if node.ResumesEvent(oldNode) {
+		if oldNode.Status == constant.NodeStatus.Posted {
+			return nil
+		}
+		node.Version = oldNode.Version
+	}
+
 	if s.isProfileHashUnchanged(node, oldNode) {
 		logger.Info(
 			fmt.Sprintf(
@@ -73,34 +89,32 @@ func (s *nodeService) SetNodeValid(node *model.Node) error {
 		// Reposted node with no changes has same last_updated timestamp.
 		node.LastUpdated = oldNode.LastUpdated
 	}
+	if oldNode != nil {
+		node.SourceIndex = oldNode.SourceIndex
+	}
 
 	// Update the node in MongoDB.
-	if err := s.mongoRepo.Update(node); err != nil {
+	if err := s.updateForEvent(node); err != nil {
 		return err
 	}
 
-	profile := model.NewProfile(node.ProfileStr)
-	if err := profile.Update(node.ProfileURL, node.LastUpdated); err != nil {
+	profileJSON, err := searchDocument(node)
+	if err != nil {
 		return err
 	}
 
-	profileJSON := profile.GetJSON()
-	if node.Expires != nil {
-		profileJSON["expires"] = *node.Expires
-	}
-
-	// Update Elastic Search.
-	if err := s.elasticRepo.IndexByID(node.ID, profileJSON); err != nil {
+	// Update the search index.
+	if err := s.searchRepo.IndexByID(node.ID, profileJSON); err != nil {
 		errMsg := fmt.Sprintf(
-			"Error indexing node ID '%s' in Elastic repository.",
+			"Error indexing node ID '%s' in the search index.",
 			node.ID,
 		)
 		logger.Error(errMsg, err)
 
 		node.SetStatusPostFailed()
-		if mongoErr := s.mongoRepo.Update(node); mongoErr != nil {
+		if mongoErr := s.updateForEvent(node); mongoErr != nil {
 			logger.Error(
-				"Failed to update node in MongoDB after Elastic indexing failure.",
+				"Failed to update node in MongoDB after search indexing failure.",
 				mongoErr,
 			)
 		}
@@ -109,7 +123,7 @@ func (s *nodeService) SetNodeValid(node *model.Node) error {
 
 	// Set final status and update.
 	node.SetStatusPosted()
-	return s.mongoRepo.Update(node)
+	return s.updateForEvent(node)
 }
 
 // isProfileHashUnchanged checks if the profile hash of the new node matches
@@ -129,20 +143,67 @@ func (s *nodeService) isProfileHashUnchanged(
 		*oldNode.ProfileHash == newHash
 }
 
+// searchDocument builds the search index document of the node from its
+// profile.
+func searchDocument(node *model.Node) (map[string]interface{}, error) {
+	profile := model.NewProfile(node.ProfileStr)
+	if err := profile.Update(node.ProfileURL, node.LastUpdated); err != nil {
+		return nil, err
+	}
+
+	profileJSON := profile.GetJSON()
+	if node.Expires != nil {
+		profileJSON["expires"] = *node.Expires
+	}
+	if node.ProfileHash != nil {
+		profileJSON["profile_hash"] = *node.ProfileHash
+	}
+	profileJSON["source_index"] = sourceIndex(node)
+	return profileJSON, nil
+}
+
+// sourceIndex returns the URL of the index the node was posted to.
+func sourceIndex(node *model.Node) string {
+	if node.SourceIndex != "" {
+		return node.SourceIndex
+	}
+	return config.Values.Peering.IndexURL
+}
+
 // SetNodeInvalid sets a node as invali.
 func (s *nodeService) SetNodeInvalid(node *model.Node) error {
 	node.ID = cryptoutil.ComputeSHA256(node.ProfileURL)
+
+	oldNode, err := s.mongoRepo.GetByID(node.ID)
+	if err != nil && !errors.As(err, &index.NotFoundError{}) {
+		return err
+	}
+	// The node was saved by this event, only removing it from the search
+	// index failed.
+	if node.ResumesEvent(oldNode) {
+		return s.searchRepo.DeleteByID(node.ID)
+	}
+
 	node.Status = constant.NodeStatus.ValidationFailed
 	emptystr := ""
 	node.ProfileHash = &emptystr
 	lastUpdated := dateutil.GetZeroValueUnix()
 	node.LastUpdated = &lastUpdated
 
-	if err := s.mongoRepo.Update(node); err != nil {
+	if err := s.updateForEvent(node); err != nil {
 		return err
 	}
 
-	return s.elasticRepo.DeleteByID(node.ID)
+	return s.searchRepo.DeleteByID(node.ID)
+}
+
+// updateForEvent saves the node changed by its event, recording the version
+// the change gives the node.
+func (s *nodeService) updateForEvent(node *model.Node) error {
+	if node.LastEvent != nil && node.Version != nil {
+		node.LastEvent.Version = *node.Version + 1
+	}
+	return s.mongoRepo.Update(node)
 }
 
 // AddNode adds a new node to the system.
@@ -170,13 +231,13 @@ func (s *nodeService) AddNode(
 	node.Status = constant.NodeStatus.Received
 	node.CreatedAt = dateutil.GetNowUnix()
 
-	if err := s.mongoRepo.Add(node); err != nil {
-		return nil, err
-	}
-
-	err = messaging.Publish(messaging.NodeCreated, messaging.NodeCreatedData{
-		ProfileURL: node.ProfileURL,
-		Version:    *node.Version,
+	// The node is only saved along with the event sending it to validation,
+	// so it can't get stuck in the received status.
+	err = s.mongoRepo.WithTransaction(func(ctx context.Context) error {
+		if err := s.mongoRepo.Add(ctx, node); err != nil {
+			return err
+		}
+		return s.enqueueNodeCreated(ctx, node, isAccepted(oldNode))
 	})
 	if err != nil {
 		return nil, err
@@ -185,6 +246,88 @@ func (s *nodeService) AddNode(
 	return node, nil
 }
 
+// enqueueNodeCreated writes the event sending the node to validation to the
+// outbox.
+func (s *nodeService) enqueueNodeCreated(
+	ctx context.Context,
+	node *model.Node,
+	existing bool,
+) error {
+	event, err := outbox.NewEvent(
+		messaging.NodeCreated,
+		messaging.NodeCreatedData{
+			ProfileURL: node.ProfileURL,
+			Version:    *node.Version,
+			Existing:   existing,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := s.outbox.Enqueue(ctx, event); err != nil {
+		return index.DatabaseError{
+			Message: "Error occurred while enqueuing the node created event",
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// isAccepted reports whether the node passed the validation before, so that
+// posting it again isn't blocked by the schemas sunset since then. Only the
+// validation sets the profile hash, and a failed validation clears it.
+func isAccepted(node *model.Node) bool {
+	if node == nil || node.ProfileHash == nil || *node.ProfileHash == "" {
+		return false
+	}
+	// A deleted node is posted again as a new one.
+	return node.Status != constant.NodeStatus.Deleted
+}
+
+// ReplicateNode updates the copy of a node replicated from a peer, given its
+// profile URL, hash, status and source index. A posted node is sent to
+// validation like the nodes posted here, unless its profile is unchanged, and
+// a deleted node is deleted. The nodes posted to this index are left alone.
+func (s *nodeService) ReplicateNode(node *model.Node) error {
+	if err := validateProfileURL(node.ProfileURL); err != nil {
+		return err
+	}
+
+	node.ID = cryptoutil.ComputeSHA256(node.ProfileURL)
+
+	oldNode, err := s.mongoRepo.GetByID(node.ID)
+	if err != nil && !errors.As(err, &index.NotFoundError{}) {
+		return err
+	}
+	if oldNode != nil && oldNode.SourceIndex == "" {
+		return nil
+	}
+
+	switch node.Status {
+	case constant.NodeStatus.Deleted:
+		if oldNode == nil || oldNode.Status == constant.NodeStatus.Deleted {
+			return nil
+		}
+		_, err := s.proceedWithDeletion(oldNode)
+		return err
+	case constant.NodeStatus.Posted:
+		if oldNode != nil &&
+			oldNode.Status == constant.NodeStatus.Posted &&
+			oldNode.ProfileHash != nil && node.ProfileHash != nil &&
+			*oldNode.ProfileHash == *node.ProfileHash {
+			return nil
+		}
+		_, err := s.AddNode(&model.Node{
+			ProfileURL:  node.ProfileURL,
+			SourceIndex: node.SourceIndex,
+		})
+		return err
+	default:
+		return nil
+	}
+}
+
 func validateProfileURL(url string) error {
 	if url == "" {
 		return index.ValidationError{
@@ -211,8 +354,8 @@ func (s *nodeService) GetNode(nodeID string) (*model.Node, error) {
 }
 
 // Search performs a search operation based on the provided query.
-func (s *nodeService) Search(query *es.Query) (*es.QueryResults, error) {
-	result, err := s.elasticRepo.Search(query)
+func (s *nodeService) Search(query *search.Query) (*search.QueryResults, error) {
+	result, err := s.searchRepo.Search(query)
 	if err != nil {
 		return nil, err
 	}
@@ -291,22 +434,22 @@ func (s *nodeService) proceedWithDeletion(node *model.Node) (string, error) {
 		if err := s.mongoRepo.SoftDelete(node); err != nil {
 			return node.ProfileURL, err
 		}
-		err = s.elasticRepo.SoftDelete(node)
+		err = s.searchRepo.SoftDelete(node)
 		return node.ProfileURL, err
 	}
 
 	if err = s.mongoRepo.Delete(node); err != nil {
 		return node.ProfileURL, err
 	}
-	err = s.elasticRepo.DeleteByID(node.ID)
+	err = s.searchRepo.DeleteByID(node.ID)
 	return node.ProfileURL, err
 }
 
 // Export exports nodes based on the provided query.
 func (s *nodeService) Export(
-	query *es.BlockQuery,
-) (*es.BlockQueryResults, error) {
-	result, err := s.elasticRepo.Export(query)
+	query *search.BlockQuery,
+) (*search.BlockQueryResults, error) {
+	result, err := s.searchRepo.Export(query)
 	if err != nil {
 		return nil, err
 	}
@@ -315,9 +458,9 @@ func (s *nodeService) Export(
 
 // Export exports nodes based on the provided query.
 func (s *nodeService) GetNodes(
-	query *es.Query,
-) (*es.MapQueryResults, error) {
-	result, err := s.elasticRepo.GetNodes(query)
+	query *search.Query,
+) (*search.MapQueryResults, error) {
+	result, err := s.searchRepo.GetNodes(query)
 	if err != nil {
 		return nil, err
 	}
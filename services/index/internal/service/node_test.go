@@ -0,0 +1,356 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/messaging"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/outbox"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/config"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/index"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/search"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/service"
+)
+
+// txNodeRepo keeps nodes in memory and discards the ones added in a
+// transaction which fails.
+type txNodeRepo struct {
+	mongo.NodeRepository
+
+	nodes map[string]*model.Node
+}
+
+func (r *txNodeRepo) WithTransaction(
+	fn func(ctx context.Context) error,
+) error {
+	saved := make(map[string]*model.Node, len(r.nodes))
+	for id, node := range r.nodes {
+		saved[id] = node
+	}
+	if err := fn(context.Background()); err != nil {
+		r.nodes = saved
+		return err
+	}
+	return nil
+}
+
+func (r *txNodeRepo) GetByID(nodeID string) (*model.Node, error) {
+	node, ok := r.nodes[nodeID]
+	if !ok {
+		return nil, index.NotFoundError{}
+	}
+	return node, nil
+}
+
+func (r *txNodeRepo) Add(_ context.Context, node *model.Node) error {
+	version := int32(1)
+	if stored, ok := r.nodes[node.ID]; ok {
+		version = *stored.Version + 1
+	}
+	node.Version = &version
+	r.nodes[node.ID] = node
+	return nil
+}
+
+func (r *txNodeRepo) Update(node *model.Node) error {
+	stored, ok := r.nodes[node.ID]
+	if node.Version != nil && (!ok || *stored.Version != *node.Version) {
+		return index.VersionConflictError{
+			NodeID:  node.ID,
+			Version: *node.Version,
+		}
+	}
+	version := int32(1)
+	if ok {
+		version = *stored.Version + 1
+	}
+	updated := *node
+	updated.Version = &version
+	r.nodes[node.ID] = &updated
+	node.Version = &version
+	return nil
+}
+
+// fakeES records the indexed documents.
+type fakeES struct {
+	search.NodeRepository
+
+	docs map[string]any
+	// failures is the number of times indexing fails before it succeeds.
+	failures int
+}
+
+func (e *fakeES) IndexByID(id string, doc any) error {
+	if e.failures > 0 {
+		e.failures--
+		return errors.New("connection refused")
+	}
+	e.docs[id] = doc
+	return nil
+}
+
+func (e *fakeES) DeleteByID(id string) error {
+	if e.failures > 0 {
+		e.failures--
+		return errors.New("connection refused")
+	}
+	delete(e.docs, id)
+	return nil
+}
+
+type fakeOutbox struct {
+	outbox.Store
+
+	events []*outbox.Event
+	err    error
+}
+
+func (o *fakeOutbox) Enqueue(_ context.Context, event *outbox.Event) error {
+	if o.err != nil {
+		return o.err
+	}
+	o.events = append(o.events, event)
+	return nil
+}
+
+func TestAddNode(t *testing.T) {
+	nodeRepo := &txNodeRepo{nodes: map[string]*model.Node{}}
+	events := &fakeOutbox{}
+	svc := service.NewNodeService(nodeRepo, nil, events)
+
+	node, err := svc.AddNode(&model.Node{ProfileURL: "https://example.com/a"})
+	require.NoError(t, err)
+	require.Equal(t, constant.NodeStatus.Received, node.Status)
+	require.Contains(t, nodeRepo.nodes, node.ID)
+
+	require.Len(t, events.events, 1)
+	require.Equal(t, messaging.NodeCreated, events.events[0].Subject)
+	envelope, err := messaging.ParseEnvelope(
+		messaging.NodeCreated,
+		[]byte(events.events[0].Data),
+	)
+	require.NoError(t, err)
+	require.NotEmpty(t, envelope.ID)
+	require.JSONEq(
+		t,
+		`{"profile_url": "https://example.com/a", "version": 1}`,
+		string(envelope.Data),
+	)
+}
+
+func TestAddNode_Existing(t *testing.T) {
+	hash := "8a3b"
+	tests := []struct {
+		name     string
+		node     *model.Node
+		existing bool
+	}{
+		{
+			name: "posted",
+			node: &model.Node{
+				ProfileHash: &hash,
+				Status:      constant.NodeStatus.Posted,
+			},
+			existing: true,
+		},
+		{
+			name:     "never validated",
+			node:     &model.Node{Status: constant.NodeStatus.ValidationFailed},
+			existing: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeRepo := &txNodeRepo{nodes: map[string]*model.Node{}}
+			events := &fakeOutbox{}
+			svc := service.NewNodeService(nodeRepo, nil, events)
+
+			node, err := svc.AddNode(
+				&model.Node{ProfileURL: "https://example.com/a"},
+			)
+			require.NoError(t, err)
+			version := int32(1)
+			tt.node.ID = node.ID
+			tt.node.Version = &version
+			nodeRepo.nodes[node.ID] = tt.node
+			events.events = nil
+
+			// The node is posted again.
+			_, err = svc.AddNode(
+				&model.Node{ProfileURL: "https://example.com/a"},
+			)
+			require.NoError(t, err)
+
+			require.Len(t, events.events, 1)
+			envelope, err := messaging.ParseEnvelope(
+				messaging.NodeCreated,
+				[]byte(events.events[0].Data),
+			)
+			require.NoError(t, err)
+			var data messaging.NodeCreatedData
+			require.NoError(t, json.Unmarshal(envelope.Data, &data))
+			require.Equal(t, tt.existing, data.Existing)
+		})
+	}
+}
+
+func TestAddNode_OutboxFailure(t *testing.T) {
+	nodeRepo := &txNodeRepo{nodes: map[string]*model.Node{}}
+	events := &fakeOutbox{err: errors.New("write conflict")}
+	svc := service.NewNodeService(nodeRepo, nil, events)
+
+	_, err := svc.AddNode(&model.Node{ProfileURL: "https://example.com/a"})
+	require.ErrorAs(t, err, &index.DatabaseError{})
+	// The node isn't saved without its event.
+	require.Empty(t, nodeRepo.nodes)
+}
+
+func TestSetNodeValid(t *testing.T) {
+	nodeRepo := &txNodeRepo{nodes: map[string]*model.Node{}}
+	docs := &fakeES{docs: map[string]any{}}
+	svc := service.NewNodeService(nodeRepo, docs, &fakeOutbox{})
+
+	node, err := svc.AddNode(&model.Node{ProfileURL: "https://example.com/a"})
+	require.NoError(t, err)
+	version := *node.Version
+
+	err = svc.SetNodeValid(validatedNode(version))
+	require.NoError(t, err)
+	require.Equal(t, constant.NodeStatus.Posted, nodeRepo.nodes[node.ID].Status)
+	require.Contains(t, docs.docs, node.ID)
+	doc := docs.docs[node.ID].(map[string]interface{})
+	require.Equal(t, "hash", doc["profile_hash"])
+	require.Equal(t, config.Values.Peering.IndexURL, doc["source_index"])
+
+	// The same event delivered again is stale, the node moved on.
+	delete(docs.docs, node.ID)
+	err = svc.SetNodeValid(validatedNode(version))
+	require.ErrorAs(t, err, &index.VersionConflictError{})
+	require.NotContains(t, docs.docs, node.ID)
+}
+
+func TestSetNodeValid_RedeliveredAfterIndexingFailure(t *testing.T) {
+	nodeRepo := &txNodeRepo{nodes: map[string]*model.Node{}}
+	docs := &fakeES{docs: map[string]any{}, failures: 1}
+	svc := service.NewNodeService(nodeRepo, docs, &fakeOutbox{})
+
+	node, err := svc.AddNode(&model.Node{ProfileURL: "https://example.com/a"})
+	require.NoError(t, err)
+	version := *node.Version
+
+	err = svc.SetNodeValid(validatedEvent("event-1", version))
+	require.Error(t, err)
+	require.Equal(
+		t,
+		constant.NodeStatus.PostFailed,
+		nodeRepo.nodes[node.ID].Status,
+	)
+
+	// The redelivered event carries on from the node it changed.
+	err = svc.SetNodeValid(validatedEvent("event-1", version))
+	require.NoError(t, err)
+	require.Equal(t, constant.NodeStatus.Posted, nodeRepo.nodes[node.ID].Status)
+	require.Contains(t, docs.docs, node.ID)
+
+	// Delivered once more, there is nothing left to do.
+	err = svc.SetNodeValid(validatedEvent("event-1", version))
+	require.NoError(t, err)
+
+	// Another event for the same version is still stale.
+	err = svc.SetNodeValid(validatedEvent("event-2", version))
+	require.ErrorAs(t, err, &index.VersionConflictError{})
+}
+
+func TestSetNodeValid_RedeliveredAfterNodeChanged(t *testing.T) {
+	nodeRepo := &txNodeRepo{nodes: map[string]*model.Node{}}
+	docs := &fakeES{docs: map[string]any{}, failures: 1}
+	svc := service.NewNodeService(nodeRepo, docs, &fakeOutbox{})
+
+	node, err := svc.AddNode(&model.Node{ProfileURL: "https://example.com/a"})
+	require.NoError(t, err)
+	version := *node.Version
+
+	err = svc.SetNodeValid(validatedEvent("event-1", version))
+	require.Error(t, err)
+
+	// The node is posted again before the event is delivered again.
+	_, err = svc.AddNode(&model.Node{ProfileURL: "https://example.com/a"})
+	require.NoError(t, err)
+
+	err = svc.SetNodeValid(validatedEvent("event-1", version))
+	require.ErrorAs(t, err, &index.VersionConflictError{})
+	require.Equal(t, constant.NodeStatus.Received, nodeRepo.nodes[node.ID].Status)
+	require.NotContains(t, docs.docs, node.ID)
+}
+
+func TestSetNodeInvalid_RedeliveredAfterDeleteFailure(t *testing.T) {
+	nodeRepo := &txNodeRepo{nodes: map[string]*model.Node{}}
+	docs := &fakeES{docs: map[string]any{}}
+	svc := service.NewNodeService(nodeRepo, docs, &fakeOutbox{})
+
+	node, err := svc.AddNode(&model.Node{ProfileURL: "https://example.com/a"})
+	require.NoError(t, err)
+	docs.docs[node.ID] = map[string]interface{}{}
+	docs.failures = 1
+
+	invalid := func() *model.Node {
+		return &model.Node{
+			ProfileURL: "https://example.com/a",
+			Version:    node.Version,
+			LastEvent:  &model.NodeEvent{ID: "event-1"},
+		}
+	}
+	require.Error(t, svc.SetNodeInvalid(invalid()))
+	require.Contains(t, docs.docs, node.ID)
+
+	require.NoError(t, svc.SetNodeInvalid(invalid()))
+	require.NotContains(t, docs.docs, node.ID)
+}
+
+func TestSetNodeInvalid_StaleVersion(t *testing.T) {
+	nodeRepo := &txNodeRepo{nodes: map[string]*model.Node{}}
+	svc := service.NewNodeService(nodeRepo, nil, &fakeOutbox{})
+
+	node, err := svc.AddNode(&model.Node{ProfileURL: "https://example.com/a"})
+	require.NoError(t, err)
+	// The node was posted again since it was validated.
+	_, err = svc.AddNode(&model.Node{ProfileURL: "https://example.com/a"})
+	require.NoError(t, err)
+
+	err = svc.SetNodeInvalid(&model.Node{
+		ProfileURL: "https://example.com/a",
+		Version:    node.Version,
+	})
+	require.ErrorAs(t, err, &index.VersionConflictError{})
+	require.Equal(
+		t,
+		constant.NodeStatus.Received,
+		nodeRepo.nodes[node.ID].Status,
+	)
+}
+
+func validatedNode(version int32) *model.Node {
+	hash := "hash"
+	lastUpdated := int64(1700000000)
+	return &model.Node{
+		ProfileURL:  "https://example.com/a",
+		ProfileHash: &hash,
+		ProfileStr:  `{"name": "A"}`,
+		LastUpdated: &lastUpdated,
+		Version:     &version,
+	}
+}
+
+func validatedEvent(eventID string, version int32) *model.Node {
+	node := validatedNode(version)
+	node.LastEvent = &model.NodeEvent{ID: eventID}
+	return node
+}
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/cryptoutil"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/dateutil"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/config"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/index"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/search"
+)
+
+// exportPageSize is the number of nodes fetched at once from a peer.
+const exportPageSize = 500
+
+// exportTimeout bounds a request to the export endpoint of a peer.
+const exportTimeout = 30 * time.Second
+
+// PeerService manages the federation of the index with other indices.
+//
+// The nodes of the upstream peers are replicated by reading their export
+// endpoint, which returns the nodes ordered by last update. Only the metadata
+// of the nodes is replicated, their profiles are validated again here. The
+// nodes keep the URL of the index they were posted to as their source index,
+// so that the nodes of the index coming back through another peer are
+// skipped.
+type PeerService interface {
+	// AddPeer registers a peer, or changes its direction.
+	AddPeer(peer *model.Peer) (*model.Peer, error)
+	GetPeers() ([]*model.Peer, error)
+	DeletePeer(peerID string) error
+	// Replicate replicates the nodes of the upstream peers which changed
+	// since the previous replication.
+	Replicate(ctx context.Context) error
+}
+
+type peerService struct {
+	peerRepo mongo.PeerRepository
+	nodeSvc  NodeService
+	client   *http.Client
+}
+
+// NewPeerService creates a new instance of PeerService.
+func NewPeerService(
+	peerRepo mongo.PeerRepository,
+	nodeSvc NodeService,
+) PeerService {
+	return &peerService{
+		peerRepo: peerRepo,
+		nodeSvc:  nodeSvc,
+		client:   &http.Client{Timeout: exportTimeout},
+	}
+}
+
+// AddPeer registers a peer, or changes its direction.
+func (s *peerService) AddPeer(peer *model.Peer) (*model.Peer, error) {
+	peer.URL = strings.TrimSuffix(peer.URL, "/")
+	if err := validatePeer(peer); err != nil {
+		return nil, err
+	}
+
+	peer.ID = cryptoutil.ComputeSHA256(peer.URL)
+	peer.CreatedAt = dateutil.GetNowUnix()
+	if err := s.peerRepo.Add(peer); err != nil {
+		return nil, err
+	}
+	return peer, nil
+}
+
+func validatePeer(peer *model.Peer) error {
+	u, err := url.Parse(peer.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") ||
+		u.Host == "" {
+		return index.ValidationError{
+			Field:  "URL",
+			Reason: "The `url` property must be the URL of an index.",
+		}
+	}
+	if peer.URL == strings.TrimSuffix(config.Values.Peering.IndexURL, "/") {
+		return index.ValidationError{
+			Field:  "URL",
+			Reason: "The index can't be its own peer.",
+		}
+	}
+	if peer.Direction != constant.PeerDirection.Upstream &&
+		peer.Direction != constant.PeerDirection.Downstream {
+		return index.ValidationError{
+			Field: "Direction",
+			Reason: fmt.Sprintf(
+				"The `direction` property must be `%s` or `%s`.",
+				constant.PeerDirection.Upstream,
+				constant.PeerDirection.Downstream,
+			),
+		}
+	}
+	return nil
+}
+
+// GetPeers returns the registered peers.
+func (s *peerService) GetPeers() ([]*model.Peer, error) {
+	return s.peerRepo.GetAll()
+}
+
+// DeletePeer removes a peer. The nodes replicated from it are kept.
+func (s *peerService) DeletePeer(peerID string) error {
+	return s.peerRepo.Delete(peerID)
+}
+
+// Replicate replicates the nodes of the upstream peers which changed since the
+// previous replication. A peer failing doesn't prevent replicating the others.
+func (s *peerService) Replicate(ctx context.Context) error {
+	peers, err := s.peerRepo.GetAll()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, peer := range peers {
+		if peer.Direction != constant.PeerDirection.Upstream {
+			continue
+		}
+		if err := s.replicatePeer(ctx, peer); err != nil {
+			errs = append(errs, fmt.Errorf("peer %s: %w", peer.URL, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// replicatePeer replicates the nodes of the peer page by page, recording the
+// cursor after each page so that the next replication resumes from there.
+func (s *peerService) replicatePeer(
+	ctx context.Context,
+	peer *model.Peer,
+) error {
+	for {
+		page, err := s.fetchExport(ctx, peer)
+		if err == nil {
+			err = s.replicateNodes(peer, page.Result)
+		}
+
+		now := dateutil.GetNowUnix()
+		peer.LastSynced = &now
+		peer.LastError = ""
+		if err != nil {
+			peer.LastError = err.Error()
+		} else if len(page.Sort) > 0 {
+			peer.Cursor = page.Sort
+		}
+		if updateErr := s.peerRepo.UpdateSync(peer); updateErr != nil {
+			return updateErr
+		}
+
+		if err != nil || len(page.Result) < exportPageSize {
+			return err
+		}
+	}
+}
+
+// replicateNodes replicates a page of the nodes exported by the peer.
+func (s *peerService) replicateNodes(
+	peer *model.Peer,
+	results []search.QueryResult,
+) error {
+	ownURL := strings.TrimSuffix(config.Values.Peering.IndexURL, "/")
+
+	for _, result := range results {
+		profileURL, _ := result["profile_url"].(string)
+		status, _ := result["status"].(string)
+		source, _ := result["source_index"].(string)
+		// The peer doesn't record the source of its own nodes.
+		if source == "" {
+			source = peer.URL
+		}
+		// The node was posted here and came back through the federation.
+		if strings.TrimSuffix(source, "/") == ownURL {
+			continue
+		}
+
+		node := &model.Node{
+			ProfileURL:  profileURL,
+			Status:      status,
+			SourceIndex: source,
+		}
+		if hash, ok := result["profile_hash"].(string); ok {
+			node.ProfileHash = &hash
+		}
+
+		err := s.nodeSvc.ReplicateNode(node)
+		if errors.As(err, &index.ValidationError{}) {
+			logger.Info(fmt.Sprintf(
+				"Skipping node %q of peer %s: %s",
+				profileURL,
+				peer.URL,
+				err,
+			))
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportResponse is the response of the export endpoint.
+type exportResponse struct {
+	Data []search.QueryResult `json:"data"`
+	Meta struct {
+		Sort []interface{} `json:"sort"`
+	} `json:"meta"`
+}
+
+// fetchExport returns the nodes exported by the peer after its cursor.
+func (s *peerService) fetchExport(
+	ctx context.Context,
+	peer *model.Peer,
+) (*search.BlockQueryResults, error) {
+	body, err := json.Marshal(search.BlockQuery{
+		PageSize:    exportPageSize,
+		SearchAfter: peer.Cursor,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		peer.URL+"/v2/export",
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf(
+			"export endpoint returned status %d",
+			resp.StatusCode,
+		)
+	}
+
+	var res exportResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("invalid export response: %w", err)
+	}
+	return &search.BlockQueryResults{
+		Result: res.Data,
+		Sort:   res.Meta.Sort,
+	}, nil
+}
@@ -0,0 +1,226 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/cryptoutil"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/config"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/index"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/service"
+)
+
+const ownIndexURL = "https://index.example.org"
+
+// fakePeerRepo keeps the peers in memory.
+type fakePeerRepo struct {
+	mongo.PeerRepository
+
+	peers []*model.Peer
+}
+
+func (r *fakePeerRepo) Add(peer *model.Peer) error {
+	r.peers = append(r.peers, peer)
+	return nil
+}
+
+func (r *fakePeerRepo) GetAll() ([]*model.Peer, error) {
+	return r.peers, nil
+}
+
+func (r *fakePeerRepo) UpdateSync(*model.Peer) error {
+	return nil
+}
+
+func (r *txNodeRepo) Delete(node *model.Node) error {
+	delete(r.nodes, node.ID)
+	return nil
+}
+
+// exportServer serves the export endpoint of a peer, recording the cursors
+// it is given. The body is returned from the start, nothing after it.
+func exportServer(
+	t *testing.T,
+	status int,
+	body string,
+	cursors *[][]interface{},
+) *httptest.Server {
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/v2/export", r.URL.Path)
+			var query struct {
+				PageSize    int64         `json:"page_size"`
+				SearchAfter []interface{} `json:"search_after"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&query))
+			require.EqualValues(t, 500, query.PageSize)
+			*cursors = append(*cursors, query.SearchAfter)
+
+			w.WriteHeader(status)
+			if query.SearchAfter != nil && status == http.StatusOK {
+				_, _ = w.Write([]byte(`{"data": []}`))
+				return
+			}
+			_, _ = w.Write([]byte(body))
+		}),
+	)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func nodeID(profileURL string) string {
+	return cryptoutil.ComputeSHA256(profileURL)
+}
+
+func TestReplicate(t *testing.T) {
+	config.Values.Peering.IndexURL = ownIndexURL
+
+	var cursors [][]interface{}
+	server := exportServer(t, http.StatusOK, `{
+		"data": [
+			{"profile_url": "https://example.com/a", "status": "posted",
+				"profile_hash": "a"},
+			{"profile_url": "https://example.com/b", "status": "posted",
+				"source_index": "https://index.example.org"},
+			{"profile_url": "https://example.com/c", "status": "posted",
+				"source_index": "https://other.example.org"},
+			{"profile_url": "https://example.com/d", "status": "deleted",
+				"source_index": "https://other.example.org"}
+		],
+		"meta": {"sort": [1700000000, "https://example.com/d"]}
+	}`, &cursors)
+
+	nodeRepo := &txNodeRepo{nodes: map[string]*model.Node{
+		// Posted to this index.
+		nodeID("https://example.com/c"): {
+			ID:     nodeID("https://example.com/c"),
+			Status: constant.NodeStatus.Posted,
+		},
+		// Replicated before.
+		nodeID("https://example.com/d"): {
+			ID:          nodeID("https://example.com/d"),
+			Status:      constant.NodeStatus.Received,
+			SourceIndex: "https://other.example.org",
+		},
+	}}
+	events := &fakeOutbox{}
+	peer := &model.Peer{
+		URL:       server.URL,
+		Direction: constant.PeerDirection.Upstream,
+	}
+	svc := service.NewPeerService(
+		&fakePeerRepo{peers: []*model.Peer{
+			peer,
+			{URL: "http://localhost:1", Direction: "downstream"},
+		}},
+		service.NewNodeService(
+			nodeRepo,
+			&fakeES{docs: map[string]any{}},
+			events,
+		),
+	)
+
+	require.NoError(t, svc.Replicate(context.Background()))
+
+	// The new node is sent to validation, with the peer as its source.
+	a := nodeRepo.nodes[nodeID("https://example.com/a")]
+	require.NotNil(t, a)
+	require.Equal(t, constant.NodeStatus.Received, a.Status)
+	require.Equal(t, server.URL, a.SourceIndex)
+	require.Len(t, events.events, 1)
+
+	// The nodes of this index aren't replicated back.
+	require.NotContains(t, nodeRepo.nodes, nodeID("https://example.com/b"))
+	require.Equal(
+		t,
+		constant.NodeStatus.Posted,
+		nodeRepo.nodes[nodeID("https://example.com/c")].Status,
+	)
+	require.NotContains(t, nodeRepo.nodes, nodeID("https://example.com/d"))
+
+	require.Equal(t, [][]interface{}{nil}, cursors)
+	require.Equal(
+		t,
+		[]interface{}{float64(1700000000), "https://example.com/d"},
+		peer.Cursor,
+	)
+	require.NotNil(t, peer.LastSynced)
+	require.Empty(t, peer.LastError)
+
+	// The next replication resumes after the last node.
+	cursor := peer.Cursor
+	require.NoError(t, svc.Replicate(context.Background()))
+	require.Equal(t, cursor, cursors[1])
+	require.Equal(t, cursor, peer.Cursor)
+	require.Len(t, events.events, 1)
+}
+
+func TestReplicate_PeerFailure(t *testing.T) {
+	config.Values.Peering.IndexURL = ownIndexURL
+
+	var cursors [][]interface{}
+	server := exportServer(t, http.StatusInternalServerError, `{}`, &cursors)
+	cursor := []interface{}{float64(1700000000), "https://example.com/a"}
+	peer := &model.Peer{
+		URL:       server.URL,
+		Direction: constant.PeerDirection.Upstream,
+		Cursor:    cursor,
+	}
+	svc := service.NewPeerService(
+		&fakePeerRepo{peers: []*model.Peer{peer}},
+		service.NewNodeService(
+			&txNodeRepo{nodes: map[string]*model.Node{}},
+			nil,
+			&fakeOutbox{},
+		),
+	)
+
+	require.Error(t, svc.Replicate(context.Background()))
+	require.NotEmpty(t, peer.LastError)
+	require.Equal(t, cursor, peer.Cursor)
+}
+
+func TestAddPeer(t *testing.T) {
+	config.Values.Peering.IndexURL = ownIndexURL
+	svc := service.NewPeerService(&fakePeerRepo{}, nil)
+
+	peer, err := svc.AddPeer(&model.Peer{
+		URL:       "https://regional.example.org/",
+		Direction: constant.PeerDirection.Upstream,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "https://regional.example.org", peer.URL)
+	require.Equal(t, nodeID("https://regional.example.org"), peer.ID)
+
+	tests := []struct {
+		name string
+		peer *model.Peer
+	}{
+		{
+			name: "itself",
+			peer: &model.Peer{URL: ownIndexURL, Direction: "upstream"},
+		},
+		{
+			name: "invalid url",
+			peer: &model.Peer{URL: "regional", Direction: "upstream"},
+		},
+		{
+			name: "invalid direction",
+			peer: &model.Peer{URL: "https://a.example.org", Direction: "up"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.AddPeer(tt.peer)
+			require.ErrorAs(t, err, &index.ValidationError{})
+		})
+	}
+}
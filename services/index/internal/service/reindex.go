@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/elastic"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/es"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/search"
+)
+
+// reindexBatchSize is the number of nodes copied per bulk request.
+const reindexBatchSize = 500
+
+// indexedStatuses are the statuses of the nodes present in Elasticsearch.
+var indexedStatuses = []string{
+	constant.NodeStatus.Posted,
+	constant.NodeStatus.Deleted,
+}
+
+// ReindexOptions controls a reindex run.
+type ReindexOptions struct {
+	// DeleteOld deletes the indices previously served under the alias once the
+	// new version is live.
+	DeleteOld bool
+}
+
+// ReindexResult summarizes a reindex run.
+type ReindexResult struct {
+	// Index is the concrete index now served under the alias.
+	Index string
+	// Indexed is the number of documents written to the new index.
+	Indexed int
+	// Missing lists the posted nodes that had no document to copy.
+	Missing []string
+	// Copied is the number of documents copied from the live index, for the
+	// nodes posted before their profile was stored. They get the fields
+	// added to the mappings when they are posted again.
+	Copied int
+	// Skipped is true when the alias already served the target version.
+	Skipped bool
+}
+
+// ReindexService rebuilds the node index with the current mappings.
+type ReindexService interface {
+	Reindex(ctx context.Context, opts ReindexOptions) (*ReindexResult, error)
+}
+
+type reindexService struct {
+	mongoRepo mongo.NodeRepository
+	indexRepo es.IndexRepository
+}
+
+// NewReindexService creates a new instance of ReindexService.
+func NewReindexService(
+	mongoRepo mongo.NodeRepository,
+	indexRepo es.IndexRepository,
+) ReindexService {
+	return &reindexService{
+		mongoRepo: mongoRepo,
+		indexRepo: indexRepo,
+	}
+}
+
+// Reindex builds the target version of the node index from the nodes stored in
+// MongoDB and swaps the alias to it.
+//
+// MongoDB decides which nodes are indexed, and each document is rebuilt from
+// the profile stored with the node, so fields added to the mappings are filled
+// in. The nodes posted before their profile was stored are copied from the
+// live index instead. Searches keep hitting the live index while the new one
+// is built; nodes changed in the meantime are copied again right before the
+// swap, and once more right after it for the writes that still went to the
+// previous index.
+func (s *reindexService) Reindex(
+	ctx context.Context,
+	opts ReindexOptions,
+) (*ReindexResult, error) {
+	live, err := s.indexRepo.Live()
+	if err != nil {
+		return nil, err
+	}
+	target := es.NodeIndex.VersionedName()
+	if len(live) == 1 && live[0] == target {
+		return &ReindexResult{Index: target, Skipped: true}, nil
+	}
+
+	// Drop what a previous, interrupted run may have left behind.
+	exists, err := s.indexRepo.Exists()
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		if err := s.indexRepo.Delete(target); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.indexRepo.Create(); err != nil {
+		return nil, err
+	}
+
+	result := &ReindexResult{Index: target}
+
+	// First pass: copy every node and remember the version copied.
+	versions := make(map[string]int32)
+	err = s.mongoRepo.ForEachBatch(
+		ctx,
+		indexedStatuses,
+		reindexBatchSize,
+		func(nodes []*model.Node) error {
+			return s.copyNodes(nodes, versions, result)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Second pass: catch up with the nodes changed during the first one.
+	if err := s.catchUp(ctx, versions, result); err != nil {
+		return nil, err
+	}
+
+	if err := s.indexRepo.Publish(); err != nil {
+		return nil, err
+	}
+	logger.Info(fmt.Sprintf("Alias %s now points at %s", es.NodeIndex.Name, target))
+
+	// Final pass: the nodes changed between the second pass and the swap
+	// were written to the previous index. MongoDB is updated before the
+	// index, so their new version is already visible.
+	if err := s.catchUp(ctx, versions, result); err != nil {
+		return nil, err
+	}
+
+	if opts.DeleteOld {
+		for _, name := range live {
+			// An unversioned index named like the alias is removed by the swap.
+			if name == es.NodeIndex.Name {
+				continue
+			}
+			if err := s.indexRepo.Delete(name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// catchUp copies the nodes whose version changed since they were copied, and
+// removes the ones which are no longer indexed.
+func (s *reindexService) catchUp(
+	ctx context.Context,
+	versions map[string]int32,
+	result *ReindexResult,
+) error {
+	seen := make(map[string]bool, len(versions))
+	err := s.mongoRepo.ForEachBatch(
+		ctx,
+		indexedStatuses,
+		reindexBatchSize,
+		func(nodes []*model.Node) error {
+			changed := make([]*model.Node, 0)
+			for _, node := range nodes {
+				seen[node.ID] = true
+				if version, ok := versions[node.ID]; !ok ||
+					version != nodeVersion(node) {
+					changed = append(changed, node)
+				}
+			}
+			return s.copyNodes(changed, versions, result)
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	for id := range versions {
+		if seen[id] {
+			continue
+		}
+		if err := s.indexRepo.DeleteDocument(id); err != nil {
+			return err
+		}
+		delete(versions, id)
+	}
+	return nil
+}
+
+// copyNodes writes the documents of the nodes into the new index. They are
+// rebuilt from the stored profiles, or copied from the live index for the
+// nodes which have none, overriding the fields MongoDB is the source of truth
+// for.
+func (s *reindexService) copyNodes(
+	nodes []*model.Node,
+	versions map[string]int32,
+	result *ReindexResult,
+) error {
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0)
+	for _, node := range nodes {
+		if node.ProfileStr == "" {
+			ids = append(ids, node.ID)
+		}
+	}
+	liveDocs := make(map[string]search.QueryResult)
+	if len(ids) > 0 {
+		var err error
+		liveDocs, err = s.indexRepo.GetLiveDocuments(ids)
+		if err != nil {
+			return err
+		}
+	}
+
+	docs := make([]elastic.Document, 0, len(nodes))
+	for _, node := range nodes {
+		versions[node.ID] = nodeVersion(node)
+
+		doc, err := s.nodeDocument(node, liveDocs, result)
+		if err != nil {
+			return err
+		}
+		if doc == nil {
+			continue
+		}
+		docs = append(docs, elastic.Document{ID: node.ID, Body: doc})
+	}
+
+	if err := s.indexRepo.IndexDocuments(docs); err != nil {
+		return err
+	}
+	result.Indexed += len(docs)
+	return nil
+}
+
+// nodeDocument returns the document of the node in the new index, or nil when
+// the node has nothing to index.
+func (s *reindexService) nodeDocument(
+	node *model.Node,
+	liveDocs map[string]search.QueryResult,
+	result *ReindexResult,
+) (map[string]interface{}, error) {
+	if node.ProfileStr != "" {
+		doc, err := searchDocument(node)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to rebuild the document of node %s: %w",
+				node.ID,
+				err,
+			)
+		}
+		doc["status"] = node.Status
+		return doc, nil
+	}
+
+	doc, ok := liveDocs[node.ID]
+	if !ok {
+		if node.Status != constant.NodeStatus.Deleted {
+			result.Missing = append(result.Missing, node.ID)
+			return nil, nil
+		}
+		doc = search.QueryResult{}
+	} else {
+		result.Copied++
+	}
+
+	doc["profile_url"] = node.ProfileURL
+	doc["status"] = node.Status
+	doc["last_updated"] = node.LastUpdated
+	if node.Expires != nil {
+		doc["expires"] = *node.Expires
+	}
+	if node.ProfileHash != nil {
+		doc["profile_hash"] = *node.ProfileHash
+	}
+	doc["source_index"] = sourceIndex(node)
+	return doc, nil
+}
+
+func nodeVersion(node *model.Node) int32 {
+	if node.Version == nil {
+		return 0
+	}
+	return *node.Version
+}
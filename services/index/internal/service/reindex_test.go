@@ -0,0 +1,218 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/elastic"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/es"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/search"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/service"
+)
+
+type fakeNodeRepo struct {
+	mongo.NodeRepository
+
+	nodes []*model.Node
+	// afterPass is called once each pass over the nodes is done.
+	afterPass func(r *fakeNodeRepo, pass int)
+	passes    int
+}
+
+func (r *fakeNodeRepo) ForEachBatch(
+	_ context.Context,
+	_ []string,
+	_ int,
+	handle func(nodes []*model.Node) error,
+) error {
+	nodes := make([]*model.Node, len(r.nodes))
+	copy(nodes, r.nodes)
+	if err := handle(nodes); err != nil {
+		return err
+	}
+	r.passes++
+	if r.afterPass != nil {
+		r.afterPass(r, r.passes)
+	}
+	return nil
+}
+
+type fakeIndexRepo struct {
+	live      []string
+	liveDocs  map[string]search.QueryResult
+	target    map[string]interface{}
+	created   bool
+	published bool
+	deleted   []string
+}
+
+func (r *fakeIndexRepo) Live() ([]string, error) { return r.live, nil }
+
+func (r *fakeIndexRepo) Exists() (bool, error) { return false, nil }
+
+func (r *fakeIndexRepo) Create() error {
+	r.created = true
+	r.target = make(map[string]interface{})
+	return nil
+}
+
+func (r *fakeIndexRepo) Delete(name string) error {
+	r.deleted = append(r.deleted, name)
+	return nil
+}
+
+func (r *fakeIndexRepo) GetLiveDocuments(
+	ids []string,
+) (map[string]search.QueryResult, error) {
+	docs := make(map[string]search.QueryResult)
+	for _, id := range ids {
+		if doc, ok := r.liveDocs[id]; ok {
+			copied := search.QueryResult{}
+			for k, v := range doc {
+				copied[k] = v
+			}
+			docs[id] = copied
+		}
+	}
+	return docs, nil
+}
+
+func (r *fakeIndexRepo) IndexDocuments(docs []elastic.Document) error {
+	for _, doc := range docs {
+		r.target[doc.ID] = doc.Body
+	}
+	return nil
+}
+
+func (r *fakeIndexRepo) DeleteDocument(id string) error {
+	delete(r.target, id)
+	return nil
+}
+
+func (r *fakeIndexRepo) Publish() error {
+	r.published = true
+	return nil
+}
+
+func newNode(id, status string, version int32) *model.Node {
+	lastUpdated := int64(100)
+	return &model.Node{
+		ID:          id,
+		ProfileURL:  "https://example.com/" + id,
+		Status:      status,
+		LastUpdated: &lastUpdated,
+		Version:     &version,
+	}
+}
+
+func newNodeWithProfile(
+	id, status string,
+	version int32,
+	profile string,
+) *model.Node {
+	node := newNode(id, status, version)
+	node.ProfileStr = profile
+	return node
+}
+
+func TestReindex(t *testing.T) {
+	nodeRepo := &fakeNodeRepo{
+		nodes: []*model.Node{
+			newNode("a", constant.NodeStatus.Posted, 1),
+			newNode("b", constant.NodeStatus.Deleted, 1),
+			newNode("c", constant.NodeStatus.Posted, 1),
+			newNode("d", constant.NodeStatus.Posted, 1),
+			newNodeWithProfile(
+				"e", constant.NodeStatus.Posted, 1,
+				`{"name": "E", "name_i18n": {"fr-CA": "É"}}`,
+			),
+		},
+		afterPass: func(r *fakeNodeRepo, pass int) {
+			switch pass {
+			case 1:
+				// "a" is updated and "d" removed while the first pass runs.
+				r.nodes[0] = newNode("a", constant.NodeStatus.Deleted, 2)
+				r.nodes = append(r.nodes[:3], r.nodes[4])
+			case 2:
+				// "e" is posted again between the second pass and the swap.
+				r.nodes[3] = newNodeWithProfile(
+					"e", constant.NodeStatus.Posted, 2, `{"name": "E2"}`,
+				)
+			}
+		},
+	}
+	indexRepo := &fakeIndexRepo{
+		live: []string{"nodes"},
+		liveDocs: map[string]search.QueryResult{
+			"a": {"name": "A", "status": "posted"},
+			"d": {"name": "D"},
+			"e": {"name": "E"},
+		},
+	}
+
+	result, err := service.NewReindexService(nodeRepo, indexRepo).
+		Reindex(context.Background(), service.ReindexOptions{DeleteOld: true})
+	require.NoError(t, err)
+
+	require.True(t, indexRepo.created)
+	require.True(t, indexRepo.published)
+	require.Equal(t, 3, nodeRepo.passes)
+	require.Equal(t, es.NodeIndex.VersionedName(), result.Index)
+	// "c" is posted but has no document to copy.
+	require.Equal(t, []string{"c"}, result.Missing)
+	// The unversioned index is dropped by the alias swap itself.
+	require.Empty(t, indexRepo.deleted)
+
+	require.Len(t, indexRepo.target, 3)
+	a := indexRepo.target["a"].(map[string]interface{})
+	require.Equal(t, "A", a["name"])
+	require.Equal(t, constant.NodeStatus.Deleted, a["status"])
+	b := indexRepo.target["b"].(map[string]interface{})
+	require.Equal(t, constant.NodeStatus.Deleted, b["status"])
+	require.Equal(t, "https://example.com/b", b["profile_url"])
+	// "e" is rebuilt from its stored profile, not copied from the live
+	// index, and its last change before the swap isn't lost.
+	e := indexRepo.target["e"].(map[string]interface{})
+	require.Equal(t, "E2", e["name"])
+	require.Equal(t, constant.NodeStatus.Posted, e["status"])
+	require.Equal(t, "https://example.com/e", e["profile_url"])
+}
+
+func TestReindexRebuildsFromProfile(t *testing.T) {
+	nodeRepo := &fakeNodeRepo{
+		nodes: []*model.Node{
+			newNodeWithProfile(
+				"a", constant.NodeStatus.Posted, 1,
+				`{"name": "A", "name_i18n": {"fr-CA": "Â"}}`,
+			),
+		},
+	}
+	// The live document predates the "name_i18n" mapping.
+	indexRepo := &fakeIndexRepo{
+		live:     []string{"nodes"},
+		liveDocs: map[string]search.QueryResult{"a": {"name": "A"}},
+	}
+
+	result, err := service.NewReindexService(nodeRepo, indexRepo).
+		Reindex(context.Background(), service.ReindexOptions{})
+	require.NoError(t, err)
+	require.Zero(t, result.Copied)
+
+	a := indexRepo.target["a"].(map[string]interface{})
+	require.Equal(t, map[string]interface{}{"fr": "Â"}, a["name_i18n"])
+}
+
+func TestReindexAlreadyLive(t *testing.T) {
+	indexRepo := &fakeIndexRepo{live: []string{es.NodeIndex.VersionedName()}}
+
+	result, err := service.NewReindexService(&fakeNodeRepo{}, indexRepo).
+		Reindex(context.Background(), service.ReindexOptions{})
+	require.NoError(t, err)
+	require.True(t, result.Skipped)
+	require.False(t, indexRepo.created)
+}
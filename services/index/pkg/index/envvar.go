@@ -10,6 +10,7 @@ import (
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/elastic"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/config"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/es"
 )
 
 func init() {
@@ -17,78 +18,22 @@ func init() {
 	if err != nil {
 		log.Fatalf("Failed to decode environment variables: %s", err)
 	}
-	setupElasticsearch()
+	switch config.Values.Search.Backend {
+	case constant.SearchBackend.Elasticsearch:
+		setupElasticsearch()
+	case constant.SearchBackend.Mongo:
+	default:
+		log.Fatalf(
+			"Unknown search backend %q, expected %q or %q",
+			config.Values.Search.Backend,
+			constant.SearchBackend.Elasticsearch,
+			constant.SearchBackend.Mongo,
+		)
+	}
 }
 
 // setupElasticsearch initializes Elasticsearch service and sets up necessary indices.
 func setupElasticsearch() {
-	var indices = []elastic.Index{
-		{
-			Name: constant.ESIndex.Node,
-			Body: `{
-				"mappings": {
-					"dynamic": "false",
-					"_source": {
-						"includes": [
-							"name",
-							"geolocation",
-							"last_updated",
-							"linked_schemas",
-							"country",
-							"locality",
-							"region",
-							"profile_url",
-							"status",
-							"tags",
-							"primary_url",
-							"expires"
-						]
-					},
-					"properties": {
-						"name": {
-							"type": "text"
-						},
-						"geolocation": {
-							"type": "geo_point"
-						},
-						"last_updated": {
-							"type": "date",
-							"format": "epoch_second"
-						},
-						"linked_schemas": {
-							"type": "keyword"
-						},
-						"country": {
-							"type": "text"
-						},
-						"locality": {
-							"type": "text"
-						},
-						"region": {
-							"type": "text"
-						},
-						"profile_url": {
-							"type": "keyword"
-						},
-						"status": {
-							"type": "keyword"
-						},
-						"tags": {
-							"type": "text"
-						},
-						"primary_url": {
-							"type": "keyword"
-						},
-						"expires": {
-							"type": "date",
-							"format": "epoch_second"
-						}
-					}
-				}
-			}`,
-		},
-	}
-
 	// Initialize a new Elasticsearch client.
 	err := elastic.NewClient(config.Values.ES.URL)
 	if err != nil {
@@ -97,7 +42,7 @@ func setupElasticsearch() {
 	}
 
 	// Create indices in Elasticsearch.
-	err = elastic.Client.CreateMappings(indices)
+	err = elastic.Client.CreateMappings([]elastic.Index{es.NodeIndex})
 	if err != nil {
 		logger.Error("Failed to create index mappings for Elasticsearch", err)
 		os.Exit(1)
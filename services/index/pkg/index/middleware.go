@@ -1,10 +1,14 @@
 package index
 
 import (
+	"crypto/subtle"
 	"net/http"
 	"os"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/config"
 )
 
 func AllowInNonProductionMiddleware() gin.HandlerFunc {
@@ -23,3 +27,21 @@ func AllowInNonProductionMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// PeeringTokenMiddleware only lets through the requests with the peering token
+// as their bearer token. All the requests are refused when no token is set.
+func PeeringTokenMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := config.Values.Peering.Token
+		given := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if token == "" ||
+			subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(
+				http.StatusForbidden,
+				gin.H{"error": "Invalid peering token"},
+			)
+			return
+		}
+		c.Next()
+	}
+}
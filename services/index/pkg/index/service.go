@@ -15,18 +15,23 @@ import (
 	"github.com/tevino/abool/v2"
 	"go.uber.org/zap/zapcore"
 
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/core"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/handler"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/messaging"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/middleware/limiter"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/middleware/localizer"
 	midlogger "github.com/MurmurationsNetwork/MurmurationsServices/pkg/middleware/logger"
 	mongodb "github.com/MurmurationsNetwork/MurmurationsServices/pkg/mongo"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/natsclient"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/outbox"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/config"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/controller/event"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/controller/rest"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/es"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/search"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/service"
 )
 
@@ -36,6 +41,10 @@ type Service struct {
 	server *http.Server
 	// Node event handler
 	nodeHandler event.NodeHandler
+	// Relay publishing the events of the outbox
+	relay *outbox.Relay
+	// Peer service replicating the upstream peers
+	peerService service.PeerService
 	// Atomic boolean to manage service state
 	run *abool.AtomicBool
 	// HTTP router for the index service
@@ -57,19 +66,56 @@ func NewService() *Service {
 	svc.setupNATS()
 
 	svc.setupServer()
+
+	eventRepo := mongo.NewEventRepository()
+	if err := eventRepo.EnsureIndexes(context.Background()); err != nil {
+		svc.panic("error when trying to create the MongoDB indexes", err)
+	}
+	outboxStore := outbox.NewStore(constant.MongoIndex.Outbox)
+	if err := outboxStore.EnsureIndexes(context.Background()); err != nil {
+		svc.panic("error when trying to create the outbox indexes", err)
+	}
+
 	svc.nodeHandler = event.NewNodeHandler(
 		service.NewNodeService(
 			mongo.NewNodeRepository(),
-			es.NewNodeRepository(),
+			newSearchRepository(),
+			outboxStore,
+		),
+		service.NewEventService(eventRepo),
+	)
+	svc.peerService = service.NewPeerService(
+		mongo.NewPeerRepository(),
+		service.NewNodeService(
+			mongo.NewNodeRepository(),
+			newSearchRepository(),
+			outboxStore,
 		),
 	)
+	svc.relay = outbox.NewRelay(
+		outboxStore,
+		messaging.PublishSync,
+	)
 	core.InstallShutdownHandler(svc.Shutdown)
 
 	return svc
 }
 
-// setupNATS initializes Nats service.
+// newSearchRepository returns the repository of the configured search
+// backend.
+func newSearchRepository() search.NodeRepository {
+	if config.Values.Search.Backend == constant.SearchBackend.Mongo {
+		return mongo.NewSearchRepository()
+	}
+	return es.NewNodeRepository()
+}
+
+// setupNATS initializes Nats service, unless the events go through another
+// bus.
 func (s *Service) setupNATS() {
+	if !messaging.UsesNATS() {
+		return
+	}
 	err := natsclient.Initialize(config.Values.Nats.URL)
 	if err != nil {
 		logger.Error("Failed to create Nats client", err)
@@ -131,9 +177,10 @@ func (s *Service) middlewares() []gin.HandlerFunc {
 			Method: "GET",
 		}),
 		midlogger.NewLogger(),
+		localizer.NewLocalizer(),
 		// CORS for all origins, allowing:
 		// - GET, POST and DELETE methods
-		// - Origin, Authorization and Content-Type header
+		// - Origin, Authorization, Content-Type and Accept-Language header
 		// - Credentials share
 		// - Preflight requests cached for 12 hours
 		cors.New(cors.Config{
@@ -143,6 +190,7 @@ func (s *Service) middlewares() []gin.HandlerFunc {
 				"Origin",
 				"Authorization",
 				"Content-Type",
+				"Accept-Language",
 			},
 			ExposeHeaders:    []string{"Content-Length"},
 			AllowCredentials: true,
@@ -156,12 +204,24 @@ func (s *Service) registerRoutes() {
 	nodeHandler := rest.NewNodeHandler(
 		service.NewNodeService(
 			mongo.NewNodeRepository(),
-			es.NewNodeRepository(),
+			newSearchRepository(),
+			outbox.NewStore(constant.MongoIndex.Outbox),
+		),
+	)
+
+	peerHandler := rest.NewPeerHandler(
+		service.NewPeerService(
+			mongo.NewPeerRepository(),
+			service.NewNodeService(
+				mongo.NewNodeRepository(),
+				newSearchRepository(),
+				outbox.NewStore(constant.MongoIndex.Outbox),
+			),
 		),
 	)
 
 	s.setupV1Routes()
-	s.setupV2Routes(nodeHandler)
+	s.setupV2Routes(nodeHandler, peerHandler)
 }
 
 // setupV1Routes configures routes for API version 1.
@@ -171,7 +231,10 @@ func (s *Service) setupV1Routes() {
 }
 
 // setupV2Routes configures routes for API version 2.
-func (s *Service) setupV2Routes(nodeHandler rest.NodeHandler) {
+func (s *Service) setupV2Routes(
+	nodeHandler rest.NodeHandler,
+	peerHandler rest.PeerHandler,
+) {
 	v2 := s.router.Group("/v2")
 	v2.GET("/ping", handler.PingHandler)
 	v2.PUT(
@@ -190,6 +253,11 @@ func (s *Service) setupV2Routes(nodeHandler rest.NodeHandler) {
 	v2.POST("/nodes-sync", nodeHandler.AddSync)
 	v2.POST("/export", nodeHandler.Export)
 	v2.GET("/get-nodes", nodeHandler.GetNodes)
+
+	// Peer-related routes
+	v2.GET("/peers", peerHandler.GetAll)
+	v2.POST("/peers", PeeringTokenMiddleware(), peerHandler.Add)
+	v2.DELETE("/peers/:peerID", PeeringTokenMiddleware(), peerHandler.Delete)
 }
 
 // panic performs a cleanup and then emits the supplied message as the panic value.
@@ -210,12 +278,31 @@ func (s *Service) Run() {
 		err != http.ErrServerClosed {
 		s.panic("Error when trying to listen events", err)
 	}
+	go s.relay.Run(s.shutdownCtx)
+	go s.replicatePeers(s.shutdownCtx)
 	if err := s.server.ListenAndServe(); err != nil &&
 		err != http.ErrServerClosed {
 		s.panic("Error when trying to start the server", err)
 	}
 }
 
+// replicatePeers replicates the upstream peers periodically until the context
+// is done.
+func (s *Service) replicatePeers(ctx context.Context) {
+	ticker := time.NewTicker(config.Values.Peering.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.peerService.Replicate(ctx); err != nil {
+				logger.Error("Failed to replicate the peers", err)
+			}
+		}
+	}
+}
+
 // WaitUntilUp returns a channel which blocks until the index service is up.
 func (s *Service) WaitUntilUp() <-chan struct{} {
 	initialized := make(chan struct{})
@@ -268,9 +355,12 @@ func (s *Service) cleanup() {
 		mongodb.Client.Disconnect()
 
 		// Disconnect from NATS.
-		if err := natsclient.GetInstance().Disconnect(); err != nil {
-			logger.Error("Error disconnecting from NATS: %v", err)
-			errOccurred = true
+		natsClient := natsclient.GetInstance()
+		if natsClient != nil {
+			if err := natsClient.Disconnect(); err != nil {
+				logger.Error("Error disconnecting from NATS: %v", err)
+				errOccurred = true
+			}
 		}
 
 		// Log based on whether an error occurred.
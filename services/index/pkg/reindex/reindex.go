@@ -0,0 +1,118 @@
+package reindex
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+
+	env "github.com/caarlos0/env/v10"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/elastic"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+	mongodb "github.com/MurmurationsNetwork/MurmurationsServices/pkg/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/config"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/es"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/service"
+)
+
+// Options controls a reindex run.
+type Options struct {
+	// DeleteOld deletes the previous index once the new one is live.
+	DeleteOld bool
+}
+
+// Reindexer rebuilds the node index when its mappings change.
+type Reindexer struct {
+	runCleanup sync.Once // Ensures cleanup is only run once.
+}
+
+// NewReindexer reads the index service configuration and connects to MongoDB
+// and Elasticsearch.
+func NewReindexer() *Reindexer {
+	if err := env.Parse(&config.Values); err != nil {
+		log.Fatalf("Failed to decode environment variables: %s", err)
+	}
+	if config.Values.Search.Backend != constant.SearchBackend.Elasticsearch {
+		logger.Info("Only the Elasticsearch search backend has to be reindexed")
+		os.Exit(0)
+	}
+
+	uri := mongodb.GetURI(
+		config.Values.Mongo.USERNAME,
+		config.Values.Mongo.PASSWORD,
+		config.Values.Mongo.HOST,
+	)
+	if err := mongodb.NewClient(uri, config.Values.Mongo.DBName); err != nil {
+		logger.Error("Failed to connect to MongoDB", err)
+		os.Exit(1)
+	}
+
+	if err := mongodb.Client.Ping(); err != nil {
+		logger.Error("Failed to ping MongoDB", err)
+		os.Exit(1)
+	}
+
+	if err := elastic.NewClient(config.Values.ES.URL); err != nil {
+		logger.Error("Failed to connect to Elasticsearch", err)
+		os.Exit(1)
+	}
+
+	return &Reindexer{}
+}
+
+// Run builds the current version of the node index and swaps it in.
+func (r *Reindexer) Run(ctx context.Context, opts Options) error {
+	defer r.cleanup()
+
+	svc := service.NewReindexService(
+		mongo.NewNodeRepository(),
+		es.NewIndexRepository(es.NodeIndex),
+	)
+
+	result, err := svc.Reindex(ctx, service.ReindexOptions{
+		DeleteOld: opts.DeleteOld,
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.Skipped {
+		logger.Info(
+			fmt.Sprintf("Index %s is already live, nothing to do", result.Index),
+		)
+		return nil
+	}
+	if len(result.Missing) > 0 {
+		logger.Info(
+			fmt.Sprintf(
+				"%d posted nodes had no document in the previous index: %s",
+				len(result.Missing),
+				strings.Join(result.Missing, ", "),
+			),
+		)
+	}
+	if result.Copied > 0 {
+		logger.Info(
+			fmt.Sprintf(
+				"%d documents were copied from the previous index, their nodes have no stored profile yet",
+				result.Copied,
+			),
+		)
+	}
+	logger.Info(
+		fmt.Sprintf("Indexed %d documents into %s", result.Indexed, result.Index),
+	)
+	return nil
+}
+
+// cleanup releases resources associated with the Reindexer.
+func (r *Reindexer) cleanup() {
+	r.runCleanup.Do(func() {
+		mongodb.Client.Disconnect()
+	})
+}
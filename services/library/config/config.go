@@ -9,9 +9,11 @@ var Values = Config{}
 
 // Config holds all configuration data for the application.
 type Config struct {
-	Server serverConfig
-	Mongo  mongoConfig
-	Static staticConfig
+	Server   serverConfig
+	Mongo    mongoConfig
+	Static   staticConfig
+	Index    indexConfig
+	Registry registryConfig
 }
 
 // serverConfig holds server-specific configuration.
@@ -47,3 +49,24 @@ type staticConfig struct {
 	// Path to the static files
 	StaticFilePath string `env:"STATIC_FILE_PATH,required"`
 }
+
+// indexConfig holds the configuration to reach the index.
+type indexConfig struct {
+	// URL of the index, used to sample the profiles when checking the
+	// compatibility of schemas. Sampling is disabled when it's empty.
+	URL string `env:"INDEX_HOST"`
+	// How long sampling the profiles may take, the profiles which aren't
+	// fetched by then are left out
+	SampleTimeout time.Duration `env:"SAMPLE_TIMEOUT" envDefault:"20s"`
+}
+
+// registryConfig holds the configuration of the schema registry, which lets
+// authors propose schemas through the API. The requests are refused when the
+// tokens are empty.
+type registryConfig struct {
+	// Bearer token of the authors proposing schemas
+	AuthorToken string `env:"REGISTRY_AUTHOR_TOKEN"`
+	// Bearer token of the reviewers approving or rejecting the schemas, which
+	// can also propose them
+	ReviewerToken string `env:"REGISTRY_REVIEWER_TOKEN"`
+}
@@ -0,0 +1,97 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/jsonapi"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/library"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/service"
+)
+
+// maxSample is the maximum number of profiles validated by a compatibility
+// check.
+const maxSample = 100
+
+// CompatibilityHandler defines the actions to compare the versions of
+// schemas.
+type CompatibilityHandler interface {
+	// Check reports whether a schema is compatible with an older one.
+	Check(c *gin.Context)
+}
+
+type compatibilityHandler struct {
+	svc service.CompatibilityService
+}
+
+// NewCompatibilityHandler returns a new compatibilityHandler with the
+// provided service.
+func NewCompatibilityHandler(
+	svc service.CompatibilityService,
+) CompatibilityHandler {
+	return &compatibilityHandler{
+		svc: svc,
+	}
+}
+
+// Check lists the changes between the `from` and `to` schemas.
+func (handler *compatibilityHandler) Check(c *gin.Context) {
+	var query model.CompatibilityQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		errors := jsonapi.NewError(
+			[]string{"Invalid Query Parameter"},
+			[]string{"The query parameters could not be parsed."},
+			nil,
+			[]int{http.StatusBadRequest},
+		)
+		res := jsonapi.Response(nil, errors, nil, nil)
+		c.JSON(errors[0].Status, res)
+		return
+	}
+	if query.From == "" || query.To == "" {
+		errors := jsonapi.NewError(
+			[]string{"Missing Query Parameter"},
+			[]string{"The `from` and `to` schemas must be provided."},
+			nil,
+			[]int{http.StatusBadRequest},
+		)
+		res := jsonapi.Response(nil, errors, nil, nil)
+		c.JSON(errors[0].Status, res)
+		return
+	}
+	if query.Sample < 0 || query.Sample > maxSample {
+		errors := jsonapi.NewError(
+			[]string{"Invalid Query Parameter"},
+			[]string{"The `sample` must be between 0 and 100."},
+			nil,
+			[]int{http.StatusBadRequest},
+		)
+		res := jsonapi.Response(nil, errors, nil, nil)
+		c.JSON(errors[0].Status, res)
+		return
+	}
+
+	report, err := handler.svc.Check(c.Request.Context(), &query)
+	if err != nil {
+		var invalidQueryError library.InvalidQueryError
+		if errors.As(err, &invalidQueryError) {
+			errors := jsonapi.NewError(
+				[]string{"Invalid Query Parameter"},
+				[]string{invalidQueryError.Reason},
+				nil,
+				[]int{http.StatusBadRequest},
+			)
+			res := jsonapi.Response(nil, errors, nil, nil)
+			c.JSON(errors[0].Status, res)
+			return
+		}
+		handleSchemaErrors(c, err)
+		return
+	}
+
+	res := jsonapi.Response(report.Marshall(), nil, nil, nil)
+	c.JSON(http.StatusOK, res)
+}
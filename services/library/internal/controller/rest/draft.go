@@ -0,0 +1,202 @@
+package rest
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/jsonapi"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/library"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/service"
+)
+
+// DraftHandler defines the actions to propose and review schemas.
+type DraftHandler interface {
+	// Propose saves the uploaded schema as a draft.
+	Propose(c *gin.Context)
+	Get(c *gin.Context)
+	GetAll(c *gin.Context)
+	// Approve publishes a draft to the library.
+	Approve(c *gin.Context)
+	Reject(c *gin.Context)
+}
+
+type draftHandler struct {
+	svc service.DraftService
+}
+
+// NewDraftHandler returns a new draftHandler with the provided service.
+func NewDraftHandler(svc service.DraftService) DraftHandler {
+	return &draftHandler{
+		svc: svc,
+	}
+}
+
+// Propose saves the schema in the body of the request as a draft.
+func (handler *draftHandler) Propose(c *gin.Context) {
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil || len(data) == 0 {
+		errors := jsonapi.NewError(
+			[]string{"Invalid Body"},
+			[]string{"The schema must be sent as the body of the request."},
+			nil,
+			[]int{http.StatusBadRequest},
+		)
+		res := jsonapi.Response(nil, errors, nil, nil)
+		c.JSON(errors[0].Status, res)
+		return
+	}
+
+	draft, err := handler.svc.Propose(data)
+	if err != nil {
+		handleDraftErrors(c, err)
+		return
+	}
+
+	res := jsonapi.Response(draft.Marshall(), nil, nil, nil)
+	c.JSON(http.StatusCreated, res)
+}
+
+// Get fetches a draft with its full schema.
+func (handler *draftHandler) Get(c *gin.Context) {
+	draft, err := handler.svc.Get(c.Param("schemaName"))
+	if err != nil {
+		handleDraftErrors(c, err)
+		return
+	}
+
+	res := jsonapi.Response(draft.Marshall(), nil, nil, nil)
+	c.JSON(http.StatusOK, res)
+}
+
+// GetAll lists the drafts, filtered by the `status` query parameter.
+func (handler *draftHandler) GetAll(c *gin.Context) {
+	status := c.Query("status")
+	if status != "" &&
+		status != model.DraftStatus.Draft &&
+		status != model.DraftStatus.Published &&
+		status != model.DraftStatus.Rejected {
+		errors := jsonapi.NewError(
+			[]string{"Invalid Query Parameter"},
+			[]string{
+				"The `status` must be `draft`, `published` or `rejected`.",
+			},
+			nil,
+			[]int{http.StatusBadRequest},
+		)
+		res := jsonapi.Response(nil, errors, nil, nil)
+		c.JSON(errors[0].Status, res)
+		return
+	}
+
+	drafts, err := handler.svc.GetAll(status)
+	if err != nil {
+		handleDraftErrors(c, err)
+		return
+	}
+
+	res := jsonapi.Response(drafts.Marshall(), nil, nil, nil)
+	c.JSON(http.StatusOK, res)
+}
+
+// Approve adds the schema of a draft to the library.
+func (handler *draftHandler) Approve(c *gin.Context) {
+	draft, err := handler.svc.Approve(c.Param("schemaName"))
+	if err != nil {
+		handleDraftErrors(c, err)
+		return
+	}
+
+	res := jsonapi.Response(draft.Marshall(), nil, nil, nil)
+	c.JSON(http.StatusOK, res)
+}
+
+// Reject refuses a draft, with the optional reason in the body.
+func (handler *draftHandler) Reject(c *gin.Context) {
+	var req model.ReviewRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errors := jsonapi.NewError(
+				[]string{"Invalid Body"},
+				[]string{"The body must be a JSON object with a `reason`."},
+				nil,
+				[]int{http.StatusBadRequest},
+			)
+			res := jsonapi.Response(nil, errors, nil, nil)
+			c.JSON(errors[0].Status, res)
+			return
+		}
+	}
+
+	draft, err := handler.svc.Reject(c.Param("schemaName"), req.Reason)
+	if err != nil {
+		handleDraftErrors(c, err)
+		return
+	}
+
+	res := jsonapi.Response(draft.Marshall(), nil, nil, nil)
+	c.JSON(http.StatusOK, res)
+}
+
+func handleDraftErrors(c *gin.Context, err error) {
+	var invalidSchemaError library.InvalidSchemaError
+	var conflictError library.ConflictError
+	var schemaNotFoundError library.SchemaNotFoundError
+	var dbError library.DatabaseError
+	var jsonErr []jsonapi.Error
+
+	switch {
+	case errors.As(err, &invalidSchemaError):
+		titles := make([]string, len(invalidSchemaError.Reasons))
+		statuses := make([]int, len(invalidSchemaError.Reasons))
+		for i := range invalidSchemaError.Reasons {
+			titles[i] = "Invalid Schema"
+			statuses[i] = http.StatusBadRequest
+		}
+		jsonErr = jsonapi.NewError(
+			titles,
+			invalidSchemaError.Reasons,
+			nil,
+			statuses,
+		)
+	case errors.As(err, &conflictError):
+		jsonErr = jsonapi.NewError(
+			[]string{"Conflict"},
+			[]string{conflictError.Error()},
+			nil,
+			[]int{http.StatusConflict},
+		)
+	case errors.As(err, &schemaNotFoundError):
+		jsonErr = jsonapi.NewError(
+			[]string{"Draft Not Found"},
+			[]string{
+				"Could not find the draft: " + schemaNotFoundError.SchemaName,
+			},
+			nil,
+			[]int{http.StatusNotFound},
+		)
+	case errors.As(err, &dbError):
+		logger.Error("Error when trying to access drafts", err)
+		jsonErr = jsonapi.NewError(
+			[]string{"Database Error"},
+			[]string{"Error when trying to access drafts."},
+			nil,
+			[]int{http.StatusInternalServerError},
+		)
+	default:
+		logger.Error("Error when trying to access drafts", err)
+		jsonErr = jsonapi.NewError(
+			[]string{"Unknown Error"},
+			[]string{"An unexpected error has occurred."},
+			nil,
+			[]int{http.StatusInternalServerError},
+		)
+	}
+
+	res := jsonapi.Response(nil, jsonErr, nil, nil)
+	c.JSON(jsonErr[0].Status, res)
+}
@@ -0,0 +1,239 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/controller/rest"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/library"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
+)
+
+type MockDraftService struct {
+	err    error
+	reason string
+}
+
+func (s *MockDraftService) draft(name, status string) (*model.Draft, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &model.Draft{
+		Schema: model.Schema{Name: name},
+		Status: status,
+		Reason: s.reason,
+	}, nil
+}
+
+func (s *MockDraftService) Propose(_ []byte) (*model.Draft, error) {
+	return s.draft("test_schema-v1.0.0", model.DraftStatus.Draft)
+}
+
+func (s *MockDraftService) Get(schemaName string) (*model.Draft, error) {
+	return s.draft(schemaName, model.DraftStatus.Draft)
+}
+
+func (s *MockDraftService) GetAll(_ string) (model.Drafts, error) {
+	draft, err := s.draft("test_schema-v1.0.0", model.DraftStatus.Draft)
+	if err != nil {
+		return nil, err
+	}
+	return model.Drafts{draft}, nil
+}
+
+func (s *MockDraftService) Approve(schemaName string) (*model.Draft, error) {
+	return s.draft(schemaName, model.DraftStatus.Published)
+}
+
+func (s *MockDraftService) Reject(
+	schemaName string,
+	reason string,
+) (*model.Draft, error) {
+	s.reason = reason
+	return s.draft(schemaName, model.DraftStatus.Rejected)
+}
+
+func TestDraftHandler_Propose(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		mockSvc        *MockDraftService
+		expectedStatus int
+		expectedErrors int
+	}{
+		{
+			name:           "success",
+			body:           `{"title": "Test"}`,
+			mockSvc:        &MockDraftService{},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "empty body",
+			mockSvc:        &MockDraftService{},
+			expectedStatus: http.StatusBadRequest,
+			expectedErrors: 1,
+		},
+		{
+			name: "invalid schema",
+			body: `{}`,
+			mockSvc: &MockDraftService{err: library.InvalidSchemaError{
+				Reasons: []string{"First reason.", "Second reason."},
+			}},
+			expectedStatus: http.StatusBadRequest,
+			expectedErrors: 2,
+		},
+		{
+			name: "published schema",
+			body: `{}`,
+			mockSvc: &MockDraftService{
+				err: library.ConflictError{Reason: "Already published."},
+			},
+			expectedStatus: http.StatusConflict,
+			expectedErrors: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := rest.NewDraftHandler(tt.mockSvc)
+
+			r := gin.Default()
+			r.POST("/drafts", handler.Propose)
+
+			req, _ := http.NewRequest(
+				http.MethodPost,
+				"/drafts",
+				strings.NewReader(tt.body),
+			)
+			resp := httptest.NewRecorder()
+
+			r.ServeHTTP(resp, req)
+
+			require.Equal(t, tt.expectedStatus, resp.Code)
+			var body struct {
+				Errors []struct {
+					Detail string `json:"detail"`
+				} `json:"errors"`
+			}
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+			require.Len(t, body.Errors, tt.expectedErrors)
+		})
+	}
+}
+
+func TestDraftHandler_GetAll(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+	}{
+		{name: "all", expectedStatus: http.StatusOK},
+		{
+			name:           "by status",
+			query:          "?status=draft",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid status",
+			query:          "?status=unknown",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := rest.NewDraftHandler(&MockDraftService{})
+
+			r := gin.Default()
+			r.GET("/drafts", handler.GetAll)
+
+			req, _ := http.NewRequest(http.MethodGet, "/drafts"+tt.query, nil)
+			resp := httptest.NewRecorder()
+
+			r.ServeHTTP(resp, req)
+
+			require.Equal(t, tt.expectedStatus, resp.Code)
+		})
+	}
+}
+
+func TestDraftHandler_Reject(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		mockSvc        *MockDraftService
+		expectedStatus int
+		expectedReason string
+	}{
+		{
+			name:           "with reason",
+			body:           `{"reason": "Use the tags field."}`,
+			mockSvc:        &MockDraftService{},
+			expectedStatus: http.StatusOK,
+			expectedReason: "Use the tags field.",
+		},
+		{
+			name:           "without reason",
+			mockSvc:        &MockDraftService{},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid body",
+			body:           `reason`,
+			mockSvc:        &MockDraftService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "draft not found",
+			mockSvc: &MockDraftService{
+				err: library.SchemaNotFoundError{SchemaName: "test"},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := rest.NewDraftHandler(tt.mockSvc)
+
+			r := gin.Default()
+			r.POST("/drafts/:schemaName/reject", handler.Reject)
+
+			req, _ := http.NewRequest(
+				http.MethodPost,
+				"/drafts/test_schema-v1.0.0/reject",
+				strings.NewReader(tt.body),
+			)
+			resp := httptest.NewRecorder()
+
+			r.ServeHTTP(resp, req)
+
+			require.Equal(t, tt.expectedStatus, resp.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var body struct {
+				Data struct {
+					Status string `json:"status"`
+					Reason string `json:"reason"`
+				} `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+			require.Equal(t, model.DraftStatus.Rejected, body.Data.Status)
+			require.Equal(t, tt.expectedReason, body.Data.Reason)
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/jsonapi"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/library"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/service"
+)
+
+// FieldHandler defines the actions that can be performed with a Field.
+type FieldHandler interface {
+	// Get fetches a field with its definition and the schemas using it.
+	Get(c *gin.Context)
+	// Search lists the fields.
+	Search(c *gin.Context)
+}
+
+type fieldHandler struct {
+	svc service.FieldService
+}
+
+// NewFieldHandler returns a new fieldHandler with the provided service.
+func NewFieldHandler(svc service.FieldService) FieldHandler {
+	return &fieldHandler{
+		svc: svc,
+	}
+}
+
+// Get fetches a field with a specific name.
+func (handler *fieldHandler) Get(c *gin.Context) {
+	field, err := handler.svc.Get(c.Param("fieldName"))
+	if err != nil {
+		handleFieldErrors(c, err)
+		return
+	}
+
+	res := jsonapi.Response(field.Marshall(), nil, nil, nil)
+	c.JSON(http.StatusOK, res)
+}
+
+// Search fetches a page of the fields that match the search criteria.
+func (handler *fieldHandler) Search(c *gin.Context) {
+	var query model.FieldQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		errors := jsonapi.NewError(
+			[]string{"Invalid Query Parameter"},
+			[]string{"The query parameters could not be parsed."},
+			nil,
+			[]int{http.StatusBadRequest},
+		)
+		res := jsonapi.Response(nil, errors, nil, nil)
+		c.JSON(errors[0].Status, res)
+		return
+	}
+	if query.Page < 1 || query.PageSize < 1 {
+		errors := jsonapi.NewError(
+			[]string{"Invalid Query Parameter"},
+			[]string{"The `page` and `page_size` must be positive."},
+			nil,
+			[]int{http.StatusBadRequest},
+		)
+		res := jsonapi.Response(nil, errors, nil, nil)
+		c.JSON(errors[0].Status, res)
+		return
+	}
+
+	searchRes, err := handler.svc.Search(&query)
+	if err != nil {
+		handleFieldErrors(c, err)
+		return
+	}
+
+	data := searchRes.Fields.Marshall()
+	// No links when the page is past the results.
+	if searchRes.TotalPages == 0 || query.Page > searchRes.TotalPages {
+		res := jsonapi.Response(data, nil, nil, nil)
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	meta := jsonapi.NewSearchMeta(
+		"",
+		searchRes.NumberOfResults,
+		searchRes.TotalPages,
+	)
+	links := jsonapi.NewLinks(c, query.Page, searchRes.TotalPages)
+	res := jsonapi.Response(data, nil, links, meta)
+	c.JSON(http.StatusOK, res)
+}
+
+func handleFieldErrors(c *gin.Context, err error) {
+	var fieldNotFoundError library.FieldNotFoundError
+	var dbError library.DatabaseError
+	var jsonErr []jsonapi.Error
+
+	switch {
+	case errors.As(err, &fieldNotFoundError):
+		jsonErr = jsonapi.NewError(
+			[]string{"Field Not Found"},
+			[]string{fieldNotFoundError.Error()},
+			nil,
+			[]int{http.StatusNotFound},
+		)
+	case errors.As(err, &dbError):
+		logger.Error("Error when trying to find fields", err)
+		jsonErr = jsonapi.NewError(
+			[]string{"Database Error"},
+			[]string{"Error when trying to find fields."},
+			nil,
+			[]int{http.StatusInternalServerError},
+		)
+	default:
+		logger.Error("Error when trying to find fields", err)
+		jsonErr = jsonapi.NewError(
+			[]string{"Unknown Error"},
+			[]string{"An unexpected error has occurred."},
+			nil,
+			[]int{http.StatusInternalServerError},
+		)
+	}
+
+	res := jsonapi.Response(nil, jsonErr, nil, nil)
+	c.JSON(jsonErr[0].Status, res)
+}
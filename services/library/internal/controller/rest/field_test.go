@@ -0,0 +1,158 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/controller/rest"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/library"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
+)
+
+type MockFieldService struct {
+	err error
+}
+
+func (s *MockFieldService) Get(fieldName string) (*model.SingleField, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &model.SingleField{
+		Field: model.Field{
+			Name:   fieldName,
+			UsedBy: []string{"people_schema-v0.1.0"},
+		},
+		FullField: bson.D{{Key: "type", Value: "string"}},
+	}, nil
+}
+
+func (s *MockFieldService) Search(
+	_ *model.FieldQuery,
+) (*model.FieldSearchResult, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &model.FieldSearchResult{
+		Fields: model.Fields{
+			{Name: "name", UsedBy: []string{"people_schema-v0.1.0"}},
+			{Name: "tags", UsedBy: []string{}},
+		},
+		NumberOfResults: 2,
+		TotalPages:      1,
+	}, nil
+}
+
+func TestFieldHandler_Get(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		mockSvc        *MockFieldService
+		expectedStatus int
+	}{
+		{
+			name: "field not found",
+			mockSvc: &MockFieldService{
+				err: library.FieldNotFoundError{FieldName: "name"},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "database error",
+			mockSvc:        &MockFieldService{err: library.DatabaseError{}},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "unknown error",
+			mockSvc:        &MockFieldService{err: errors.New("unknown")},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "success",
+			mockSvc:        &MockFieldService{},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := rest.NewFieldHandler(tt.mockSvc)
+
+			r := gin.Default()
+			r.GET("/fields/:fieldName", handler.Get)
+
+			req, _ := http.NewRequest(http.MethodGet, "/fields/name", nil)
+			resp := httptest.NewRecorder()
+
+			r.ServeHTTP(resp, req)
+
+			require.Equal(t, tt.expectedStatus, resp.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var body struct {
+				Data struct {
+					Name       string                 `json:"name"`
+					UsedBy     []string               `json:"used_by"`
+					Definition map[string]interface{} `json:"definition"`
+				} `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+			require.Equal(t, "name", body.Data.Name)
+			require.Equal(t, []string{"people_schema-v0.1.0"}, body.Data.UsedBy)
+			require.Equal(t, "string", body.Data.Definition["type"])
+		})
+	}
+}
+
+func TestFieldHandler_Search(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		query          string
+		mockSvc        *MockFieldService
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			mockSvc:        &MockFieldService{},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid page",
+			query:          "?page=0",
+			mockSvc:        &MockFieldService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "database error",
+			mockSvc:        &MockFieldService{err: library.DatabaseError{}},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := rest.NewFieldHandler(tt.mockSvc)
+
+			r := gin.Default()
+			r.GET("/fields", handler.Search)
+
+			req, _ := http.NewRequest(http.MethodGet, "/fields"+tt.query, nil)
+			resp := httptest.NewRecorder()
+
+			r.ServeHTTP(resp, req)
+
+			require.Equal(t, tt.expectedStatus, resp.Code)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/jsonapi"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/service"
+)
+
+// RunHandler defines the actions that can be performed with the runs of the
+// Schema Parser.
+type RunHandler interface {
+	// Search lists the runs, from the newest to the oldest.
+	Search(c *gin.Context)
+	// GetStatus fetches the active generation of the library with the
+	// latest runs.
+	GetStatus(c *gin.Context)
+}
+
+type runHandler struct {
+	svc service.RunService
+}
+
+// NewRunHandler returns a new runHandler with the provided service.
+func NewRunHandler(svc service.RunService) RunHandler {
+	return &runHandler{
+		svc: svc,
+	}
+}
+
+// Search fetches a page of the runs that match the search criteria.
+func (handler *runHandler) Search(c *gin.Context) {
+	var query model.RunQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		errors := jsonapi.NewError(
+			[]string{"Invalid Query Parameter"},
+			[]string{"The query parameters could not be parsed."},
+			nil,
+			[]int{http.StatusBadRequest},
+		)
+		res := jsonapi.Response(nil, errors, nil, nil)
+		c.JSON(errors[0].Status, res)
+		return
+	}
+	if query.Page < 1 || query.PageSize < 1 {
+		errors := jsonapi.NewError(
+			[]string{"Invalid Query Parameter"},
+			[]string{"The `page` and `page_size` must be positive."},
+			nil,
+			[]int{http.StatusBadRequest},
+		)
+		res := jsonapi.Response(nil, errors, nil, nil)
+		c.JSON(errors[0].Status, res)
+		return
+	}
+
+	searchRes, err := handler.svc.Search(&query)
+	if err != nil {
+		handleRunErrors(c, err)
+		return
+	}
+
+	data := searchRes.Runs.Marshall()
+	// No links when the page is past the results.
+	if searchRes.TotalPages == 0 || query.Page > searchRes.TotalPages {
+		res := jsonapi.Response(data, nil, nil, nil)
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	meta := jsonapi.NewSearchMeta(
+		"",
+		searchRes.NumberOfResults,
+		searchRes.TotalPages,
+	)
+	links := jsonapi.NewLinks(c, query.Page, searchRes.TotalPages)
+	res := jsonapi.Response(data, nil, links, meta)
+	c.JSON(http.StatusOK, res)
+}
+
+// GetStatus fetches the active generation of the library with the latest
+// run and the latest successful run.
+func (handler *runHandler) GetStatus(c *gin.Context) {
+	status, err := handler.svc.GetStatus()
+	if err != nil {
+		handleRunErrors(c, err)
+		return
+	}
+
+	res := jsonapi.Response(status, nil, nil, nil)
+	c.JSON(http.StatusOK, res)
+}
+
+func handleRunErrors(c *gin.Context, err error) {
+	logger.Error("Error when trying to find the schemaparser runs", err)
+	errors := jsonapi.NewError(
+		[]string{"Database Error"},
+		[]string{"Error when trying to find the schemaparser runs."},
+		nil,
+		[]int{http.StatusInternalServerError},
+	)
+	res := jsonapi.Response(nil, errors, nil, nil)
+	c.JSON(errors[0].Status, res)
+}
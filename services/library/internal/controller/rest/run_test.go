@@ -0,0 +1,162 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/librarygen"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/controller/rest"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/library"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
+)
+
+type MockRunService struct {
+	err   error
+	query *model.RunQuery
+}
+
+func (s *MockRunService) Search(
+	q *model.RunQuery,
+) (*model.RunSearchResult, error) {
+	s.query = q
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &model.RunSearchResult{
+		Runs: model.Runs{{
+			Action:         "update",
+			Status:         model.RunStatus.Succeeded,
+			Source:         "github",
+			Commit:         "3f786850e387550fdab836ed7e6dc881de23001b",
+			Generation:     2,
+			StartedAt:      1714557600,
+			EndedAt:        1714557605,
+			ChangedSchemas: []string{"test_schema-v1.0.0"},
+			ChangedFields:  []string{},
+		}},
+		NumberOfResults: 1,
+		TotalPages:      1,
+	}, nil
+}
+
+func (s *MockRunService) GetStatus() (*model.UpdateStatus, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &model.UpdateStatus{
+		Active: librarygen.Generation{Number: 2, Source: "github"},
+		LastRun: &model.Run{
+			Action:         "update",
+			Status:         model.RunStatus.Failed,
+			Generation:     2,
+			ChangedSchemas: []string{},
+			ChangedFields:  []string{},
+			Errors:         []string{"failed to load the schemas"},
+		},
+	}, nil
+}
+
+func TestRunHandler_Search(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		query          string
+		mockSvc        *MockRunService
+		expectedStatus int
+		expectedData   string
+	}{
+		{
+			name:           "success",
+			query:          "?schema=test_schema-v1.0.0",
+			mockSvc:        &MockRunService{},
+			expectedStatus: http.StatusOK,
+			expectedData: `[{
+				"action": "update",
+				"status": "succeeded",
+				"source": "github",
+				"commit": "3f786850e387550fdab836ed7e6dc881de23001b",
+				"generation": 2,
+				"started_at": 1714557600,
+				"ended_at": 1714557605,
+				"changed_schemas": ["test_schema-v1.0.0"],
+				"changed_fields": []
+			}]`,
+		},
+		{
+			name:           "invalid page size",
+			query:          "?page_size=0",
+			mockSvc:        &MockRunService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "database error",
+			mockSvc:        &MockRunService{err: library.DatabaseError{}},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := rest.NewRunHandler(tt.mockSvc)
+
+			r := gin.Default()
+			r.GET("/schemas/_history", handler.Search)
+			r.GET("/schemas/:schemaName", func(c *gin.Context) {
+				c.Status(http.StatusTeapot)
+			})
+
+			req, _ := http.NewRequest(
+				http.MethodGet,
+				"/schemas/_history"+tt.query,
+				nil,
+			)
+			resp := httptest.NewRecorder()
+			r.ServeHTTP(resp, req)
+
+			require.Equal(t, tt.expectedStatus, resp.Code)
+			if tt.expectedData == "" {
+				return
+			}
+			require.Equal(t, "test_schema-v1.0.0", *tt.mockSvc.query.Schema)
+			var body struct {
+				Data json.RawMessage `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+			require.JSONEq(t, tt.expectedData, string(body.Data))
+		})
+	}
+}
+
+func TestRunHandler_GetStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := rest.NewRunHandler(&MockRunService{})
+	r := gin.Default()
+	r.GET("/schemas/_status", handler.GetStatus)
+
+	req, _ := http.NewRequest(http.MethodGet, "/schemas/_status", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.JSONEq(t, `{"data": {
+		"active": {"number": 2, "source": "github"},
+		"last_run": {
+			"action": "update",
+			"status": "failed",
+			"generation": 2,
+			"started_at": 0,
+			"ended_at": 0,
+			"changed_schemas": [],
+			"changed_fields": [],
+			"errors": ["failed to load the schemas"]
+		},
+		"last_successful_run": null
+	}}`, resp.Body.String())
+}
@@ -9,13 +9,21 @@ import (
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/jsonapi"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/library"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/service"
 )
 
 // SchemaHandler defines the actions that can be performed with a Schema.
 type SchemaHandler interface {
 	Get(c *gin.Context)
+	// GetMeta fetches the active generation of the library, with the
+	// commit it was loaded from.
+	GetMeta(c *gin.Context)
 	Search(c *gin.Context)
+	// GetVersions lists the versions of a family of schemas.
+	GetVersions(c *gin.Context)
+	// GetLatest fetches the newest version of a family of schemas.
+	GetLatest(c *gin.Context)
 }
 
 type schemaHandler struct {
@@ -48,47 +56,52 @@ func (handler *schemaHandler) Get(c *gin.Context) {
 
 	schema, err := handler.svc.Get(schemaName)
 	if err != nil {
-		var schemaNotFoundError library.SchemaNotFoundError
-		var dbError library.DatabaseError
-
-		switch {
-		case errors.As(err, &schemaNotFoundError):
-			errors := jsonapi.NewError(
-				[]string{"Schema Not Found"},
-				[]string{schemaNotFoundError.Error()},
-				nil,
-				[]int{http.StatusNotFound},
-			)
-			res := jsonapi.Response(nil, errors, nil, nil)
-			c.JSON(http.StatusNotFound, res)
-		case errors.As(err, &dbError):
-			errors := jsonapi.NewError(
-				[]string{"Database Error"},
-				[]string{dbError.Error()},
-				nil,
-				[]int{http.StatusInternalServerError},
-			)
-			res := jsonapi.Response(nil, errors, nil, nil)
-			c.JSON(http.StatusInternalServerError, res)
-		default:
-			errors := jsonapi.NewError(
-				[]string{"Unknown Error"},
-				[]string{"An unexpected error has occurred."},
-				nil,
-				[]int{http.StatusInternalServerError},
-			)
-			res := jsonapi.Response(nil, errors, nil, nil)
-			c.JSON(http.StatusInternalServerError, res)
-		}
+		handleSchemaErrors(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, schema)
 }
 
-// Search fetches all schemas that match the search criteria.
+// GetMeta fetches the active and the previous generations of the library.
+func (handler *schemaHandler) GetMeta(c *gin.Context) {
+	meta, err := handler.svc.GetMeta()
+	if err != nil {
+		handleSchemaErrors(c, err)
+		return
+	}
+
+	res := jsonapi.Response(meta, nil, nil, nil)
+	c.JSON(http.StatusOK, res)
+}
+
+// Search fetches a page of the schemas that match the search criteria.
 func (handler *schemaHandler) Search(c *gin.Context) {
-	searchRes, err := handler.svc.Search()
+	var query model.SchemaQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		errors := jsonapi.NewError(
+			[]string{"Invalid Query Parameter"},
+			[]string{"The query parameters could not be parsed."},
+			nil,
+			[]int{http.StatusBadRequest},
+		)
+		res := jsonapi.Response(nil, errors, nil, nil)
+		c.JSON(errors[0].Status, res)
+		return
+	}
+	if query.Page < 1 || query.PageSize < 1 {
+		errors := jsonapi.NewError(
+			[]string{"Invalid Query Parameter"},
+			[]string{"The `page` and `page_size` must be positive."},
+			nil,
+			[]int{http.StatusBadRequest},
+		)
+		res := jsonapi.Response(nil, errors, nil, nil)
+		c.JSON(errors[0].Status, res)
+		return
+	}
+
+	searchRes, err := handler.svc.Search(&query)
 	if err != nil {
 		logger.Error("Error when trying to find schemas", err)
 		errors := jsonapi.NewError(
@@ -102,6 +115,80 @@ func (handler *schemaHandler) Search(c *gin.Context) {
 		return
 	}
 
-	res := jsonapi.Response(searchRes.Marshall(), nil, nil, nil)
+	data := searchRes.Schemas.Marshall()
+	// No links when the page is past the results.
+	if searchRes.TotalPages == 0 || query.Page > searchRes.TotalPages {
+		res := jsonapi.Response(data, nil, nil, nil)
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	meta := jsonapi.NewSearchMeta(
+		"",
+		searchRes.NumberOfResults,
+		searchRes.TotalPages,
+	)
+	links := jsonapi.NewLinks(c, query.Page, searchRes.TotalPages)
+	res := jsonapi.Response(data, nil, links, meta)
+	c.JSON(http.StatusOK, res)
+}
+
+// GetVersions lists the versions of a family of schemas, from the oldest to
+// the newest.
+func (handler *schemaHandler) GetVersions(c *gin.Context) {
+	versions, err := handler.svc.GetVersions(c.Param("schemaName"))
+	if err != nil {
+		handleSchemaErrors(c, err)
+		return
+	}
+
+	res := jsonapi.Response(versions.Marshall(), nil, nil, nil)
 	c.JSON(http.StatusOK, res)
 }
+
+// GetLatest fetches the newest version of a family of schemas.
+func (handler *schemaHandler) GetLatest(c *gin.Context) {
+	latest, err := handler.svc.GetLatest(c.Param("schemaName"))
+	if err != nil {
+		handleSchemaErrors(c, err)
+		return
+	}
+
+	res := jsonapi.Response(latest.Marshall(), nil, nil, nil)
+	c.JSON(http.StatusOK, res)
+}
+
+func handleSchemaErrors(c *gin.Context, err error) {
+	var schemaNotFoundError library.SchemaNotFoundError
+	var dbError library.DatabaseError
+
+	switch {
+	case errors.As(err, &schemaNotFoundError):
+		errors := jsonapi.NewError(
+			[]string{"Schema Not Found"},
+			[]string{schemaNotFoundError.Error()},
+			nil,
+			[]int{http.StatusNotFound},
+		)
+		res := jsonapi.Response(nil, errors, nil, nil)
+		c.JSON(http.StatusNotFound, res)
+	case errors.As(err, &dbError):
+		errors := jsonapi.NewError(
+			[]string{"Database Error"},
+			[]string{dbError.Error()},
+			nil,
+			[]int{http.StatusInternalServerError},
+		)
+		res := jsonapi.Response(nil, errors, nil, nil)
+		c.JSON(http.StatusInternalServerError, res)
+	default:
+		errors := jsonapi.NewError(
+			[]string{"Unknown Error"},
+			[]string{"An unexpected error has occurred."},
+			nil,
+			[]int{http.StatusInternalServerError},
+		)
+		res := jsonapi.Response(nil, errors, nil, nil)
+		c.JSON(http.StatusInternalServerError, res)
+	}
+}
@@ -1,6 +1,7 @@
 package rest_test
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -9,6 +10,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/require"
 
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/librarygen"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/controller/rest"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/library"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
@@ -16,7 +18,9 @@ import (
 
 type MockSchemaService struct {
 	schema *model.Schema
+	meta   *librarygen.Meta
 	err    error
+	query  *model.SchemaQuery
 }
 
 func (s *MockSchemaService) Get(_ string) (interface{}, error) {
@@ -26,11 +30,21 @@ func (s *MockSchemaService) Get(_ string) (interface{}, error) {
 	return s.schema, nil
 }
 
-func (s *MockSchemaService) Search() (*model.Schemas, error) {
+func (s *MockSchemaService) GetMeta() (*librarygen.Meta, error) {
 	if s.err != nil {
 		return nil, s.err
 	}
-	return &model.Schemas{
+	return s.meta, nil
+}
+
+func (s *MockSchemaService) Search(
+	q *model.SchemaQuery,
+) (*model.SchemaSearchResult, error) {
+	s.query = q
+	if s.err != nil {
+		return nil, s.err
+	}
+	schemas := model.Schemas{
 		&model.Schema{
 			Title:       "TestSchema1",
 			Description: "This is a test schema 1",
@@ -43,9 +57,34 @@ func (s *MockSchemaService) Search() (*model.Schemas, error) {
 			Name:        "TestSchema2",
 			URL:         "https://test.com/TestSchema2",
 		},
+	}
+	return &model.SchemaSearchResult{
+		Schemas:         schemas,
+		NumberOfResults: 3,
+		TotalPages:      2,
 	}, nil
 }
 
+func (s *MockSchemaService) GetVersions(
+	family string,
+) (model.Schemas, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return model.Schemas{
+		{Name: family + "-v1.0.0", Family: family, Version: "1.0.0"},
+		{Name: family + "-v1.1.0", Family: family, Version: "1.1.0"},
+	}, nil
+}
+
+func (s *MockSchemaService) GetLatest(family string) (*model.Schema, error) {
+	versions, err := s.GetVersions(family)
+	if err != nil {
+		return nil, err
+	}
+	return versions[len(versions)-1], nil
+}
+
 func TestSchemaHandler_Get(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -115,11 +154,46 @@ func TestSchemaHandler_Get(t *testing.T) {
 	}
 }
 
+func TestSchemaHandler_GetMeta(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := rest.NewSchemaHandler(&MockSchemaService{
+		meta: &librarygen.Meta{
+			ID: librarygen.MetaID,
+			Active: librarygen.Generation{
+				Number: 2,
+				Source: "git",
+				Commit: "3f786850e387550fdab836ed7e6dc881de23001b",
+			},
+			Previous: &librarygen.Generation{Number: 1, Source: "git"},
+		},
+	})
+
+	r := gin.Default()
+	r.GET("/schemas/_meta", handler.GetMeta)
+	r.GET("/schemas/:schemaName", handler.Get)
+
+	req, _ := http.NewRequest(http.MethodGet, "/schemas/_meta", nil)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.JSONEq(t, `{"data": {
+		"active": {
+			"number": 2,
+			"source": "git",
+			"commit": "3f786850e387550fdab836ed7e6dc881de23001b"
+		},
+		"previous": {"number": 1, "source": "git"}
+	}}`, resp.Body.String())
+}
+
 func TestSchemaHandler_Search(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	tests := []struct {
 		name           string
+		query          string
 		mockSvc        *MockSchemaService
 		expectedStatus int
 	}{
@@ -128,6 +202,18 @@ func TestSchemaHandler_Search(t *testing.T) {
 			mockSvc:        &MockSchemaService{},
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name:           "invalid page size",
+			query:          "?page_size=0",
+			mockSvc:        &MockSchemaService{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid page",
+			query:          "?page=first",
+			mockSvc:        &MockSchemaService{},
+			expectedStatus: http.StatusBadRequest,
+		},
 		{
 			name: "database error",
 			mockSvc: &MockSchemaService{
@@ -151,12 +237,126 @@ func TestSchemaHandler_Search(t *testing.T) {
 			r := gin.Default()
 			r.GET("/schemas", handler.Search)
 
-			req, _ := http.NewRequest(http.MethodGet, "/schemas", nil)
+			req, _ := http.NewRequest(
+				http.MethodGet,
+				"/schemas"+tt.query,
+				nil,
+			)
+			resp := httptest.NewRecorder()
+
+			r.ServeHTTP(resp, req)
+
+			require.Equal(t, tt.expectedStatus, resp.Code)
+		})
+	}
+}
+
+func TestSchemaHandler_SearchQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	svc := &MockSchemaService{}
+	handler := rest.NewSchemaHandler(svc)
+
+	r := gin.Default()
+	r.GET("/v2/schemas", handler.Search)
+
+	req, _ := http.NewRequest(
+		http.MethodGet,
+		"/v2/schemas?q=map&name=people&version=0.1.0"+
+			"&include_deprecated=true&page_size=2",
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, "map", *svc.query.Q)
+	require.Equal(t, "people", *svc.query.Name)
+	require.Equal(t, "0.1.0", *svc.query.Version)
+	require.True(t, svc.query.IncludeDeprecated)
+	require.EqualValues(t, 1, svc.query.Page)
+	require.EqualValues(t, 2, svc.query.PageSize)
+
+	var body struct {
+		Data  []model.Schema `json:"data"`
+		Links struct {
+			Next string `json:"next"`
+		} `json:"links"`
+		Meta struct {
+			NumberOfResults int64 `json:"number_of_results"`
+			TotalPages      int64 `json:"total_pages"`
+		} `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	require.Len(t, body.Data, 2)
+	require.Contains(t, body.Links.Next, "page=2")
+	require.EqualValues(t, 3, body.Meta.NumberOfResults)
+	require.EqualValues(t, 2, body.Meta.TotalPages)
+}
+
+func TestSchemaHandler_Versions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		path           string
+		mockSvc        *MockSchemaService
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "versions",
+			path:           "/schemas/test_schema/versions",
+			mockSvc:        &MockSchemaService{},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"data": [
+				{"title": "", "description": "", "url": "",
+					"name": "test_schema-v1.0.0",
+					"family": "test_schema", "version": "1.0.0"},
+				{"title": "", "description": "", "url": "",
+					"name": "test_schema-v1.1.0",
+					"family": "test_schema", "version": "1.1.0"}
+			]}`,
+		},
+		{
+			name:           "latest",
+			path:           "/schemas/test_schema/latest",
+			mockSvc:        &MockSchemaService{},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"data":
+				{"title": "", "description": "", "url": "",
+					"name": "test_schema-v1.1.0",
+					"family": "test_schema", "version": "1.1.0"}
+			}`,
+		},
+		{
+			name: "family not found",
+			path: "/schemas/missing/latest",
+			mockSvc: &MockSchemaService{
+				err: library.SchemaNotFoundError{SchemaName: "missing"},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := rest.NewSchemaHandler(tt.mockSvc)
+
+			r := gin.Default()
+			r.GET("/schemas/:schemaName", handler.Get)
+			r.GET("/schemas/:schemaName/versions", handler.GetVersions)
+			r.GET("/schemas/:schemaName/latest", handler.GetLatest)
+
+			req, _ := http.NewRequest(http.MethodGet, tt.path, nil)
 			resp := httptest.NewRecorder()
 
 			r.ServeHTTP(resp, req)
 
 			require.Equal(t, tt.expectedStatus, resp.Code)
+			if tt.expectedBody != "" {
+				require.JSONEq(t, tt.expectedBody, resp.Body.String())
+			}
 		})
 	}
 }
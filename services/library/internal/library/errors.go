@@ -2,6 +2,7 @@ package library
 
 import (
 	"fmt"
+	"strings"
 )
 
 // SchemaNotFoundError represents an error that occurs when a specified schema
@@ -24,6 +25,59 @@ func (e SchemaNotFoundError) Unwrap() error {
 	return e.Err
 }
 
+// FieldNotFoundError represents an error that occurs when a specified field is
+// not found in the library.
+type FieldNotFoundError struct {
+	FieldName string
+	Err       error
+}
+
+// Error conforms to go conventions.
+func (e FieldNotFoundError) Error() string {
+	return fmt.Sprintf(
+		"could not locate the following field in the Library: %s",
+		e.FieldName,
+	)
+}
+
+// Unwrap conforms to go conventions.
+func (e FieldNotFoundError) Unwrap() error {
+	return e.Err
+}
+
+// InvalidQueryError represents an error that occurs when a request can't be
+// fulfilled with the given parameters.
+type InvalidQueryError struct {
+	Reason string
+}
+
+// Error conforms to go conventions.
+func (e InvalidQueryError) Error() string {
+	return e.Reason
+}
+
+// InvalidSchemaError represents an error that occurs when a proposed schema
+// can't be added to the library.
+type InvalidSchemaError struct {
+	Reasons []string
+}
+
+// Error conforms to go conventions.
+func (e InvalidSchemaError) Error() string {
+	return "invalid schema: " + strings.Join(e.Reasons, " ")
+}
+
+// ConflictError represents an error that occurs when a request conflicts with
+// the state of the library, such as publishing a schema twice.
+type ConflictError struct {
+	Reason string
+}
+
+// Error conforms to go conventions.
+func (e ConflictError) Error() string {
+	return e.Reason
+}
+
 // DatabaseError represents an error that occurs during a database operation.
 type DatabaseError struct {
 	Err error
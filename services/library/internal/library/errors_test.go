@@ -21,6 +21,32 @@ func TestSchemaNotFoundError(t *testing.T) {
 	)
 }
 
+func TestFieldNotFoundError(t *testing.T) {
+	err := &library.FieldNotFoundError{FieldName: "test-field"}
+
+	expected := "could not locate the following field in the Library: test-field"
+	require.Equal(
+		t,
+		expected,
+		err.Error(),
+		"FieldNotFoundError Error() message was incorrect",
+	)
+}
+
+func TestInvalidSchemaError(t *testing.T) {
+	err := &library.InvalidSchemaError{
+		Reasons: []string{"The title is missing.", "The name is missing."},
+	}
+
+	expected := "invalid schema: The title is missing. The name is missing."
+	require.Equal(
+		t,
+		expected,
+		err.Error(),
+		"InvalidSchemaError Error() message was incorrect",
+	)
+}
+
 func TestDatabaseError(t *testing.T) {
 	err := &library.DatabaseError{Err: errors.New("db error")}
 
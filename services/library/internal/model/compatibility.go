@@ -0,0 +1,54 @@
+package model
+
+import (
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemacompat"
+)
+
+// CompatibilityQuery defines the parameters to check the compatibility of a
+// schema with a newer version.
+type CompatibilityQuery struct {
+	// From and To are the names of the older and the newer schemas.
+	From string `form:"from"`
+	To   string `form:"to"`
+
+	// Sample is the number of the profiles linked to the From schema which
+	// are validated against the To schema, none by default.
+	Sample int64 `form:"sample"`
+}
+
+// CompatibilityReport lists the changes between two schemas.
+type CompatibilityReport struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Compatible is set when none of the changes is breaking.
+	Compatible bool                  `json:"compatible"`
+	Changes    []schemacompat.Change `json:"changes"`
+	// Sample is the validation of the sampled profiles, if requested.
+	Sample *SampleResult `json:"sample,omitempty"`
+}
+
+// Marshall transforms the CompatibilityReport instance to an interface.
+func (report *CompatibilityReport) Marshall() interface{} {
+	return report
+}
+
+// SampleResult is the validation of a sample of the indexed profiles against
+// the newer schema.
+type SampleResult struct {
+	Size    int              `json:"size"`
+	Valid   int              `json:"valid"`
+	Invalid []InvalidProfile `json:"invalid"`
+}
+
+// InvalidProfile is a sampled profile which is invalid against the newer
+// schema.
+type InvalidProfile struct {
+	ProfileURL string   `json:"profile_url"`
+	Errors     []string `json:"errors"`
+}
+
+// Profile is a profile posted to the index.
+type Profile struct {
+	ProfileURL string
+	JSON       string
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"github.com/iancoleman/orderedmap"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// DraftStatus lists the statuses of the schemas proposed to the registry.
+var DraftStatus = struct {
+	// Draft schemas wait for a review.
+	Draft string
+	// Published schemas were approved and added to the library.
+	Published string
+	// Rejected schemas were refused by a reviewer.
+	Rejected string
+}{
+	Draft:     "draft",
+	Published: "published",
+	Rejected:  "rejected",
+}
+
+// Draft is a schema proposed through the registry. It is added to the
+// library once a reviewer approves it.
+type Draft struct {
+	Schema `bson:",inline"`
+	// FullSchema is the schema with its references to the fields resolved.
+	FullSchema bson.D `json:"-" bson:"full_schema,omitempty"`
+	// Fields holds the names of the fields the schema references.
+	Fields []string `json:"fields" bson:"fields"`
+
+	Status     string `json:"status"                bson:"status"`
+	ProposedAt int64  `json:"proposed_at"           bson:"proposed_at"`
+	ReviewedAt int64  `json:"reviewed_at,omitempty" bson:"reviewed_at,omitempty"`
+	// Reason explains why the schema was rejected.
+	Reason string `json:"reason,omitempty" bson:"reason,omitempty"`
+}
+
+// Marshall transforms the Draft instance to an interface, with the full
+// schema when it is loaded.
+func (draft *Draft) Marshall() interface{} {
+	if draft.FullSchema == nil {
+		return draft
+	}
+
+	fullSchema := &SingleSchema{FullSchema: draft.FullSchema}
+	return struct {
+		*Draft
+		FullSchema *orderedmap.OrderedMap `json:"schema"`
+	}{
+		Draft:      draft,
+		FullSchema: fullSchema.ToMap(),
+	}
+}
+
+// Drafts is a slice of Draft instances.
+type Drafts []*Draft
+
+func (drafts Drafts) Marshall() interface{} {
+	data := make([]interface{}, len(drafts))
+	for index, draft := range drafts {
+		data[index] = draft.Marshall()
+	}
+	return data
+}
+
+// ReviewRequest is the body of the requests rejecting a draft.
+type ReviewRequest struct {
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"github.com/iancoleman/orderedmap"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Field defines the structure for a reusable field, which the schemas
+// reference with `$ref`.
+type Field struct {
+	Name        string `json:"name"        bson:"name,omitempty"`
+	Title       string `json:"title"       bson:"title,omitempty"`
+	Description string `json:"description" bson:"description,omitempty"`
+	// UsedBy holds the names of the schemas referencing the field.
+	UsedBy []string `json:"used_by" bson:"used_by"`
+}
+
+// Marshall transforms the Field instance to an interface.
+func (field *Field) Marshall() interface{} {
+	return field
+}
+
+// Fields is a slice of Field instances.
+type Fields []*Field
+
+func (fields Fields) Marshall() interface{} {
+	data := make([]interface{}, len(fields))
+	for index, field := range fields {
+		data[index] = field.Marshall()
+	}
+	return data
+}
+
+// FieldQuery defines the parameters that can be used to list the fields.
+type FieldQuery struct {
+	// Q matches the name, the title or the description, case-insensitively.
+	Q *string `form:"q"`
+
+	// Page and PageSize are used to control the pagination of the results.
+	Page     int64 `form:"page,default=1"`
+	PageSize int64 `form:"page_size,default=100"`
+}
+
+// FieldSearchResult is a page of the fields matching a query.
+type FieldSearchResult struct {
+	Fields          Fields
+	NumberOfResults int64
+	TotalPages      int64
+}
+
+// SingleField represents a field with its full definition, in which the
+// references to other fields are resolved.
+type SingleField struct {
+	Field     `bson:",inline"`
+	FullField bson.D `bson:"full_field"`
+}
+
+// Marshall transforms the SingleField instance to an interface, with the
+// definition of the field in the order of its source.
+func (field *SingleField) Marshall() interface{} {
+	definition := &SingleSchema{FullSchema: field.FullField}
+	return struct {
+		*Field
+		Definition *orderedmap.OrderedMap `json:"definition"`
+	}{
+		Field:      &field.Field,
+		Definition: definition.ToMap(),
+	}
+}
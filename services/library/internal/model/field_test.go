@@ -0,0 +1,35 @@
+package model_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
+)
+
+func TestSingleFieldMarshall(t *testing.T) {
+	field := &model.SingleField{
+		Field: model.Field{
+			Name:   "name",
+			Title:  "Name",
+			UsedBy: []string{"people_schema-v0.1.0"},
+		},
+		FullField: bson.D{
+			{Key: "type", Value: "string"},
+			{Key: "title", Value: "Name"},
+		},
+	}
+
+	data, err := json.Marshal(field.Marshall())
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"name": "name",
+		"title": "Name",
+		"description": "",
+		"used_by": ["people_schema-v0.1.0"],
+		"definition": {"type": "string", "title": "Name"}
+	}`, string(data))
+}
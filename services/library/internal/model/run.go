@@ -0,0 +1,85 @@
+package model
+
+import "github.com/MurmurationsNetwork/MurmurationsServices/pkg/librarygen"
+
+// RunStatus lists the outcomes of the runs of the Schema Parser.
+var RunStatus = struct {
+	Succeeded string
+	Failed    string
+}{
+	Succeeded: "succeeded",
+	Failed:    "failed",
+}
+
+// Run is a run of the Schema Parser which changed, or tried to change, the
+// library: an update from the schema source or a rollback.
+type Run struct {
+	// Action is either "update" or "rollback".
+	Action string `json:"action" bson:"action"`
+	// Status is either "succeeded" or "failed".
+	Status string `json:"status" bson:"status"`
+	// Source, Revision and Commit describe where the schemas were loaded
+	// from, or the generation rolled back to.
+	Source   string `json:"source,omitempty"   bson:"source,omitempty"`
+	Revision string `json:"revision,omitempty" bson:"revision,omitempty"`
+	Commit   string `json:"commit,omitempty"   bson:"commit,omitempty"`
+	// Generation is the active generation of the library after the run.
+	Generation int64 `json:"generation" bson:"generation"`
+	// StartedAt and EndedAt are Unix timestamps.
+	StartedAt int64 `json:"started_at" bson:"started_at"`
+	EndedAt   int64 `json:"ended_at"   bson:"ended_at"`
+	// ChangedSchemas and ChangedFields hold the names of the schemas and
+	// fields the run added or changed, or tried to when it failed.
+	ChangedSchemas []string `json:"changed_schemas"  bson:"changed_schemas"`
+	ChangedFields  []string `json:"changed_fields"   bson:"changed_fields"`
+	Errors         []string `json:"errors,omitempty" bson:"errors,omitempty"`
+}
+
+// Marshall transforms the Run instance to an interface.
+func (run *Run) Marshall() interface{} {
+	return run
+}
+
+// Runs is a slice of Run instances.
+type Runs []*Run
+
+func (runs Runs) Marshall() interface{} {
+	data := make([]interface{}, len(runs))
+	for index, run := range runs {
+		data[index] = run.Marshall()
+	}
+	return data
+}
+
+// RunQuery defines the parameters that can be used to list the runs.
+type RunQuery struct {
+	// Schema only keeps the runs which changed the schema, such as
+	// "organizations_schema-v1.0.0".
+	Schema *string `form:"schema"`
+	// Status only keeps the runs with the status, "succeeded" or "failed".
+	Status *string `form:"status"`
+	// Commit only keeps the runs of the commit. A prefix of the SHA is
+	// enough.
+	Commit *string `form:"commit"`
+
+	// Page and PageSize are used to control the pagination of the results.
+	Page     int64 `form:"page,default=1"`
+	PageSize int64 `form:"page_size,default=100"`
+}
+
+// RunSearchResult is a page of the runs matching a query.
+type RunSearchResult struct {
+	Runs            Runs
+	NumberOfResults int64
+	TotalPages      int64
+}
+
+// UpdateStatus is the state of the updates of the library.
+type UpdateStatus struct {
+	// Active is the generation of the library which is served.
+	Active librarygen.Generation `json:"active"`
+	// LastRun is the latest run, which may have failed.
+	LastRun *Run `json:"last_run"`
+	// LastSuccessfulRun is the latest run which succeeded.
+	LastSuccessfulRun *Run `json:"last_successful_run"`
+}
@@ -1,8 +1,12 @@
 package model
 
 import (
+	"sort"
+
 	"github.com/iancoleman/orderedmap"
 	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemaversion"
 )
 
 // Schema defines the structure for a schema.
@@ -11,6 +15,26 @@ type Schema struct {
 	Description string `json:"description" bson:"description,omitempty"`
 	Name        string `json:"name"        bson:"name,omitempty"`
 	URL         string `json:"url"         bson:"url,omitempty"`
+	// Family and Version are parsed from the name, such as
+	// "organizations_schema" and "1.0.0" for "organizations_schema-v1.0.0".
+	Family  string `json:"family,omitempty"  bson:"-"`
+	Version string `json:"version,omitempty" bson:"-"`
+	// Deprecated schemas can still be linked until SunsetAt, a Unix
+	// timestamp, and should be replaced by the ReplacedBy schema.
+	Deprecated bool   `json:"deprecated,omitempty"  bson:"deprecated,omitempty"`
+	SunsetAt   int64  `json:"sunset_at,omitempty"   bson:"sunset_at,omitempty"`
+	ReplacedBy string `json:"replaced_by,omitempty" bson:"replaced_by,omitempty"`
+}
+
+// SetVersion sets the family and the version of the schema from its name,
+// if it has a version.
+func (schema *Schema) SetVersion() {
+	name, ok := schemaversion.ParseName(schema.Name)
+	if !ok {
+		return
+	}
+	schema.Family = name.Family
+	schema.Version = name.Version.String()
 }
 
 // Marshall transforms the Schema instance to an interface.
@@ -21,6 +45,16 @@ func (schema *Schema) Marshall() interface{} {
 // Schemas is a slice of Schema instances.
 type Schemas []*Schema
 
+// SortByVersion orders the schemas of a family from the oldest version to
+// the newest one.
+func (schemas Schemas) SortByVersion() {
+	sort.SliceStable(schemas, func(i, j int) bool {
+		a, _ := schemaversion.ParseName(schemas[i].Name)
+		b, _ := schemaversion.ParseName(schemas[j].Name)
+		return a.Version.Compare(b.Version) < 0
+	})
+}
+
 func (schemas Schemas) Marshall() interface{} {
 	data := make([]interface{}, len(schemas))
 	for index, schema := range schemas {
@@ -29,6 +63,34 @@ func (schemas Schemas) Marshall() interface{} {
 	return data
 }
 
+// SchemaQuery defines the parameters that can be used to search the schemas.
+type SchemaQuery struct {
+	// Q matches the title or the description, case-insensitively.
+	Q *string `form:"q"`
+
+	// Name matches the beginning of the name of the schemas, such as
+	// "organizations_schema".
+	Name *string `form:"name"`
+
+	// Version matches the version in the name of the schemas, such as
+	// "1.0.0" for "organizations_schema-v1.0.0".
+	Version *string `form:"version"`
+
+	// IncludeDeprecated also returns the deprecated schemas.
+	IncludeDeprecated bool `form:"include_deprecated"`
+
+	// Page and PageSize are used to control the pagination of the results.
+	Page     int64 `form:"page,default=1"`
+	PageSize int64 `form:"page_size,default=100"`
+}
+
+// SchemaSearchResult is a page of the schemas matching a query.
+type SchemaSearchResult struct {
+	Schemas         Schemas
+	NumberOfResults int64
+	TotalPages      int64
+}
+
 // SingleSchema represents a schema with its description and full schema.
 type SingleSchema struct {
 	Description string `bson:"description"`
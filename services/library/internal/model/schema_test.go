@@ -26,6 +26,41 @@ func TestSchema(t *testing.T) {
 	require.Equal(t, want, schema.Marshall())
 }
 
+func TestSchemaSetVersion(t *testing.T) {
+	schema := &model.Schema{Name: "organizations_schema-v1.2.3"}
+	schema.SetVersion()
+	require.Equal(t, "organizations_schema", schema.Family)
+	require.Equal(t, "1.2.3", schema.Version)
+
+	schema = &model.Schema{Name: "unversioned"}
+	schema.SetVersion()
+	require.Empty(t, schema.Family)
+	require.Empty(t, schema.Version)
+}
+
+func TestSchemasSortByVersion(t *testing.T) {
+	schemas := model.Schemas{
+		{Name: "test_schema-v1.10.0"},
+		{Name: "test_schema-v2.0.0"},
+		{Name: "test_schema-v1.2.0"},
+	}
+	schemas.SortByVersion()
+
+	names := make([]string, len(schemas))
+	for i, schema := range schemas {
+		names[i] = schema.Name
+	}
+	require.Equal(
+		t,
+		[]string{
+			"test_schema-v1.2.0",
+			"test_schema-v1.10.0",
+			"test_schema-v2.0.0",
+		},
+		names,
+	)
+}
+
 func TestSchemas(t *testing.T) {
 	schemas := model.Schemas{
 		&model.Schema{
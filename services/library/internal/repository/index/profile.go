@@ -0,0 +1,149 @@
+package index
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
+)
+
+// maxConcurrentFetches is the number of profiles fetched at the same time.
+const maxConcurrentFetches = 10
+
+// ProfileRepo defines the methods to read the profiles posted to the index.
+type ProfileRepo interface {
+	// Sample returns up to size profiles linked to the schema. The profiles
+	// which can't be fetched before the deadline are left out.
+	Sample(
+		ctx context.Context,
+		schemaName string,
+		size int64,
+	) ([]*model.Profile, error)
+}
+
+type profileRepo struct {
+	indexURL string
+	timeout  time.Duration
+	client   *http.Client
+}
+
+// NewProfileRepo returns a new profile repository reading from the index at
+// indexURL. Sampling gives up after timeout, a timeout of zero doesn't limit
+// it.
+func NewProfileRepo(indexURL string, timeout time.Duration) ProfileRepo {
+	return &profileRepo{
+		indexURL: indexURL,
+		timeout:  timeout,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Sample searches the index for the profiles linked to the schema and fetches
+// them from their profile URLs, a few at a time.
+func (r *profileRepo) Sample(
+	ctx context.Context,
+	schemaName string,
+	size int64,
+) ([]*model.Profile, error) {
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	query := url.Values{}
+	query.Set("schema", schemaName)
+	query.Set("page_size", strconv.FormatInt(size, 10))
+	searchURL := r.indexURL + "/v2/nodes?" + query.Encode()
+
+	data, err := r.get(ctx, searchURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search the index: %w", err)
+	}
+
+	var body struct {
+		Data []struct {
+			ProfileURL string `json:"profile_url"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil, fmt.Errorf("failed to decode the index response: %w", err)
+	}
+
+	// The profiles keep the order of the search results.
+	fetched := make([]*model.Profile, len(body.Data))
+	limit := make(chan struct{}, maxConcurrentFetches)
+	var wg sync.WaitGroup
+	for i, node := range body.Data {
+		wg.Add(1)
+		go func(i int, profileURL string) {
+			defer wg.Done()
+			select {
+			case limit <- struct{}{}:
+				defer func() { <-limit }()
+			case <-ctx.Done():
+				return
+			}
+
+			profileJSON, err := r.getJSON(ctx, profileURL)
+			if err != nil {
+				return
+			}
+			fetched[i] = &model.Profile{
+				ProfileURL: profileURL,
+				JSON:       profileJSON,
+			}
+		}(i, node.ProfileURL)
+	}
+	wg.Wait()
+
+	profiles := make([]*model.Profile, 0, len(fetched))
+	for _, profile := range fetched {
+		if profile != nil {
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles, nil
+}
+
+// get returns the body of a successful response to a GET request.
+func (r *profileRepo) get(ctx context.Context, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("response status is %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// getJSON returns the compacted JSON document at the URL.
+func (r *profileRepo) getJSON(
+	ctx context.Context,
+	rawURL string,
+) (string, error) {
+	data, err := r.get(ctx, rawURL)
+	if err != nil {
+		return "", err
+	}
+	var buffer bytes.Buffer
+	if err := json.Compact(&buffer, data); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
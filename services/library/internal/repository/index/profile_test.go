@@ -0,0 +1,87 @@
+package index_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/repository/index"
+)
+
+func TestSample(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/v2/nodes":
+				require.Equal(t, "test_schema-v1.0.0", r.URL.Query().Get("schema"))
+				require.Equal(t, "2", r.URL.Query().Get("page_size"))
+				_, _ = w.Write([]byte(`{"data": [
+					{"profile_url": "` + server.URL + `/profiles/a"},
+					{"profile_url": "` + server.URL + `/profiles/missing"}
+				]}`))
+			case "/profiles/a":
+				_, _ = w.Write([]byte(`{"name": "A"}`))
+			default:
+				http.NotFound(w, r)
+			}
+		}),
+	)
+	defer server.Close()
+
+	profiles, err := index.NewProfileRepo(server.URL, time.Second).
+		Sample(context.Background(), "test_schema-v1.0.0", 2)
+	require.NoError(t, err)
+	require.Equal(t, []*model.Profile{
+		{ProfileURL: server.URL + "/profiles/a", JSON: `{"name":"A"}`},
+	}, profiles)
+}
+
+func TestSample_Deadline(t *testing.T) {
+	slow := make(chan struct{})
+	var server *httptest.Server
+	server = httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/v2/nodes":
+				_, _ = w.Write([]byte(`{"data": [
+					{"profile_url": "` + server.URL + `/profiles/slow"},
+					{"profile_url": "` + server.URL + `/profiles/a"},
+					{"profile_url": "` + server.URL + `/profiles/b"}
+				]}`))
+			case "/profiles/slow":
+				select {
+				case <-slow:
+				case <-r.Context().Done():
+				}
+			default:
+				_, _ = w.Write([]byte(`{"name": "` + r.URL.Path + `"}`))
+			}
+		}),
+	)
+	defer server.Close()
+	defer close(slow)
+
+	start := time.Now()
+	profiles, err := index.NewProfileRepo(server.URL, 200*time.Millisecond).
+		Sample(context.Background(), "test_schema-v1.0.0", 3)
+	require.NoError(t, err)
+	// The slow profile doesn't hold up the others, and is left out once the
+	// deadline passes.
+	require.Less(t, time.Since(start), 2*time.Second)
+	require.Equal(t, []*model.Profile{
+		{
+			ProfileURL: server.URL + "/profiles/a",
+			JSON:       `{"name":"/profiles/a"}`,
+		},
+		{
+			ProfileURL: server.URL + "/profiles/b",
+			JSON:       `{"name":"/profiles/b"}`,
+		},
+	}, profiles)
+}
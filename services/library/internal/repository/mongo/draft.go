@@ -0,0 +1,182 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/librarygen"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/library"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
+)
+
+// DraftRepo defines the methods a DraftRepo can perform.
+type DraftRepo interface {
+	// Save adds the draft, or replaces the draft with the same name.
+	Save(draft *model.Draft) error
+	Get(schemaName string) (*model.Draft, error)
+	// GetAll lists the drafts with the status, or all of them when it's
+	// empty, without their full schemas.
+	GetAll(status string) (model.Drafts, error)
+	// Publish adds the schema of the draft to the library and marks the
+	// draft as published.
+	Publish(draft *model.Draft) error
+}
+
+// maxPublishAttempts is the number of times a schema is published while the
+// schemaparser switches the active generation of the library.
+const maxPublishAttempts = 5
+
+type draftRepo struct{}
+
+// NewDraftRepo returns a new draft repository.
+func NewDraftRepo() DraftRepo {
+	return &draftRepo{}
+}
+
+// Save replaces the draft with the same name, so the fields of a previous
+// proposal aren't kept.
+func (r *draftRepo) Save(draft *model.Draft) error {
+	filter := bson.M{"name": draft.Name}
+	err := mongo.Client.DeleteOne(constant.MongoIndex.SchemaDraft, filter)
+	if err != nil {
+		return library.DatabaseError{Err: err}
+	}
+
+	_, err = mongo.Client.InsertOne(constant.MongoIndex.SchemaDraft, draft)
+	if err != nil {
+		return library.DatabaseError{Err: err}
+	}
+	return nil
+}
+
+// Get retrieves a specific draft from the DB based on its name.
+func (r *draftRepo) Get(schemaName string) (*model.Draft, error) {
+	filter := bson.M{"name": schemaName}
+	result := mongo.Client.FindOne(constant.MongoIndex.SchemaDraft, filter)
+
+	var draft model.Draft
+	err := result.Decode(&draft)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, library.SchemaNotFoundError{SchemaName: schemaName}
+		}
+		return nil, library.DatabaseError{Err: err}
+	}
+
+	draft.SetVersion()
+	return &draft, nil
+}
+
+// GetAll retrieves the drafts with the status, ordered by name.
+func (r *draftRepo) GetAll(status string) (model.Drafts, error) {
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	opts := options.Find().
+		SetSort(bson.M{"name": 1}).
+		SetProjection(bson.M{"full_schema": 0})
+	cur, err := mongo.Client.Find(constant.MongoIndex.SchemaDraft, filter, opts)
+	if err != nil {
+		return nil, library.DatabaseError{Err: err}
+	}
+	defer cur.Close(context.TODO())
+
+	drafts := make(model.Drafts, 0)
+	for cur.Next(context.TODO()) {
+		var draft model.Draft
+		if err := cur.Decode(&draft); err != nil {
+			return nil, library.DatabaseError{Err: err}
+		}
+		draft.SetVersion()
+		drafts = append(drafts, &draft)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, library.DatabaseError{Err: err}
+	}
+	return drafts, nil
+}
+
+// Publish marks the draft as published, writes the schema the way the
+// schemaparser does and records it in the fields it uses. The schema is added
+// to the active generation of the library. When the active generation changes
+// meanwhile, the schema is added to the new one too, unless the schemaparser
+// already copied it there. When the generation keeps changing, the draft is
+// put back up for review and a ConflictError is returned, so the approval can
+// be tried again.
+func (r *draftRepo) Publish(draft *model.Draft) error {
+	// The schemaparser adds the published drafts to the generations it
+	// writes, so the draft is saved first.
+	if err := r.Save(draft); err != nil {
+		return err
+	}
+
+	for attempt := 0; attempt < maxPublishAttempts; attempt++ {
+		meta, err := librarygen.GetMeta()
+		if err != nil {
+			return library.DatabaseError{Err: err}
+		}
+		if err := publishTo(meta.Active.Number, draft); err != nil {
+			return err
+		}
+		ok, err := librarygen.MarkPublished(
+			meta.Active.Number,
+			draft.ReviewedAt,
+		)
+		if err != nil {
+			return library.DatabaseError{Err: err}
+		}
+		if ok {
+			return nil
+		}
+	}
+
+	draft.Status = model.DraftStatus.Draft
+	draft.ReviewedAt = 0
+	if err := r.Save(draft); err != nil {
+		return err
+	}
+	return library.ConflictError{
+		Reason: "The library is being updated, please try again later.",
+	}
+}
+
+// publishTo adds the schema of the draft to a generation of the library.
+func publishTo(generation int64, draft *model.Draft) error {
+	schema := bson.M{
+		"title":       draft.Title,
+		"description": draft.Description,
+		"name":        draft.Name,
+		"url":         draft.URL,
+		"full_schema": draft.FullSchema,
+		"deprecated":  draft.Deprecated,
+		"sunset_at":   draft.SunsetAt,
+		"replaced_by": draft.ReplacedBy,
+	}
+	_, err := mongo.Client.FindOneAndUpdate(
+		librarygen.Collection(constant.MongoIndex.Schema, generation),
+		bson.M{"name": draft.Name},
+		bson.M{"$set": schema},
+		options.FindOneAndUpdate().SetUpsert(true),
+	)
+	if err != nil {
+		return library.DatabaseError{Err: err}
+	}
+
+	for _, field := range draft.Fields {
+		_, err := mongo.Client.FindOneAndUpdate(
+			librarygen.Collection(constant.MongoIndex.Field, generation),
+			bson.M{"name": field},
+			bson.M{"$addToSet": bson.M{"used_by": draft.Name}},
+		)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return library.DatabaseError{Err: err}
+		}
+	}
+	return nil
+}
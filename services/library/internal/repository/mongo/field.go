@@ -0,0 +1,146 @@
+package mongo
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/pagination"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/library"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
+)
+
+// FieldRepo defines the methods a FieldRepo can perform.
+type FieldRepo interface {
+	Get(fieldName string) (*model.SingleField, error)
+	Search(q *model.FieldQuery) (*model.FieldSearchResult, error)
+	// GetDefinitions returns the definitions of all the fields as JSON,
+	// keyed by their file names such as "name.json".
+	GetDefinitions() (map[string][]byte, error)
+}
+
+type fieldRepo struct{}
+
+// NewFieldRepo returns a new field repository.
+func NewFieldRepo() FieldRepo {
+	return &fieldRepo{}
+}
+
+// Get retrieves a specific field from the DB based on its name.
+func (r *fieldRepo) Get(fieldName string) (*model.SingleField, error) {
+	collection, err := activeCollection(constant.MongoIndex.Field)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"name": fieldName}
+	result := mongo.Client.FindOne(collection, filter)
+
+	var field model.SingleField
+	err = result.Decode(&field)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, library.FieldNotFoundError{FieldName: fieldName}
+		}
+		return nil, library.DatabaseError{Err: err}
+	}
+
+	return &field, nil
+}
+
+// Search retrieves a page of the fields matching the query, ordered by name.
+func (r *fieldRepo) Search(
+	q *model.FieldQuery,
+) (*model.FieldSearchResult, error) {
+	collection, err := activeCollection(constant.MongoIndex.Field)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := buildFieldFilter(q)
+	count, err := mongo.Client.Count(collection, filter)
+	if err != nil {
+		return nil, library.DatabaseError{Err: err}
+	}
+
+	opts := options.Find().
+		SetSort(bson.M{"name": 1}).
+		SetSkip(pagination.From(q.Page, q.PageSize)).
+		SetLimit(pagination.Size(q.PageSize)).
+		SetProjection(bson.M{"full_field": 0})
+	cur, err := mongo.Client.Find(collection, filter, opts)
+	if err != nil {
+		return nil, library.DatabaseError{Err: err}
+	}
+	defer cur.Close(context.TODO())
+
+	fields := make(model.Fields, 0)
+	for cur.Next(context.TODO()) {
+		var field model.Field
+		if err := cur.Decode(&field); err != nil {
+			return nil, library.DatabaseError{Err: err}
+		}
+		fields = append(fields, &field)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, library.DatabaseError{Err: err}
+	}
+
+	return &model.FieldSearchResult{
+		Fields:          fields,
+		NumberOfResults: count,
+		TotalPages:      pagination.TotalPages(count, q.PageSize),
+	}, nil
+}
+
+// GetDefinitions retrieves the definitions of all the fields.
+func (r *fieldRepo) GetDefinitions() (map[string][]byte, error) {
+	collection, err := activeCollection(constant.MongoIndex.Field)
+	if err != nil {
+		return nil, err
+	}
+
+	cur, err := mongo.Client.Find(collection, bson.M{})
+	if err != nil {
+		return nil, library.DatabaseError{Err: err}
+	}
+	defer cur.Close(context.TODO())
+
+	definitions := make(map[string][]byte)
+	for cur.Next(context.TODO()) {
+		var field model.SingleField
+		if err := cur.Decode(&field); err != nil {
+			return nil, library.DatabaseError{Err: err}
+		}
+		definition := &model.SingleSchema{FullSchema: field.FullField}
+		data, err := json.Marshal(definition.ToMap())
+		if err != nil {
+			return nil, err
+		}
+		definitions[field.Name+".json"] = data
+	}
+	if err := cur.Err(); err != nil {
+		return nil, library.DatabaseError{Err: err}
+	}
+	return definitions, nil
+}
+
+// buildFieldFilter returns the MongoDB filter matching the query.
+func buildFieldFilter(q *model.FieldQuery) bson.M {
+	filter := bson.M{}
+	if q.Q != nil && *q.Q != "" {
+		text := primitive.Regex{Pattern: regexp.QuoteMeta(*q.Q), Options: "i"}
+		filter["$or"] = bson.A{
+			bson.M{"name": text},
+			bson.M{"title": text},
+			bson.M{"description": text},
+		}
+	}
+	return filter
+}
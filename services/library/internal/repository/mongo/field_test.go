@@ -0,0 +1,29 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
+)
+
+func TestBuildFieldFilter(t *testing.T) {
+	require.Equal(t, bson.M{}, buildFieldFilter(&model.FieldQuery{}))
+
+	q := "geo.location"
+	text := primitive.Regex{Pattern: `geo\.location`, Options: "i"}
+	require.Equal(
+		t,
+		bson.M{
+			"$or": bson.A{
+				bson.M{"name": text},
+				bson.M{"title": text},
+				bson.M{"description": text},
+			},
+		},
+		buildFieldFilter(&model.FieldQuery{Q: &q}),
+	)
+}
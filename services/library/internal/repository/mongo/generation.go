@@ -0,0 +1,16 @@
+package mongo
+
+import (
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/librarygen"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/library"
+)
+
+// activeCollection returns the name of the collection of the active
+// generation of the library, such as "schemas_g3" for "schemas".
+func activeCollection(base string) (string, error) {
+	meta, err := librarygen.GetMeta()
+	if err != nil {
+		return "", library.DatabaseError{Err: err}
+	}
+	return librarygen.Collection(base, meta.Active.Number), nil
+}
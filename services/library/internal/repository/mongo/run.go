@@ -0,0 +1,113 @@
+package mongo
+
+import (
+	"context"
+	"regexp"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/pagination"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/library"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
+)
+
+// RunRepo defines the methods a RunRepo can perform.
+type RunRepo interface {
+	// Search retrieves a page of the runs of the Schema Parser matching the
+	// query, from the newest to the oldest.
+	Search(q *model.RunQuery) (*model.RunSearchResult, error)
+	// GetLast retrieves the latest run with the status, or the latest run
+	// when it's empty. It returns nil when there is no such run.
+	GetLast(status string) (*model.Run, error)
+}
+
+type runRepo struct{}
+
+// NewRunRepo returns a new run repository.
+func NewRunRepo() RunRepo {
+	return &runRepo{}
+}
+
+// newestFirst orders the runs from the newest to the oldest.
+var newestFirst = bson.D{
+	{Key: "started_at", Value: -1},
+	{Key: "_id", Value: -1},
+}
+
+// Search retrieves a page of the runs matching the query, from the newest to
+// the oldest.
+func (r *runRepo) Search(q *model.RunQuery) (*model.RunSearchResult, error) {
+	filter := buildRunFilter(q)
+
+	count, err := mongo.Client.Count(constant.MongoIndex.SchemaRun, filter)
+	if err != nil {
+		return nil, library.DatabaseError{Err: err}
+	}
+
+	opts := options.Find().
+		SetSort(newestFirst).
+		SetSkip(pagination.From(q.Page, q.PageSize)).
+		SetLimit(pagination.Size(q.PageSize))
+	cur, err := mongo.Client.Find(constant.MongoIndex.SchemaRun, filter, opts)
+	if err != nil {
+		return nil, library.DatabaseError{Err: err}
+	}
+	defer cur.Close(context.TODO())
+
+	runs := make(model.Runs, 0)
+	if err := cur.All(context.TODO(), &runs); err != nil {
+		return nil, library.DatabaseError{Err: err}
+	}
+
+	return &model.RunSearchResult{
+		Runs:            runs,
+		NumberOfResults: count,
+		TotalPages:      pagination.TotalPages(count, q.PageSize),
+	}, nil
+}
+
+// GetLast retrieves the latest run with the status, or the latest run when
+// it's empty.
+func (r *runRepo) GetLast(status string) (*model.Run, error) {
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	opts := options.Find().SetSort(newestFirst).SetLimit(1)
+	cur, err := mongo.Client.Find(constant.MongoIndex.SchemaRun, filter, opts)
+	if err != nil {
+		return nil, library.DatabaseError{Err: err}
+	}
+	defer cur.Close(context.TODO())
+
+	var runs model.Runs
+	if err := cur.All(context.TODO(), &runs); err != nil {
+		return nil, library.DatabaseError{Err: err}
+	}
+	if len(runs) == 0 {
+		return nil, nil
+	}
+	return runs[0], nil
+}
+
+// buildRunFilter returns the MongoDB filter matching the query.
+func buildRunFilter(q *model.RunQuery) bson.M {
+	filter := bson.M{}
+	if q.Schema != nil && *q.Schema != "" {
+		filter["changed_schemas"] = *q.Schema
+	}
+	if q.Status != nil && *q.Status != "" {
+		filter["status"] = *q.Status
+	}
+	if q.Commit != nil && *q.Commit != "" {
+		filter["commit"] = primitive.Regex{
+			Pattern: "^" + regexp.QuoteMeta(*q.Commit),
+		}
+	}
+	return filter
+}
@@ -0,0 +1,30 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
+)
+
+func TestBuildRunFilter(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	require.Equal(t, bson.M{}, buildRunFilter(&model.RunQuery{}))
+	require.Equal(
+		t,
+		bson.M{
+			"changed_schemas": "test_schema-v1.0.0",
+			"status":          "failed",
+			"commit":          primitive.Regex{Pattern: "^3f7868"},
+		},
+		buildRunFilter(&model.RunQuery{
+			Schema: str("test_schema-v1.0.0"),
+			Status: str("failed"),
+			Commit: str("3f7868"),
+		}),
+	)
+}
@@ -2,11 +2,18 @@ package mongo
 
 import (
 	"context"
+	"regexp"
+	"strings"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/librarygen"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/pagination"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/library"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
 )
@@ -14,7 +21,13 @@ import (
 // SchemaRepo defines the methods a SchemaRepo can perform.
 type SchemaRepo interface {
 	Get(schemaName string) (interface{}, error)
-	Search() (*model.Schemas, error)
+	// GetMeta returns the active and the previous generations of the
+	// library.
+	GetMeta() (*librarygen.Meta, error)
+	Search(q *model.SchemaQuery) (*model.SchemaSearchResult, error)
+	// GetVersions returns the versions of a family of schemas, from the
+	// oldest to the newest.
+	GetVersions(family string) (model.Schemas, error)
 }
 
 type schemaRepo struct{}
@@ -26,11 +39,16 @@ func NewSchemaRepo() SchemaRepo {
 
 // Get retrieves a specific schema from the DB based on its name.
 func (r *schemaRepo) Get(schemaName string) (interface{}, error) {
+	collection, err := activeCollection(constant.MongoIndex.Schema)
+	if err != nil {
+		return nil, err
+	}
+
 	filter := bson.M{"name": schemaName}
-	result := mongo.Client.FindOne(constant.MongoIndex.Schema, filter)
+	result := mongo.Client.FindOne(collection, filter)
 
 	var singleSchema model.SingleSchema
-	err := result.Decode(&singleSchema)
+	err = result.Decode(&singleSchema)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, library.SchemaNotFoundError{SchemaName: schemaName}
@@ -41,30 +59,135 @@ func (r *schemaRepo) Get(schemaName string) (interface{}, error) {
 	return singleSchema.ToMap(), nil
 }
 
-// Search retrieves all schemas from the DB.
-func (r *schemaRepo) Search() (*model.Schemas, error) {
-	filter := bson.M{}
+// GetMeta returns the active and the previous generations of the library.
+func (r *schemaRepo) GetMeta() (*librarygen.Meta, error) {
+	meta, err := librarygen.GetMeta()
+	if err != nil {
+		return nil, library.DatabaseError{Err: err}
+	}
+	return meta, nil
+}
+
+// Search retrieves a page of the schemas matching the query, ordered by name.
+func (r *schemaRepo) Search(
+	q *model.SchemaQuery,
+) (*model.SchemaSearchResult, error) {
+	collection, err := activeCollection(constant.MongoIndex.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := buildSearchFilter(q)
+	count, err := mongo.Client.Count(collection, filter)
+	if err != nil {
+		return nil, library.DatabaseError{Err: err}
+	}
 
-	cur, err := mongo.Client.Find(constant.MongoIndex.Schema, filter)
+	opts := options.Find().
+		SetSort(bson.M{"name": 1}).
+		SetSkip(pagination.From(q.Page, q.PageSize)).
+		SetLimit(pagination.Size(q.PageSize))
+	cur, err := mongo.Client.Find(collection, filter, opts)
 	if err != nil {
 		return nil, library.DatabaseError{Err: err}
 	}
+	defer cur.Close(context.TODO())
+
+	schemas, err := decodeSchemas(cur)
+	if err != nil {
+		return nil, err
+	}
 
-	var schemas model.Schemas
+	return &model.SchemaSearchResult{
+		Schemas:         schemas,
+		NumberOfResults: count,
+		TotalPages:      pagination.TotalPages(count, q.PageSize),
+	}, nil
+}
+
+// GetVersions returns the versions of a family of schemas, from the oldest to
+// the newest.
+func (r *schemaRepo) GetVersions(family string) (model.Schemas, error) {
+	collection, err := activeCollection(constant.MongoIndex.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"name": primitive.Regex{Pattern: "^" + regexp.QuoteMeta(family) + "-v"},
+	}
+
+	cur, err := mongo.Client.Find(collection, filter)
+	if err != nil {
+		return nil, library.DatabaseError{Err: err}
+	}
+	defer cur.Close(context.TODO())
+
+	schemas, err := decodeSchemas(cur)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make(model.Schemas, 0, len(schemas))
+	for _, schema := range schemas {
+		if schema.Family == family {
+			versions = append(versions, schema)
+		}
+	}
+	if len(versions) == 0 {
+		return nil, library.SchemaNotFoundError{SchemaName: family}
+	}
+	versions.SortByVersion()
+	return versions, nil
+}
+
+func decodeSchemas(cur *mongodriver.Cursor) (model.Schemas, error) {
+	schemas := make(model.Schemas, 0)
 	for cur.Next(context.TODO()) {
 		var schema model.Schema
 		err := cur.Decode(&schema)
 		if err != nil {
 			return nil, library.DatabaseError{Err: err}
 		}
+		schema.SetVersion()
 		schemas = append(schemas, &schema)
 	}
 
 	if err := cur.Err(); err != nil {
 		return nil, library.DatabaseError{Err: err}
 	}
+	return schemas, nil
+}
 
-	cur.Close(context.TODO())
+// buildSearchFilter returns the MongoDB filter matching the query.
+func buildSearchFilter(q *model.SchemaQuery) bson.M {
+	filter := bson.M{}
+	if q.Q != nil && *q.Q != "" {
+		text := primitive.Regex{Pattern: regexp.QuoteMeta(*q.Q), Options: "i"}
+		filter["$or"] = bson.A{
+			bson.M{"title": text},
+			bson.M{"description": text},
+		}
+	}
 
-	return &schemas, nil
+	// The version is the end of the name, such as "-v1.0.0".
+	var name string
+	if q.Name != nil && *q.Name != "" {
+		name = "^" + regexp.QuoteMeta(*q.Name)
+	}
+	if q.Version != nil && *q.Version != "" {
+		version := strings.TrimPrefix(*q.Version, "v")
+		if name != "" {
+			name += ".*"
+		}
+		name += "-v" + regexp.QuoteMeta(version) + "$"
+	}
+	if name != "" {
+		filter["name"] = primitive.Regex{Pattern: name}
+	}
+
+	if !q.IncludeDeprecated {
+		filter["deprecated"] = bson.M{"$ne": true}
+	}
+	return filter
 }
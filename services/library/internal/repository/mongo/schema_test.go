@@ -0,0 +1,73 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
+)
+
+func TestBuildSearchFilter(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name   string
+		query  model.SchemaQuery
+		filter bson.M
+	}{
+		{
+			name:  "no parameters",
+			query: model.SchemaQuery{},
+			filter: bson.M{
+				"deprecated": bson.M{"$ne": true},
+			},
+		},
+		{
+			name:  "text",
+			query: model.SchemaQuery{Q: str("map (people)")},
+			filter: bson.M{
+				"$or": bson.A{
+					bson.M{"title": primitive.Regex{
+						Pattern: `map \(people\)`,
+						Options: "i",
+					}},
+					bson.M{"description": primitive.Regex{
+						Pattern: `map \(people\)`,
+						Options: "i",
+					}},
+				},
+				"deprecated": bson.M{"$ne": true},
+			},
+		},
+		{
+			name: "name and version",
+			query: model.SchemaQuery{
+				Name:              str("people_schema"),
+				Version:           str("v0.1.0"),
+				IncludeDeprecated: true,
+			},
+			filter: bson.M{
+				"name": primitive.Regex{
+					Pattern: `^people_schema.*-v0\.1\.0$`,
+				},
+			},
+		},
+		{
+			name:  "version",
+			query: model.SchemaQuery{Version: str("1.0.0")},
+			filter: bson.M{
+				"name":       primitive.Regex{Pattern: `-v1\.0\.0$`},
+				"deprecated": bson.M{"$ne": true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.filter, buildSearchFilter(&tt.query))
+		})
+	}
+}
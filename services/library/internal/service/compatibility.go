@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/profile/profilevalidator"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemacompat"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/library"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/repository/index"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/repository/mongo"
+)
+
+// CompatibilityService defines methods to compare the versions of Schemas.
+type CompatibilityService interface {
+	Check(
+		ctx context.Context,
+		q *model.CompatibilityQuery,
+	) (*model.CompatibilityReport, error)
+}
+
+type compatibilityService struct {
+	schemaRepo  mongo.SchemaRepo
+	profileRepo index.ProfileRepo
+}
+
+// NewCompatibilityService creates a new CompatibilityService. The profile
+// repository can be nil, the profiles can't be sampled then.
+func NewCompatibilityService(
+	schemaRepo mongo.SchemaRepo,
+	profileRepo index.ProfileRepo,
+) CompatibilityService {
+	return &compatibilityService{
+		schemaRepo:  schemaRepo,
+		profileRepo: profileRepo,
+	}
+}
+
+// Check lists the changes from the From Schema to the To Schema and, if
+// requested, validates a sample of the profiles linked to the From Schema
+// against the To Schema.
+func (s *compatibilityService) Check(
+	ctx context.Context,
+	q *model.CompatibilityQuery,
+) (*model.CompatibilityReport, error) {
+	if q.Sample > 0 && s.profileRepo == nil {
+		return nil, library.InvalidQueryError{
+			Reason: "Sampling the profiles isn't available in this library.",
+		}
+	}
+
+	_, from, err := s.getSchema(q.From)
+	if err != nil {
+		return nil, err
+	}
+	toJSON, to, err := s.getSchema(q.To)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := schemacompat.Compare(from, to)
+	report := &model.CompatibilityReport{
+		From:       q.From,
+		To:         q.To,
+		Compatible: schemacompat.IsCompatible(changes),
+		Changes:    changes,
+	}
+	if report.Changes == nil {
+		report.Changes = []schemacompat.Change{}
+	}
+
+	if q.Sample > 0 {
+		report.Sample, err = s.validateSample(ctx, q, toJSON)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+// getSchema returns the Schema as JSON and decoded into a map.
+func (s *compatibilityService) getSchema(
+	schemaName string,
+) ([]byte, map[string]interface{}, error) {
+	schema, err := s.schemaRepo.Get(schemaName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode %s: %w", schemaName, err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode %s: %w", schemaName, err)
+	}
+	return data, decoded, nil
+}
+
+func (s *compatibilityService) validateSample(
+	ctx context.Context,
+	q *model.CompatibilityQuery,
+	toJSON []byte,
+) (*model.SampleResult, error) {
+	profiles, err := s.profileRepo.Sample(ctx, q.From, q.Sample)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.SampleResult{
+		Size:    len(profiles),
+		Invalid: []model.InvalidProfile{},
+	}
+	for _, profile := range profiles {
+		validator, err := profilevalidator.NewBuilder().
+			WithJSONSchemas([]string{q.To}, []string{string(toJSON)}).
+			WithStrProfile(profile.JSON).
+			Build()
+		if err != nil {
+			result.Invalid = append(result.Invalid, model.InvalidProfile{
+				ProfileURL: profile.ProfileURL,
+				Errors:     []string{err.Error()},
+			})
+			continue
+		}
+
+		validation := validator.Validate()
+		if validation.Valid {
+			result.Valid++
+			continue
+		}
+		result.Invalid = append(result.Invalid, model.InvalidProfile{
+			ProfileURL: profile.ProfileURL,
+			Errors:     validation.Details,
+		})
+	}
+	return result, nil
+}
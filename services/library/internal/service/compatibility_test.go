@@ -0,0 +1,97 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemacompat"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/library"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/service"
+)
+
+type MockProfileRepo struct {
+	mock.Mock
+}
+
+func (m *MockProfileRepo) Sample(
+	_ context.Context,
+	schemaName string,
+	size int64,
+) ([]*model.Profile, error) {
+	args := m.Called(schemaName, size)
+	return args.Get(0).([]*model.Profile), args.Error(1)
+}
+
+func TestCompatibilityService_Check(t *testing.T) {
+	mockRepo := new(MockRepo)
+	mockRepo.On("Get", "test_schema-v1.0.0").Return(json.RawMessage(`{
+		"type": "object",
+		"properties": {"name": {"type": "string"}},
+		"required": ["name"]
+	}`), nil)
+	mockRepo.On("Get", "test_schema-v2.0.0").Return(json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"email": {"type": "string"}
+		},
+		"required": ["name", "email"]
+	}`), nil)
+	mockProfileRepo := new(MockProfileRepo)
+	mockProfileRepo.On("Sample", "test_schema-v1.0.0", int64(10)).
+		Return([]*model.Profile{
+			{
+				ProfileURL: "https://example.com/a",
+				JSON:       `{"name": "A", "email": "a@example.com"}`,
+			},
+			{ProfileURL: "https://example.com/b", JSON: `{"name": "B"}`},
+		}, nil)
+	s := service.NewCompatibilityService(mockRepo, mockProfileRepo)
+
+	report, err := s.Check(context.Background(), &model.CompatibilityQuery{
+		From:   "test_schema-v1.0.0",
+		To:     "test_schema-v2.0.0",
+		Sample: 10,
+	})
+	assert.NoError(t, err)
+	assert.False(t, report.Compatible)
+	assert.Equal(t, []schemacompat.Change{
+		{
+			Path:     "/properties/email",
+			Kind:     schemacompat.RequiredAdded,
+			Breaking: true,
+			Detail:   "The `email` field is now required.",
+		},
+		{
+			Path:   "/properties/email",
+			Kind:   schemacompat.PropertyAdded,
+			Detail: "The `email` field was added.",
+		},
+	}, report.Changes)
+	assert.Equal(t, 2, report.Sample.Size)
+	assert.Equal(t, 1, report.Sample.Valid)
+	assert.Len(t, report.Sample.Invalid, 1)
+	assert.Equal(
+		t,
+		"https://example.com/b",
+		report.Sample.Invalid[0].ProfileURL,
+	)
+	mockRepo.AssertExpectations(t)
+	mockProfileRepo.AssertExpectations(t)
+}
+
+func TestCompatibilityService_CheckWithoutIndex(t *testing.T) {
+	s := service.NewCompatibilityService(new(MockRepo), nil)
+
+	_, err := s.Check(context.Background(), &model.CompatibilityQuery{
+		From:   "test_schema-v1.0.0",
+		To:     "test_schema-v2.0.0",
+		Sample: 10,
+	})
+	assert.ErrorAs(t, err, &library.InvalidQueryError{})
+}
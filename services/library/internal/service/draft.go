@@ -0,0 +1,195 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/dateutil"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemalint"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/library"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/repository/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/pkg/parser"
+)
+
+// DraftService defines methods to propose and review Schemas.
+type DraftService interface {
+	// Propose parses and checks a Schema, and saves it as a draft.
+	Propose(data []byte) (*model.Draft, error)
+	Get(schemaName string) (*model.Draft, error)
+	GetAll(status string) (model.Drafts, error)
+	// Approve publishes a draft to the library.
+	Approve(schemaName string) (*model.Draft, error)
+	Reject(schemaName string, reason string) (*model.Draft, error)
+}
+
+type draftService struct {
+	draftRepo  mongo.DraftRepo
+	schemaRepo mongo.SchemaRepo
+	fieldRepo  mongo.FieldRepo
+}
+
+// NewDraftService creates a new DraftService with the given repositories.
+func NewDraftService(
+	draftRepo mongo.DraftRepo,
+	schemaRepo mongo.SchemaRepo,
+	fieldRepo mongo.FieldRepo,
+) DraftService {
+	return &draftService{
+		draftRepo:  draftRepo,
+		schemaRepo: schemaRepo,
+		fieldRepo:  fieldRepo,
+	}
+}
+
+// Propose resolves the references of the Schema to the fields of the
+// library, like the schemaparser does, checks the result and saves it as a
+// draft, replacing a previous proposal of the same Schema.
+func (s *draftService) Propose(data []byte) (*model.Draft, error) {
+	fields, err := s.fieldRepo.GetDefinitions()
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := parser.ParseSchema(data, fields)
+	if err != nil {
+		return nil, library.InvalidSchemaError{
+			Reasons: []string{fmt.Sprintf("%s.", err)},
+		}
+	}
+
+	fullSchema := &model.SingleSchema{FullSchema: parsed.FullSchema}
+	fullJSON, err := json.Marshal(fullSchema.ToMap())
+	if err != nil {
+		return nil, err
+	}
+	if reasons := checkSchema(fullJSON); len(reasons) > 0 {
+		return nil, library.InvalidSchemaError{Reasons: reasons}
+	}
+
+	if err := s.checkNotPublished(parsed.Name); err != nil {
+		return nil, err
+	}
+
+	draft := &model.Draft{
+		Schema: model.Schema{
+			Title:       parsed.Title,
+			Description: parsed.Description,
+			Name:        parsed.Name,
+			URL:         parsed.URL,
+			Deprecated:  parsed.Deprecated,
+			SunsetAt:    parsed.SunsetAt,
+			ReplacedBy:  parsed.ReplacedBy,
+		},
+		FullSchema: parsed.FullSchema,
+		Fields:     parsed.Fields,
+		Status:     model.DraftStatus.Draft,
+		ProposedAt: dateutil.GetNowUnix(),
+	}
+	if draft.Fields == nil {
+		draft.Fields = []string{}
+	}
+	if err := s.draftRepo.Save(draft); err != nil {
+		return nil, err
+	}
+
+	draft.SetVersion()
+	return draft, nil
+}
+
+// Get fetches a draft with the given name.
+func (s *draftService) Get(schemaName string) (*model.Draft, error) {
+	return s.draftRepo.Get(schemaName)
+}
+
+// GetAll lists the drafts with the given status, or all of them.
+func (s *draftService) GetAll(status string) (model.Drafts, error) {
+	return s.draftRepo.GetAll(status)
+}
+
+// Approve adds the Schema of a draft to the library.
+func (s *draftService) Approve(schemaName string) (*model.Draft, error) {
+	draft, err := s.getForReview(schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	// The Schema may have been added from another source in the meantime.
+	if err := s.checkNotPublished(schemaName); err != nil {
+		return nil, err
+	}
+
+	draft.Status = model.DraftStatus.Published
+	draft.ReviewedAt = dateutil.GetNowUnix()
+	if err := s.draftRepo.Publish(draft); err != nil {
+		return nil, err
+	}
+	return draft, nil
+}
+
+// Reject refuses a draft, explaining why to its author.
+func (s *draftService) Reject(
+	schemaName string,
+	reason string,
+) (*model.Draft, error) {
+	draft, err := s.getForReview(schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	draft.Status = model.DraftStatus.Rejected
+	draft.ReviewedAt = dateutil.GetNowUnix()
+	draft.Reason = reason
+	if err := s.draftRepo.Save(draft); err != nil {
+		return nil, err
+	}
+	return draft, nil
+}
+
+// getForReview fetches a draft which hasn't been reviewed yet.
+func (s *draftService) getForReview(schemaName string) (*model.Draft, error) {
+	draft, err := s.draftRepo.Get(schemaName)
+	if err != nil {
+		return nil, err
+	}
+	if draft.Status != model.DraftStatus.Draft {
+		return nil, library.ConflictError{
+			Reason: fmt.Sprintf(
+				"The `%s` schema was already %s.",
+				schemaName,
+				draft.Status,
+			),
+		}
+	}
+	return draft, nil
+}
+
+// checkNotPublished returns a ConflictError when the Schema is already in
+// the library, the published Schemas can't be changed.
+func (s *draftService) checkNotPublished(schemaName string) error {
+	_, err := s.schemaRepo.Get(schemaName)
+	if err == nil {
+		return library.ConflictError{
+			Reason: fmt.Sprintf(
+				"The `%s` schema is already in the library, please propose "+
+					"a new version instead.",
+				schemaName,
+			),
+		}
+	}
+	if errors.As(err, &library.SchemaNotFoundError{}) {
+		return nil
+	}
+	return err
+}
+
+// checkSchema returns the reasons why a parsed Schema can't be added to the
+// library, the errors the schemaparser would refuse it with.
+func checkSchema(fullJSON []byte) []string {
+	var reasons []string
+	for _, issue := range schemalint.Errors(schemalint.Schema("", fullJSON)) {
+		reasons = append(reasons, issue.Message)
+	}
+	return reasons
+}
@@ -0,0 +1,223 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/library"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/service"
+)
+
+type MockFieldRepo struct {
+	mock.Mock
+}
+
+func (m *MockFieldRepo) Get(fieldName string) (*model.SingleField, error) {
+	args := m.Called(fieldName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SingleField), args.Error(1)
+}
+
+func (m *MockFieldRepo) Search(
+	q *model.FieldQuery,
+) (*model.FieldSearchResult, error) {
+	args := m.Called(q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.FieldSearchResult), args.Error(1)
+}
+
+func (m *MockFieldRepo) GetDefinitions() (map[string][]byte, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string][]byte), args.Error(1)
+}
+
+// fakeDraftRepo keeps the drafts in memory.
+type fakeDraftRepo struct {
+	drafts    map[string]*model.Draft
+	published []string
+}
+
+func (r *fakeDraftRepo) Save(draft *model.Draft) error {
+	r.drafts[draft.Name] = draft
+	return nil
+}
+
+func (r *fakeDraftRepo) Get(schemaName string) (*model.Draft, error) {
+	draft, ok := r.drafts[schemaName]
+	if !ok {
+		return nil, library.SchemaNotFoundError{SchemaName: schemaName}
+	}
+	return draft, nil
+}
+
+func (r *fakeDraftRepo) GetAll(_ string) (model.Drafts, error) {
+	var drafts model.Drafts
+	for _, draft := range r.drafts {
+		drafts = append(drafts, draft)
+	}
+	return drafts, nil
+}
+
+func (r *fakeDraftRepo) Publish(draft *model.Draft) error {
+	r.published = append(r.published, draft.Name)
+	return r.Save(draft)
+}
+
+const draftSchema = `{
+	"$schema": "https://json-schema.org/draft-07/schema#",
+	"title": "Test Schema",
+	"description": "A schema for the tests.",
+	"type": "object",
+	"properties": {
+		"linked_schemas": {"$ref": "../fields/linked_schemas.json"},
+		"name": {"$ref": "../fields/name.json"}
+	},
+	"required": ["linked_schemas", "name"],
+	"metadata": {"schema": {
+		"name": "test_schema-v1.0.0",
+		"purpose": "Testing.",
+		"url": "https://example.org/test_schema-v1.0.0"
+	}}
+}`
+
+func newDraftService(
+	t *testing.T,
+) (service.DraftService, *fakeDraftRepo, *MockRepo) {
+	t.Helper()
+
+	fieldRepo := new(MockFieldRepo)
+	fieldRepo.On("GetDefinitions").Return(map[string][]byte{
+		"linked_schemas.json": []byte(`{"title": "Linked Schemas",
+			"type": "array", "items": {"type": "string"}}`),
+		"name.json": []byte(`{"title": "Name", "type": "string"}`),
+	}, nil)
+	schemaRepo := new(MockRepo)
+	schemaRepo.On("Get", mock.Anything).Return(
+		nil,
+		library.SchemaNotFoundError{SchemaName: "test_schema-v1.0.0"},
+	)
+	draftRepo := &fakeDraftRepo{drafts: map[string]*model.Draft{}}
+
+	return service.NewDraftService(draftRepo, schemaRepo, fieldRepo),
+		draftRepo,
+		schemaRepo
+}
+
+func TestDraftService_Propose(t *testing.T) {
+	svc, draftRepo, _ := newDraftService(t)
+
+	draft, err := svc.Propose([]byte(draftSchema))
+	require.NoError(t, err)
+	require.Equal(t, "test_schema-v1.0.0", draft.Name)
+	require.Equal(t, "Test Schema", draft.Title)
+	require.Equal(t, model.DraftStatus.Draft, draft.Status)
+	require.Equal(t, []string{"linked_schemas", "name"}, draft.Fields)
+	require.NotZero(t, draft.ProposedAt)
+	require.NotNil(t, draft.FullSchema)
+	require.Contains(t, draftRepo.drafts, "test_schema-v1.0.0")
+}
+
+func TestDraftService_ProposeInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		reasons []string
+	}{
+		{
+			name: "unknown field",
+			schema: `{"title": "Test", "type": "object",
+				"properties": {"tags": {"$ref": "../fields/tags.json"}},
+				"metadata": {"schema": {"name": "test_schema-v1.0.0"}}}`,
+		},
+		{
+			name: "missing metadata",
+			schema: `{"type": "object", "properties": {
+				"linked_schemas": {"$ref": "../fields/linked_schemas.json"}},
+				"metadata": {"schema": {"name": "test_schema"}}}`,
+			reasons: []string{
+				"The `title` of the schema is missing.",
+				"The `metadata.schema.name` must end with the version of " +
+					"the schema, such as `my_schema-v1.0.0`.",
+			},
+		},
+		{
+			name: "missing linked schemas",
+			schema: `{"title": "Test", "type": "object", "properties": {},
+				"metadata": {"schema": {"name": "test_schema-v1.0.0"}}}`,
+			reasons: []string{
+				"The schema must have the `linked_schemas` property.",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, draftRepo, _ := newDraftService(t)
+
+			_, err := svc.Propose([]byte(tt.schema))
+			var invalidSchemaError library.InvalidSchemaError
+			require.ErrorAs(t, err, &invalidSchemaError)
+			if tt.reasons != nil {
+				require.Equal(t, tt.reasons, invalidSchemaError.Reasons)
+			}
+			require.Empty(t, draftRepo.drafts)
+		})
+	}
+}
+
+func TestDraftService_ProposePublished(t *testing.T) {
+	fieldRepo := new(MockFieldRepo)
+	fieldRepo.On("GetDefinitions").Return(map[string][]byte{
+		"linked_schemas.json": []byte(`{"type": "array"}`),
+		"name.json":           []byte(`{"type": "string"}`),
+	}, nil)
+	schemaRepo := new(MockRepo)
+	schemaRepo.On("Get", "test_schema-v1.0.0").
+		Return(&model.SingleSchema{}, nil)
+	draftRepo := &fakeDraftRepo{drafts: map[string]*model.Draft{}}
+	svc := service.NewDraftService(draftRepo, schemaRepo, fieldRepo)
+
+	_, err := svc.Propose([]byte(draftSchema))
+	require.ErrorAs(t, err, &library.ConflictError{})
+}
+
+func TestDraftService_Review(t *testing.T) {
+	svc, draftRepo, _ := newDraftService(t)
+	_, err := svc.Propose([]byte(draftSchema))
+	require.NoError(t, err)
+
+	draft, err := svc.Approve("test_schema-v1.0.0")
+	require.NoError(t, err)
+	require.Equal(t, model.DraftStatus.Published, draft.Status)
+	require.NotZero(t, draft.ReviewedAt)
+	require.Equal(t, []string{"test_schema-v1.0.0"}, draftRepo.published)
+
+	// A draft is only reviewed once.
+	_, err = svc.Reject("test_schema-v1.0.0", "Too late.")
+	require.ErrorAs(t, err, &library.ConflictError{})
+
+	_, err = svc.Approve("missing-v1.0.0")
+	require.ErrorAs(t, err, &library.SchemaNotFoundError{})
+}
+
+func TestDraftService_Reject(t *testing.T) {
+	svc, draftRepo, _ := newDraftService(t)
+	_, err := svc.Propose([]byte(draftSchema))
+	require.NoError(t, err)
+
+	draft, err := svc.Reject("test_schema-v1.0.0", "Use the tags field.")
+	require.NoError(t, err)
+	require.Equal(t, model.DraftStatus.Rejected, draft.Status)
+	require.Equal(t, "Use the tags field.", draft.Reason)
+	require.Empty(t, draftRepo.published)
+}
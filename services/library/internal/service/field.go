@@ -0,0 +1,35 @@
+package service
+
+import (
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/repository/mongo"
+)
+
+// FieldService defines methods for operations on Fields.
+type FieldService interface {
+	Get(fieldName string) (*model.SingleField, error)
+	Search(q *model.FieldQuery) (*model.FieldSearchResult, error)
+}
+
+type fieldService struct {
+	mongoRepo mongo.FieldRepo
+}
+
+// NewFieldService creates a new FieldService with the given FieldRepo.
+func NewFieldService(mongoRepo mongo.FieldRepo) FieldService {
+	return &fieldService{
+		mongoRepo: mongoRepo,
+	}
+}
+
+// Get fetches a Field with the given name.
+func (s *fieldService) Get(fieldName string) (*model.SingleField, error) {
+	return s.mongoRepo.Get(fieldName)
+}
+
+// Search retrieves a page of the Fields matching the query.
+func (s *fieldService) Search(
+	q *model.FieldQuery,
+) (*model.FieldSearchResult, error) {
+	return s.mongoRepo.Search(q)
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/repository/mongo"
+)
+
+// RunService defines methods for operations on the runs of the Schema
+// Parser.
+type RunService interface {
+	Search(q *model.RunQuery) (*model.RunSearchResult, error)
+	GetStatus() (*model.UpdateStatus, error)
+}
+
+type runService struct {
+	runRepo    mongo.RunRepo
+	schemaRepo mongo.SchemaRepo
+}
+
+// NewRunService creates a new RunService with the given repositories.
+func NewRunService(
+	runRepo mongo.RunRepo,
+	schemaRepo mongo.SchemaRepo,
+) RunService {
+	return &runService{
+		runRepo:    runRepo,
+		schemaRepo: schemaRepo,
+	}
+}
+
+// Search retrieves a page of the runs matching the query, from the newest to
+// the oldest.
+func (s *runService) Search(
+	q *model.RunQuery,
+) (*model.RunSearchResult, error) {
+	return s.runRepo.Search(q)
+}
+
+// GetStatus retrieves the active generation of the library with the latest
+// runs, so a failed run shows until a later one succeeds.
+func (s *runService) GetStatus() (*model.UpdateStatus, error) {
+	meta, err := s.schemaRepo.GetMeta()
+	if err != nil {
+		return nil, err
+	}
+	lastRun, err := s.runRepo.GetLast("")
+	if err != nil {
+		return nil, err
+	}
+	lastSuccessfulRun, err := s.runRepo.GetLast(model.RunStatus.Succeeded)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.UpdateStatus{
+		Active:            meta.Active,
+		LastRun:           lastRun,
+		LastSuccessfulRun: lastSuccessfulRun,
+	}, nil
+}
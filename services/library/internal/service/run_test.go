@@ -0,0 +1,54 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/librarygen"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/service"
+)
+
+type MockRunRepo struct {
+	mock.Mock
+}
+
+func (m *MockRunRepo) Search(
+	q *model.RunQuery,
+) (*model.RunSearchResult, error) {
+	args := m.Called(q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.RunSearchResult), args.Error(1)
+}
+
+func (m *MockRunRepo) GetLast(status string) (*model.Run, error) {
+	args := m.Called(status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Run), args.Error(1)
+}
+
+func TestRunService_GetStatus(t *testing.T) {
+	failed := &model.Run{Status: model.RunStatus.Failed, Commit: "c3"}
+	succeeded := &model.Run{Status: model.RunStatus.Succeeded, Commit: "b2"}
+	runRepo := new(MockRunRepo)
+	runRepo.On("GetLast", "").Return(failed, nil)
+	runRepo.On("GetLast", model.RunStatus.Succeeded).Return(succeeded, nil)
+	schemaRepo := new(MockRepo)
+	schemaRepo.On("GetMeta").Return(&librarygen.Meta{
+		Active: librarygen.Generation{Number: 2, Commit: "b2"},
+	}, nil)
+
+	status, err := service.NewRunService(runRepo, schemaRepo).GetStatus()
+	require.NoError(t, err)
+	require.Equal(t, &model.UpdateStatus{
+		Active:            librarygen.Generation{Number: 2, Commit: "b2"},
+		LastRun:           failed,
+		LastSuccessfulRun: succeeded,
+	}, status)
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/librarygen"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/repository/mongo"
 )
@@ -8,7 +9,10 @@ import (
 // SchemaService defines mtehods for operations on Schemas.
 type SchemaService interface {
 	Get(schemaName string) (interface{}, error)
-	Search() (*model.Schemas, error)
+	GetMeta() (*librarygen.Meta, error)
+	Search(q *model.SchemaQuery) (*model.SchemaSearchResult, error)
+	GetVersions(family string) (model.Schemas, error)
+	GetLatest(family string) (*model.Schema, error)
 }
 
 type schemaService struct {
@@ -31,11 +35,33 @@ func (s *schemaService) Get(schemaName string) (interface{}, error) {
 	return result, nil
 }
 
-// Search retrieves all Schemas.
-func (s *schemaService) Search() (*model.Schemas, error) {
-	result, err := s.mongoRepo.Search()
+// GetMeta fetches the active and the previous generations of the library.
+func (s *schemaService) GetMeta() (*librarygen.Meta, error) {
+	return s.mongoRepo.GetMeta()
+}
+
+// Search retrieves a page of the Schemas matching the query.
+func (s *schemaService) Search(
+	q *model.SchemaQuery,
+) (*model.SchemaSearchResult, error) {
+	result, err := s.mongoRepo.Search(q)
 	if err != nil {
 		return nil, err
 	}
 	return result, nil
 }
+
+// GetVersions retrieves the versions of a family of Schemas, from the oldest
+// to the newest.
+func (s *schemaService) GetVersions(family string) (model.Schemas, error) {
+	return s.mongoRepo.GetVersions(family)
+}
+
+// GetLatest retrieves the newest version of a family of Schemas.
+func (s *schemaService) GetLatest(family string) (*model.Schema, error) {
+	versions, err := s.mongoRepo.GetVersions(family)
+	if err != nil {
+		return nil, err
+	}
+	return versions[len(versions)-1], nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/librarygen"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/service"
 )
@@ -20,12 +21,49 @@ func (m *MockRepo) Get(schemaName string) (interface{}, error) {
 	return args.Get(0), args.Error(1)
 }
 
-func (m *MockRepo) Search() (*model.Schemas, error) {
+func (m *MockRepo) GetMeta() (*librarygen.Meta, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*model.Schemas), args.Error(1)
+	return args.Get(0).(*librarygen.Meta), args.Error(1)
+}
+
+func (m *MockRepo) Search(
+	q *model.SchemaQuery,
+) (*model.SchemaSearchResult, error) {
+	args := m.Called(q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SchemaSearchResult), args.Error(1)
+}
+
+func (m *MockRepo) GetVersions(family string) (model.Schemas, error) {
+	args := m.Called(family)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(model.Schemas), args.Error(1)
+}
+
+func TestSchemaService_GetLatest(t *testing.T) {
+	mockRepo := new(MockRepo)
+	mockRepo.On("GetVersions", "test_schema").Return(model.Schemas{
+		{Name: "test_schema-v1.0.0"},
+		{Name: "test_schema-v1.1.0"},
+	}, nil)
+	mockRepo.On("GetVersions", "missing").
+		Return(nil, errors.New("schema not found"))
+	s := service.NewSchemaService(mockRepo)
+
+	latest, err := s.GetLatest("test_schema")
+	assert.NoError(t, err)
+	assert.Equal(t, "test_schema-v1.1.0", latest.Name)
+
+	_, err = s.GetLatest("missing")
+	assert.Error(t, err)
+	mockRepo.AssertExpectations(t)
 }
 
 func TestSchemaService(t *testing.T) {
@@ -35,7 +73,8 @@ func TestSchemaService(t *testing.T) {
 		Name:        "TestSchema",
 		URL:         "URL",
 	}
-	mockSchemas := &model.Schemas{
+	query := &model.SchemaQuery{Page: 1, PageSize: 100}
+	mockSchemas := &model.SchemaSearchResult{Schemas: model.Schemas{
 		&model.Schema{
 			Title:       "Title1",
 			Description: "Description1",
@@ -48,7 +87,7 @@ func TestSchemaService(t *testing.T) {
 			Name:        "TestSchema2",
 			URL:         "URL2",
 		},
-	}
+	}, NumberOfResults: 2, TotalPages: 1}
 
 	tests := []struct {
 		name            string
@@ -58,7 +97,7 @@ func TestSchemaService(t *testing.T) {
 		expGetErr       bool
 		expSearchErr    bool
 		expGetResult    interface{}
-		expSearchResult *model.Schemas
+		expSearchResult *model.SchemaSearchResult
 	}{
 		{
 			name: "Test valid Get and Search",
@@ -66,7 +105,7 @@ func TestSchemaService(t *testing.T) {
 				m.On("Get", "TestSchema").Return(mockSchema, nil)
 			},
 			repoSearch: func(m *MockRepo) {
-				m.On("Search").Return(mockSchemas, nil)
+				m.On("Search", query).Return(mockSchemas, nil)
 			},
 			getSchemaName:   "TestSchema",
 			expGetErr:       false,
@@ -81,7 +120,7 @@ func TestSchemaService(t *testing.T) {
 					Return(nil, errors.New("schema not found"))
 			},
 			repoSearch: func(m *MockRepo) {
-				m.On("Search").Return(nil, errors.New("database error"))
+				m.On("Search", query).Return(nil, errors.New("database error"))
 			},
 			getSchemaName:   "NonExistent",
 			expGetErr:       true,
@@ -106,7 +145,7 @@ func TestSchemaService(t *testing.T) {
 				assert.Equal(t, tt.expGetResult, resultGet)
 			}
 
-			resultSearch, err := s.Search()
+			resultSearch, err := s.Search(query)
 			if tt.expSearchErr {
 				assert.Error(t, err)
 			} else {
@@ -0,0 +1,42 @@
+package library
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegistryTokenMiddleware only lets through the requests with one of the
+// tokens as their bearer token. The empty tokens are ignored, so all the
+// requests are refused when no token is set.
+func RegistryTokenMiddleware(tokens ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		given := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		for _, token := range tokens {
+			if token != "" &&
+				subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1 {
+				c.Next()
+				return
+			}
+		}
+		c.AbortWithStatusJSON(
+			http.StatusForbidden,
+			gin.H{"error": "Invalid registry token"},
+		)
+	}
+}
+
+// SamplingMiddleware only applies the middleware to the compatibility checks
+// which sample the profiles of the index.
+func SamplingMiddleware(middleware gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sample := c.Query("sample")
+		if sample == "" || sample == "0" {
+			c.Next()
+			return
+		}
+		middleware(c)
+	}
+}
@@ -17,10 +17,12 @@ import (
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/handler"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/middleware/limiter"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/middleware/localizer"
 	midlogger "github.com/MurmurationsNetwork/MurmurationsServices/pkg/middleware/logger"
 	mongodb "github.com/MurmurationsNetwork/MurmurationsServices/pkg/mongo"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/config"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/controller/rest"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/repository/index"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/repository/mongo"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/service"
 )
@@ -107,9 +109,10 @@ func (s *Service) middlewares() []gin.HandlerFunc {
 			Method: "GET",
 		}),
 		midlogger.NewLogger(),
+		localizer.NewLocalizer(),
 		// CORS for all origins, allowing:
 		// - GET and POST methods
-		// - Origin, Authorization and Content-Type header
+		// - Origin, Authorization, Content-Type and Accept-Language header
 		// - Credentials share
 		// - Preflight requests cached for 12 hours
 		cors.New(cors.Config{
@@ -118,6 +121,7 @@ func (s *Service) middlewares() []gin.HandlerFunc {
 				"Origin",
 				"Authorization",
 				"Content-Type",
+				"Accept-Language",
 			},
 			ExposeHeaders:    []string{"Content-Length"},
 			AllowCredentials: true,
@@ -131,6 +135,30 @@ func (s *Service) registerRoutes() {
 	schemaHandler := rest.NewSchemaHandler(
 		service.NewSchemaService(mongo.NewSchemaRepo()),
 	)
+	fieldHandler := rest.NewFieldHandler(
+		service.NewFieldService(mongo.NewFieldRepo()),
+	)
+	// The profiles can only be sampled with the URL of the index.
+	var profileRepo index.ProfileRepo
+	if config.Values.Index.URL != "" {
+		profileRepo = index.NewProfileRepo(
+			config.Values.Index.URL,
+			config.Values.Index.SampleTimeout,
+		)
+	}
+	compatibilityHandler := rest.NewCompatibilityHandler(
+		service.NewCompatibilityService(mongo.NewSchemaRepo(), profileRepo),
+	)
+	draftHandler := rest.NewDraftHandler(
+		service.NewDraftService(
+			mongo.NewDraftRepo(),
+			mongo.NewSchemaRepo(),
+			mongo.NewFieldRepo(),
+		),
+	)
+	runHandler := rest.NewRunHandler(
+		service.NewRunService(mongo.NewRunRepo(), mongo.NewSchemaRepo()),
+	)
 	countryHandler := rest.NewCountryHandler()
 
 	v1 := s.router.Group("/v1")
@@ -139,8 +167,38 @@ func (s *Service) registerRoutes() {
 	v2 := s.router.Group("/v2")
 	v2.GET("/ping", handler.PingHandler)
 	v2.GET("/schemas", schemaHandler.Search)
+	// The active generation of the library and the runs of the Schema
+	// Parser which updated it.
+	v2.GET("/schemas/_meta", schemaHandler.GetMeta)
+	v2.GET("/schemas/_status", runHandler.GetStatus)
+	v2.GET("/schemas/_history", runHandler.Search)
 	v2.GET("/schemas/:schemaName", schemaHandler.Get)
+	// The versions of a family, such as organizations_schema.
+	v2.GET("/schemas/:schemaName/versions", schemaHandler.GetVersions)
+	v2.GET("/schemas/:schemaName/latest", schemaHandler.GetLatest)
+	v2.GET("/fields", fieldHandler.Search)
+	v2.GET("/fields/:fieldName", fieldHandler.Get)
 	v2.GET("/countries", countryHandler.GetMap)
+
+	// The reviewers can also propose schemas.
+	author := RegistryTokenMiddleware(
+		config.Values.Registry.AuthorToken,
+		config.Values.Registry.ReviewerToken,
+	)
+	// Sampling fetches the profiles from their hosts, so only the registry
+	// users can ask for it.
+	v2.GET(
+		"/compatibility",
+		SamplingMiddleware(author),
+		compatibilityHandler.Check,
+	)
+	reviewer := RegistryTokenMiddleware(config.Values.Registry.ReviewerToken)
+	drafts := v2.Group("/drafts")
+	drafts.POST("", author, draftHandler.Propose)
+	drafts.GET("", author, draftHandler.GetAll)
+	drafts.GET("/:schemaName", author, draftHandler.Get)
+	drafts.POST("/:schemaName/approve", reviewer, draftHandler.Approve)
+	drafts.POST("/:schemaName/reject", reviewer, draftHandler.Reject)
 }
 
 // panic performs a cleanup and then emits the supplied message as the panic value.
@@ -9,9 +9,10 @@ import (
 var Values = config{}
 
 type config struct {
-	Mongo mongoConf
-	ES    esConf
-	TTL   ttlConf
+	Mongo  mongoConf
+	ES     esConf
+	Search searchConf
+	TTL    ttlConf
 }
 
 type mongoConf struct {
@@ -25,6 +26,10 @@ type esConf struct {
 	URL string `env:"ELASTICSEARCH_URL,required"`
 }
 
+type searchConf struct {
+	Backend string `env:"SEARCH_BACKEND,required"`
+}
+
 type ttlConf struct {
 	ValidationFailedTTL int64 `env:"VALIDATION_FAILED_TTL,required"`
 	DeletedTTL          int64 `env:"DELETED_TTL,required"`
@@ -7,28 +7,15 @@ import (
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/elastic"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/nodecleaner/internal/model/query"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/nodecleaner/internal/repository/search"
 )
 
-// NodeRepository defines the interface for operations that can be performed on
-// nodes in an Elasticsearch repository.
-type NodeRepository interface {
-	// Remove deletes nodes with the specified status and creation time earlier
-	// than the given timeBefore.
-	Remove(ctx context.Context, status string, timeBefore int64) error
-	// UpdateStatusByExpiration updates the status of nodes with expired status before the given time.
-	UpdateStatusByExpiration(
-		ctx context.Context,
-		status string,
-		timeBefore int64,
-	) error
-}
-
 type nodeRepository struct {
 }
 
-// NewNodeRepository initializes and returns a new NodeRepository instance for
-// interacting with Elasticsearch.
-func NewNodeRepository() NodeRepository {
+// NewNodeRepository initializes and returns a new search.NodeRepository
+// instance for interacting with Elasticsearch.
+func NewNodeRepository() search.NodeRepository {
 	return &nodeRepository{}
 }
 
@@ -0,0 +1,83 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/nodecleaner/config"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/nodecleaner/internal/repository/search"
+)
+
+type searchRepository struct {
+	client *mongo.Client
+}
+
+// NewSearchRepository initializes and returns a new search.NodeRepository for
+// the search index stored in MongoDB by the index service.
+func NewSearchRepository(client *mongo.Client) search.NodeRepository {
+	return &searchRepository{client: client}
+}
+
+func (r *searchRepository) coll() *mongo.Collection {
+	return r.client.Database(config.Values.Mongo.DBName).
+		Collection(constant.MongoIndex.SearchNode)
+}
+
+// Remove deletes the nodes from the search index that have the specified
+// status and were updated before the given time.
+func (r *searchRepository) Remove(
+	ctx context.Context,
+	status string,
+	timeBefore int64,
+) error {
+	filter := bson.M{
+		StatusField:      status,
+		LastUpdatedField: bson.M{"$lte": timeBefore},
+	}
+
+	_, err := r.coll().DeleteMany(ctx, filter)
+	if err != nil {
+		return fmt.Errorf(
+			"error removing nodes with status %s and timeBefore %d from the search index: %v",
+			status,
+			timeBefore,
+			err,
+		)
+	}
+
+	return nil
+}
+
+// UpdateStatusByExpiration updates the status of nodes with expired status before the given time.
+func (r *searchRepository) UpdateStatusByExpiration(
+	ctx context.Context,
+	status string,
+	timeBefore int64,
+) error {
+	filter := bson.M{
+		StatusField:  status,
+		ExpiresField: bson.M{"$lte": timeBefore},
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			StatusField: constant.NodeStatus.Deleted,
+		},
+	}
+
+	_, err := r.coll().UpdateMany(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf(
+			"error updating nodes status with status %s and expiresBefore %d in the search index: %v",
+			status,
+			timeBefore,
+			err,
+		)
+	}
+
+	return nil
+}
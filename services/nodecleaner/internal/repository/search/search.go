@@ -0,0 +1,21 @@
+// Package search defines the operations on the search index of the nodes
+// independently of the backend storing it.
+package search
+
+import (
+	"context"
+)
+
+// NodeRepository defines the interface for operations that can be performed on
+// nodes in the search index.
+type NodeRepository interface {
+	// Remove deletes nodes with the specified status and creation time earlier
+	// than the given timeBefore.
+	Remove(ctx context.Context, status string, timeBefore int64) error
+	// UpdateStatusByExpiration updates the status of nodes with expired status before the given time.
+	UpdateStatusByExpiration(
+		ctx context.Context,
+		status string,
+		timeBefore int64,
+	) error
+}
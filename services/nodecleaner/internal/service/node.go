@@ -8,8 +8,8 @@ import (
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/dateutil"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/nodecleaner/config"
-	"github.com/MurmurationsNetwork/MurmurationsServices/services/nodecleaner/internal/repository/es"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/nodecleaner/internal/repository/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/nodecleaner/internal/repository/search"
 )
 
 // NodesService is an interface that defines methods to remove nodes with specific statuses.
@@ -23,19 +23,19 @@ type NodesService interface {
 }
 
 type nodesService struct {
-	mongoRepo mongo.NodeRepository
-	esRepo    es.NodeRepository
+	mongoRepo  mongo.NodeRepository
+	searchRepo search.NodeRepository
 }
 
 // NewNodeService initializes and returns a new NodesService with the provided
 // NodeRepository instances.
 func NewNodeService(
 	mongoRepo mongo.NodeRepository,
-	esRepo es.NodeRepository,
+	searchRepo search.NodeRepository,
 ) NodesService {
 	return &nodesService{
-		mongoRepo: mongoRepo,
-		esRepo:    esRepo,
+		mongoRepo:  mongoRepo,
+		searchRepo: searchRepo,
 	}
 }
 
@@ -67,15 +67,15 @@ func (svc *nodesService) RemoveDeleted(ctx context.Context) error {
 		return fmt.Errorf("error removing nodes from MongoDB: %v", err)
 	}
 
-	err = svc.esRepo.Remove(ctx, constant.NodeStatus.Deleted, timeBefore)
+	err = svc.searchRepo.Remove(ctx, constant.NodeStatus.Deleted, timeBefore)
 	if err != nil {
-		return fmt.Errorf("error removing nodes from Elasticsearch: %v", err)
+		return fmt.Errorf("error removing nodes from the search index: %v", err)
 	}
 
 	return nil
 }
 
-// SetExpiredToDeleted sets nodes with expired status to deleted in both MongoDB and Elasticsearch.
+// SetExpiredToDeleted sets nodes with expired status to deleted in both MongoDB and the search index.
 func (svc *nodesService) SetExpiredToDeleted(ctx context.Context) error {
 	timeBefore := dateutil.GetNowUnix()
 
@@ -89,15 +89,15 @@ func (svc *nodesService) SetExpiredToDeleted(ctx context.Context) error {
 		return fmt.Errorf("error updating nodes status in MongoDB: %v", err)
 	}
 
-	// Update nodes in Elasticsearch
-	err = svc.esRepo.UpdateStatusByExpiration(
+	// Update nodes in the search index
+	err = svc.searchRepo.UpdateStatusByExpiration(
 		ctx,
 		constant.NodeStatus.Posted,
 		timeBefore,
 	)
 	if err != nil {
 		return fmt.Errorf(
-			"error updating nodes status in Elasticsearch: %v",
+			"error updating nodes status in the search index: %v",
 			err,
 		)
 	}
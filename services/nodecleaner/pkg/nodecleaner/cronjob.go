@@ -2,15 +2,18 @@ package nodecleaner
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"sync"
 
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/elastic"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
 	mongodb "github.com/MurmurationsNetwork/MurmurationsServices/pkg/mongo"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/nodecleaner/config"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/nodecleaner/internal/repository/es"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/nodecleaner/internal/repository/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/nodecleaner/internal/repository/search"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/nodecleaner/internal/service"
 )
 
@@ -38,8 +41,18 @@ func NewCronJob() *NodeCleaner {
 		os.Exit(1)
 	}
 
-	if err := elastic.NewClient(config.Values.ES.URL); err != nil {
-		logger.Error("Failed to connect to Elasticsearch", err)
+	switch config.Values.Search.Backend {
+	case constant.SearchBackend.Elasticsearch:
+		if err := elastic.NewClient(config.Values.ES.URL); err != nil {
+			logger.Error("Failed to connect to Elasticsearch", err)
+			os.Exit(1)
+		}
+	case constant.SearchBackend.Mongo:
+	default:
+		logger.Error(
+			"Unknown search backend",
+			fmt.Errorf("unknown search backend %q", config.Values.Search.Backend),
+		)
 		os.Exit(1)
 	}
 
@@ -50,7 +63,7 @@ func NewCronJob() *NodeCleaner {
 func (nc *NodeCleaner) Run(ctx context.Context) error {
 	svc := service.NewNodeService(
 		mongo.NewNodeRepository(mongodb.Client.GetClient()),
-		es.NewNodeRepository(),
+		nc.newSearchRepository(),
 	)
 
 	if err := svc.RemoveValidationFailed(ctx); err != nil {
@@ -70,6 +83,15 @@ func (nc *NodeCleaner) Run(ctx context.Context) error {
 	return nil
 }
 
+// newSearchRepository returns the repository of the configured search
+// backend.
+func (nc *NodeCleaner) newSearchRepository() search.NodeRepository {
+	if config.Values.Search.Backend == constant.SearchBackend.Mongo {
+		return mongo.NewSearchRepository(mongodb.Client.GetClient())
+	}
+	return es.NewNodeRepository()
+}
+
 // cleanup releases resources associated with the NodeCleaner.
 func (nc *NodeCleaner) cleanup() {
 	nc.runCleanup.Do(func() {
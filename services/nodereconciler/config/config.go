@@ -0,0 +1,48 @@
+package config
+
+import (
+	"log"
+
+	env "github.com/caarlos0/env/v10"
+)
+
+// Values holds the configuration settings for the application.
+var Values = config{}
+
+type config struct {
+	// Mongo holds the configuration for MongoDB.
+	Mongo mongoConf
+	// ES holds the configuration for Elasticsearch.
+	ES esConf
+	// Reconcile holds the configuration of the reconciliation run.
+	Reconcile reconcileConf
+}
+
+type mongoConf struct {
+	// USERNAME is the user name used to authenticate with MongoDB.
+	USERNAME string `env:"MONGO_USERNAME,required"`
+	// PASSWORD is the password used to authenticate with MongoDB.
+	PASSWORD string `env:"MONGO_PASSWORD,required"`
+	// HOST is the host address for MongoDB.
+	HOST string `env:"MONGO_HOST,required"`
+	// DBName is the name of the MongoDB database to connect to.
+	DBName string `env:"MONGO_DB_NAME,required"`
+}
+
+type esConf struct {
+	// URL is the URL used to connect to Elasticsearch.
+	URL string `env:"ELASTICSEARCH_URL,required"`
+}
+
+type reconcileConf struct {
+	// DryRun reports the drift between MongoDB and Elasticsearch without
+	// repairing it.
+	DryRun bool `env:"DRY_RUN"`
+}
+
+// Init initializes the Values variable by parsing environment variables.
+func Init() {
+	if err := env.Parse(&Values); err != nil {
+		log.Fatalf("Failed to decode environment variables: %s", err)
+	}
+}
@@ -0,0 +1,21 @@
+package model
+
+// Node is the MongoDB record of a node, the source of truth for its status.
+type Node struct {
+	// ID is the unique identifier of the node.
+	ID string `bson:"_id,omitempty"`
+	// ProfileURL is the URL of the node's profile.
+	ProfileURL string `bson:"profile_url,omitempty"`
+	// Status is the current status of the node.
+	Status string `bson:"status,omitempty"`
+	// LastUpdated is the Unix timestamp of the last update made to the node.
+	LastUpdated *int64 `bson:"last_updated,omitempty"`
+}
+
+// Document is the Elasticsearch document of a node.
+type Document struct {
+	// ID is the unique identifier of the node.
+	ID string
+	// Status is the status of the node in the index.
+	Status string
+}
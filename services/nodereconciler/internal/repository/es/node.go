@@ -0,0 +1,167 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/elastic"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/nodereconciler/internal/model"
+)
+
+// NodeRepository defines the operations the reconciler needs on the node
+// documents stored in Elasticsearch.
+type NodeRepository interface {
+	// ForEachBatch passes every document to handle, in batches of batchSize.
+	ForEachBatch(
+		ctx context.Context,
+		batchSize int,
+		handle func(docs []*model.Document) error,
+	) error
+	// FindByIDs returns the documents with the given IDs, keyed by ID.
+	FindByIDs(
+		ctx context.Context,
+		ids []string,
+	) (map[string]*model.Document, error)
+	// DeleteByIDs removes the documents with the given IDs.
+	DeleteByIDs(ctx context.Context, ids []string) error
+	// SoftDelete sets the status of an existing document to deleted.
+	SoftDelete(ctx context.Context, node *model.Node) error
+	// IndexDeleted indexes a deleted node which has no document, so
+	// aggregators still learn about the deletion.
+	IndexDeleted(ctx context.Context, node *model.Node) error
+}
+
+type nodeRepository struct{}
+
+// NewNodeRepository initializes and returns a new NodeRepository instance for
+// interacting with Elasticsearch.
+func NewNodeRepository() NodeRepository {
+	return &nodeRepository{}
+}
+
+// ForEachBatch passes every document to handle, in batches of batchSize.
+func (r *nodeRepository) ForEachBatch(
+	_ context.Context,
+	batchSize int,
+	handle func(docs []*model.Document) error,
+) error {
+	return elastic.Client.Scroll(
+		constant.ESIndex.Node,
+		[]string{"status"},
+		batchSize,
+		func(hits []elastic.Hit) error {
+			docs := make([]*model.Document, 0, len(hits))
+			for _, hit := range hits {
+				doc, err := toDocument(hit.ID, hit.Source)
+				if err != nil {
+					return err
+				}
+				docs = append(docs, doc)
+			}
+			return handle(docs)
+		},
+	)
+}
+
+// FindByIDs returns the documents with the given IDs, keyed by ID.
+func (r *nodeRepository) FindByIDs(
+	_ context.Context,
+	ids []string,
+) (map[string]*model.Document, error) {
+	sources, err := elastic.Client.GetSources(constant.ESIndex.Node, ids)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"error getting nodes from Elasticsearch: %v",
+			err,
+		)
+	}
+
+	docs := make(map[string]*model.Document, len(sources))
+	for id, source := range sources {
+		doc, err := toDocument(id, source)
+		if err != nil {
+			return nil, err
+		}
+		docs[id] = doc
+	}
+	return docs, nil
+}
+
+// DeleteByIDs removes the documents with the given IDs.
+func (r *nodeRepository) DeleteByIDs(_ context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	q := &elastic.Query{Query: elastic.NewIdsQuery(ids...)}
+	if err := elastic.Client.DeleteMany(constant.ESIndex.Node, q); err != nil {
+		return fmt.Errorf(
+			"error removing %d nodes from Elasticsearch: %v",
+			len(ids),
+			err,
+		)
+	}
+	return nil
+}
+
+// SoftDelete sets the status of an existing document to deleted.
+func (r *nodeRepository) SoftDelete(_ context.Context, node *model.Node) error {
+	err := elastic.Client.Update(
+		constant.ESIndex.Node,
+		node.ID,
+		map[string]interface{}{
+			"status":       constant.NodeStatus.Deleted,
+			"last_updated": node.LastUpdated,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"error setting node %s to deleted in Elasticsearch: %v",
+			node.ID,
+			err,
+		)
+	}
+	return nil
+}
+
+// IndexDeleted indexes a deleted node which has no document.
+func (r *nodeRepository) IndexDeleted(
+	_ context.Context,
+	node *model.Node,
+) error {
+	err := elastic.Client.IndexWithID(
+		constant.ESIndex.Node,
+		node.ID,
+		map[string]interface{}{
+			"profile_url":  node.ProfileURL,
+			"status":       constant.NodeStatus.Deleted,
+			"last_updated": node.LastUpdated,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"error indexing deleted node %s in Elasticsearch: %v",
+			node.ID,
+			err,
+		)
+	}
+	return nil
+}
+
+func toDocument(id string, source json.RawMessage) (*model.Document, error) {
+	var body struct {
+		Status string `json:"status"`
+	}
+	if len(source) > 0 {
+		if err := json.Unmarshal(source, &body); err != nil {
+			return nil, fmt.Errorf("error decoding node %s: %v", id, err)
+		}
+	}
+	// Documents indexed before the status field existed are posted nodes.
+	if body.Status == "" {
+		body.Status = constant.NodeStatus.Posted
+	}
+	return &model.Document{ID: id, Status: body.Status}, nil
+}
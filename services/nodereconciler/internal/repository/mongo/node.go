@@ -0,0 +1,141 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/nodereconciler/config"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/nodereconciler/internal/model"
+)
+
+// NodeRepository defines the operations the reconciler needs on the nodes
+// stored in MongoDB.
+type NodeRepository interface {
+	// ForEachBatch passes every node to handle, in batches of batchSize.
+	ForEachBatch(
+		ctx context.Context,
+		batchSize int,
+		handle func(nodes []*model.Node) error,
+	) error
+	// FindByIDs returns the nodes with the given IDs, keyed by ID.
+	FindByIDs(ctx context.Context, ids []string) (map[string]*model.Node, error)
+	// MarkPostFailed sets the nodes that are still posted to post_failed so
+	// revalidatenode sends them through validation and indexing again.
+	MarkPostFailed(ctx context.Context, ids []string) (int64, error)
+}
+
+type nodeRepository struct {
+	client *mongo.Client
+}
+
+// NewNodeRepository initializes and returns a new NodeRepository with the
+// provided MongoDB client.
+func NewNodeRepository(client *mongo.Client) NodeRepository {
+	return &nodeRepository{client: client}
+}
+
+func (r *nodeRepository) collection() *mongo.Collection {
+	return r.client.Database(config.Values.Mongo.DBName).
+		Collection(constant.MongoIndex.Node)
+}
+
+// ForEachBatch passes every node to handle, in batches of batchSize.
+func (r *nodeRepository) ForEachBatch(
+	ctx context.Context,
+	batchSize int,
+	handle func(nodes []*model.Node) error,
+) error {
+	opts := options.Find().
+		SetBatchSize(int32(batchSize)).
+		SetSort(bson.M{"_id": 1})
+
+	cur, err := r.collection().Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return fmt.Errorf("error finding nodes: %v", err)
+	}
+	defer cur.Close(ctx)
+
+	batch := make([]*model.Node, 0, batchSize)
+	for cur.Next(ctx) {
+		var node model.Node
+		if err := cur.Decode(&node); err != nil {
+			return fmt.Errorf("error decoding node: %v", err)
+		}
+		batch = append(batch, &node)
+		if len(batch) < batchSize {
+			continue
+		}
+		if err := handle(batch); err != nil {
+			return err
+		}
+		batch = make([]*model.Node, 0, batchSize)
+	}
+	if err := cur.Err(); err != nil {
+		return fmt.Errorf("error finding nodes: %v", err)
+	}
+
+	if len(batch) > 0 {
+		return handle(batch)
+	}
+	return nil
+}
+
+// FindByIDs returns the nodes with the given IDs, keyed by ID.
+func (r *nodeRepository) FindByIDs(
+	ctx context.Context,
+	ids []string,
+) (map[string]*model.Node, error) {
+	nodes := make(map[string]*model.Node, len(ids))
+	if len(ids) == 0 {
+		return nodes, nil
+	}
+
+	cur, err := r.collection().Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, fmt.Errorf("error finding nodes by ID: %v", err)
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var node model.Node
+		if err := cur.Decode(&node); err != nil {
+			return nil, fmt.Errorf("error decoding node: %v", err)
+		}
+		nodes[node.ID] = &node
+	}
+	if err := cur.Err(); err != nil {
+		return nil, fmt.Errorf("error finding nodes by ID: %v", err)
+	}
+
+	return nodes, nil
+}
+
+// MarkPostFailed sets the nodes that are still posted to post_failed.
+func (r *nodeRepository) MarkPostFailed(
+	ctx context.Context,
+	ids []string,
+) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	filter := bson.M{
+		"_id":    bson.M{"$in": ids},
+		"status": constant.NodeStatus.Posted,
+	}
+	update := bson.M{
+		"$set": bson.M{"status": constant.NodeStatus.PostFailed},
+	}
+
+	result, err := r.collection().UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, fmt.Errorf("error updating nodes status: %v", err)
+	}
+
+	return result.ModifiedCount, nil
+}
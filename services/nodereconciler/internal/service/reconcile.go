@@ -0,0 +1,303 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/nodereconciler/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/nodereconciler/internal/repository/es"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/nodereconciler/internal/repository/mongo"
+)
+
+// batchSize is the number of nodes read or repaired at once.
+const batchSize = 500
+
+// Drift is the kind of divergence between a node in MongoDB and its document
+// in Elasticsearch.
+type Drift string
+
+const (
+	// DriftNone means both stores agree.
+	DriftNone Drift = ""
+	// DriftMissing is a posted or deleted node without a document.
+	DriftMissing Drift = "missing_in_es"
+	// DriftOrphaned is a document whose node was purged or failed validation.
+	DriftOrphaned Drift = "orphaned_in_es"
+	// DriftStatus is a document whose status differs from the node's.
+	DriftStatus Drift = "status_mismatch"
+)
+
+// Classify returns how the document of a node diverges from it. Either
+// argument may be nil when the node or the document does not exist.
+//
+// Nodes which are still going through validation (received, validated and
+// post_failed) are never considered drifted, their document is written once
+// they are posted.
+func Classify(node *model.Node, doc *model.Document) Drift {
+	switch {
+	case node == nil && doc == nil:
+		return DriftNone
+	case node == nil:
+		return DriftOrphaned
+	case node.Status == constant.NodeStatus.ValidationFailed:
+		if doc != nil {
+			return DriftOrphaned
+		}
+		return DriftNone
+	case node.Status != constant.NodeStatus.Posted &&
+		node.Status != constant.NodeStatus.Deleted:
+		return DriftNone
+	case doc == nil:
+		return DriftMissing
+	case doc.Status != node.Status:
+		return DriftStatus
+	}
+	return DriftNone
+}
+
+// Report summarizes a reconciliation run.
+type Report struct {
+	// DryRun is true when the drift was only reported.
+	DryRun bool
+	// MongoNodes is the number of nodes scanned in MongoDB.
+	MongoNodes int
+	// ESDocuments is the number of documents scanned in Elasticsearch.
+	ESDocuments int
+	// Missing is the number of posted or deleted nodes without a document.
+	Missing int
+	// Orphaned is the number of documents without a node to back them.
+	Orphaned int
+	// StatusMismatch is the number of documents with a stale status.
+	StatusMismatch int
+	// Repaired is the number of drifted nodes that were repaired.
+	Repaired int
+	// Failed is the number of drifted nodes that could not be repaired.
+	Failed int
+}
+
+// Fields returns the report as log fields, so the drift can be graphed and
+// alerted on from the logs.
+func (r *Report) Fields() []zap.Field {
+	return []zap.Field{
+		zap.Bool("dry_run", r.DryRun),
+		zap.Int("mongo_nodes", r.MongoNodes),
+		zap.Int("es_documents", r.ESDocuments),
+		zap.Int(string(DriftMissing), r.Missing),
+		zap.Int(string(DriftOrphaned), r.Orphaned),
+		zap.Int(string(DriftStatus), r.StatusMismatch),
+		zap.Int("repaired", r.Repaired),
+		zap.Int("failed", r.Failed),
+	}
+}
+
+// ReconcileService finds and repairs the drift between the nodes in MongoDB
+// and their documents in Elasticsearch.
+type ReconcileService interface {
+	Reconcile(ctx context.Context, dryRun bool) (*Report, error)
+}
+
+type reconcileService struct {
+	mongoRepo mongo.NodeRepository
+	esRepo    es.NodeRepository
+}
+
+// NewReconcileService initializes and returns a new ReconcileService with the
+// provided NodeRepository instances.
+func NewReconcileService(
+	mongoRepo mongo.NodeRepository,
+	esRepo es.NodeRepository,
+) ReconcileService {
+	return &reconcileService{
+		mongoRepo: mongoRepo,
+		esRepo:    esRepo,
+	}
+}
+
+// Reconcile scans both stores and repairs the drift found, unless dryRun is
+// set. MongoDB is the source of truth:
+//   - orphaned documents are deleted,
+//   - posted nodes without an up-to-date document are set to post_failed, so
+//     revalidatenode sends them through validation and indexing again,
+//   - deleted nodes get their document set to deleted.
+//
+// Since the index keeps changing during the scan, every drifted node is read
+// again from both stores before it is counted and repaired.
+func (s *reconcileService) Reconcile(
+	ctx context.Context,
+	dryRun bool,
+) (*Report, error) {
+	report := &Report{DryRun: dryRun}
+
+	candidates, err := s.scan(ctx, report)
+	if err != nil {
+		return nil, err
+	}
+
+	for start := 0; start < len(candidates); start += batchSize {
+		end := min(start+batchSize, len(candidates))
+		err := s.reconcileBatch(ctx, candidates[start:end], report)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+// scan reads both stores and returns the IDs of the nodes which look drifted.
+func (s *reconcileService) scan(
+	ctx context.Context,
+	report *Report,
+) ([]string, error) {
+	nodes := make(map[string]*model.Node)
+	err := s.mongoRepo.ForEachBatch(
+		ctx,
+		batchSize,
+		func(batch []*model.Node) error {
+			for _, node := range batch {
+				nodes[node.ID] = node
+			}
+			report.MongoNodes += len(batch)
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []string
+	seen := make(map[string]bool)
+	err = s.esRepo.ForEachBatch(
+		ctx,
+		batchSize,
+		func(docs []*model.Document) error {
+			for _, doc := range docs {
+				seen[doc.ID] = true
+				if Classify(nodes[doc.ID], doc) != DriftNone {
+					candidates = append(candidates, doc.ID)
+				}
+			}
+			report.ESDocuments += len(docs)
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for id, node := range nodes {
+		if !seen[id] && Classify(node, nil) != DriftNone {
+			candidates = append(candidates, id)
+		}
+	}
+
+	return candidates, nil
+}
+
+// reconcileBatch reads the candidates again, counts the ones which are still
+// drifted and repairs them.
+func (s *reconcileService) reconcileBatch(
+	ctx context.Context,
+	ids []string,
+	report *Report,
+) error {
+	nodes, err := s.mongoRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	docs, err := s.esRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	var orphaned, repost []string
+	var deleted []*model.Node
+	for _, id := range ids {
+		node, doc := nodes[id], docs[id]
+		drift := Classify(node, doc)
+		if drift == DriftNone {
+			continue
+		}
+		logDrift(id, drift, node, doc)
+
+		switch drift {
+		case DriftOrphaned:
+			report.Orphaned++
+			orphaned = append(orphaned, id)
+			continue
+		case DriftMissing:
+			report.Missing++
+		case DriftStatus:
+			report.StatusMismatch++
+		}
+
+		if node.Status == constant.NodeStatus.Posted {
+			repost = append(repost, id)
+		} else {
+			deleted = append(deleted, node)
+		}
+	}
+
+	if report.DryRun {
+		return nil
+	}
+
+	if err := s.esRepo.DeleteByIDs(ctx, orphaned); err != nil {
+		return err
+	}
+	report.Repaired += len(orphaned)
+
+	reposted, err := s.mongoRepo.MarkPostFailed(ctx, repost)
+	if err != nil {
+		return err
+	}
+	// Nodes the index updated in the meantime are left alone.
+	report.Repaired += int(reposted)
+
+	for _, node := range deleted {
+		if err := s.repairDeleted(ctx, node, docs[node.ID]); err != nil {
+			logger.Error(
+				fmt.Sprintf("Failed to repair deleted node %s", node.ID),
+				err,
+			)
+			report.Failed++
+			continue
+		}
+		report.Repaired++
+	}
+
+	return nil
+}
+
+// repairDeleted makes the document of a deleted node reflect the deletion.
+func (s *reconcileService) repairDeleted(
+	ctx context.Context,
+	node *model.Node,
+	doc *model.Document,
+) error {
+	if doc == nil {
+		return s.esRepo.IndexDeleted(ctx, node)
+	}
+	return s.esRepo.SoftDelete(ctx, node)
+}
+
+func logDrift(id string, drift Drift, node *model.Node, doc *model.Document) {
+	fields := []zap.Field{
+		zap.String("node_id", id),
+		zap.String("drift", string(drift)),
+	}
+	if node != nil {
+		fields = append(fields,
+			zap.String("profile_url", node.ProfileURL),
+			zap.String("mongo_status", node.Status),
+		)
+	}
+	if doc != nil {
+		fields = append(fields, zap.String("es_status", doc.Status))
+	}
+	logger.Info("Node drift detected", fields...)
+}
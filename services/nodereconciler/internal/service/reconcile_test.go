@@ -0,0 +1,275 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/nodereconciler/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/nodereconciler/internal/service"
+)
+
+type fakeMongoRepo struct {
+	nodes map[string]*model.Node
+	// afterScan is called once the nodes have been scanned.
+	afterScan func()
+}
+
+func (r *fakeMongoRepo) ForEachBatch(
+	_ context.Context,
+	_ int,
+	handle func(nodes []*model.Node) error,
+) error {
+	batch := make([]*model.Node, 0, len(r.nodes))
+	for _, node := range r.nodes {
+		copied := *node
+		batch = append(batch, &copied)
+	}
+	if err := handle(batch); err != nil {
+		return err
+	}
+	if r.afterScan != nil {
+		r.afterScan()
+	}
+	return nil
+}
+
+func (r *fakeMongoRepo) FindByIDs(
+	_ context.Context,
+	ids []string,
+) (map[string]*model.Node, error) {
+	found := make(map[string]*model.Node)
+	for _, id := range ids {
+		if node, ok := r.nodes[id]; ok {
+			copied := *node
+			found[id] = &copied
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeMongoRepo) MarkPostFailed(
+	_ context.Context,
+	ids []string,
+) (int64, error) {
+	var modified int64
+	for _, id := range ids {
+		node, ok := r.nodes[id]
+		if ok && node.Status == constant.NodeStatus.Posted {
+			node.Status = constant.NodeStatus.PostFailed
+			modified++
+		}
+	}
+	return modified, nil
+}
+
+type fakeESRepo struct {
+	docs map[string]*model.Document
+}
+
+func (r *fakeESRepo) ForEachBatch(
+	_ context.Context,
+	_ int,
+	handle func(docs []*model.Document) error,
+) error {
+	batch := make([]*model.Document, 0, len(r.docs))
+	for _, doc := range r.docs {
+		copied := *doc
+		batch = append(batch, &copied)
+	}
+	return handle(batch)
+}
+
+func (r *fakeESRepo) FindByIDs(
+	_ context.Context,
+	ids []string,
+) (map[string]*model.Document, error) {
+	found := make(map[string]*model.Document)
+	for _, id := range ids {
+		if doc, ok := r.docs[id]; ok {
+			copied := *doc
+			found[id] = &copied
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeESRepo) DeleteByIDs(_ context.Context, ids []string) error {
+	for _, id := range ids {
+		delete(r.docs, id)
+	}
+	return nil
+}
+
+func (r *fakeESRepo) SoftDelete(_ context.Context, node *model.Node) error {
+	r.docs[node.ID].Status = constant.NodeStatus.Deleted
+	return nil
+}
+
+func (r *fakeESRepo) IndexDeleted(_ context.Context, node *model.Node) error {
+	r.docs[node.ID] = &model.Document{
+		ID:     node.ID,
+		Status: constant.NodeStatus.Deleted,
+	}
+	return nil
+}
+
+func node(id string, status string) *model.Node {
+	return &model.Node{ID: id, ProfileURL: "https://" + id, Status: status}
+}
+
+func doc(id string, status string) *model.Document {
+	return &model.Document{ID: id, Status: status}
+}
+
+func newStores() (*fakeMongoRepo, *fakeESRepo) {
+	mongoRepo := &fakeMongoRepo{nodes: map[string]*model.Node{
+		"in-sync":          node("in-sync", constant.NodeStatus.Posted),
+		"missing-posted":   node("missing-posted", constant.NodeStatus.Posted),
+		"missing-deleted":  node("missing-deleted", constant.NodeStatus.Deleted),
+		"stale-posted":     node("stale-posted", constant.NodeStatus.Posted),
+		"stale-deleted":    node("stale-deleted", constant.NodeStatus.Deleted),
+		"failed":           node("failed", constant.NodeStatus.ValidationFailed),
+		"received-no-doc":  node("received-no-doc", constant.NodeStatus.Received),
+		"post-failed-docs": node("post-failed-docs", constant.NodeStatus.PostFailed),
+	}}
+	esRepo := &fakeESRepo{docs: map[string]*model.Document{
+		"in-sync":          doc("in-sync", constant.NodeStatus.Posted),
+		"stale-posted":     doc("stale-posted", constant.NodeStatus.Deleted),
+		"stale-deleted":    doc("stale-deleted", constant.NodeStatus.Posted),
+		"failed":           doc("failed", constant.NodeStatus.Posted),
+		"purged":           doc("purged", constant.NodeStatus.Deleted),
+		"post-failed-docs": doc("post-failed-docs", constant.NodeStatus.Posted),
+	}}
+	return mongoRepo, esRepo
+}
+
+func TestReconcile(t *testing.T) {
+	mongoRepo, esRepo := newStores()
+	svc := service.NewReconcileService(mongoRepo, esRepo)
+
+	report, err := svc.Reconcile(context.Background(), false)
+	require.NoError(t, err)
+	require.Equal(t, &service.Report{
+		MongoNodes:     8,
+		ESDocuments:    6,
+		Missing:        2,
+		Orphaned:       2,
+		StatusMismatch: 2,
+		Repaired:       6,
+	}, report)
+
+	// Posted nodes are sent through validation and indexing again.
+	require.Equal(
+		t,
+		constant.NodeStatus.PostFailed,
+		mongoRepo.nodes["missing-posted"].Status,
+	)
+	require.Equal(
+		t,
+		constant.NodeStatus.PostFailed,
+		mongoRepo.nodes["stale-posted"].Status,
+	)
+	// Deleted nodes have their documents set to deleted.
+	require.Equal(
+		t,
+		doc("missing-deleted", constant.NodeStatus.Deleted),
+		esRepo.docs["missing-deleted"],
+	)
+	require.Equal(
+		t,
+		constant.NodeStatus.Deleted,
+		esRepo.docs["stale-deleted"].Status,
+	)
+	// Orphaned documents are removed.
+	require.NotContains(t, esRepo.docs, "failed")
+	require.NotContains(t, esRepo.docs, "purged")
+
+	// Nothing is left to repair.
+	report, err = svc.Reconcile(context.Background(), false)
+	require.NoError(t, err)
+	require.Zero(t, report.Missing+report.Orphaned+report.StatusMismatch)
+}
+
+func TestReconcile_DryRun(t *testing.T) {
+	mongoRepo, esRepo := newStores()
+	svc := service.NewReconcileService(mongoRepo, esRepo)
+
+	report, err := svc.Reconcile(context.Background(), true)
+	require.NoError(t, err)
+	require.True(t, report.DryRun)
+	require.Equal(t, 2, report.Missing)
+	require.Equal(t, 2, report.Orphaned)
+	require.Equal(t, 2, report.StatusMismatch)
+	require.Zero(t, report.Repaired)
+
+	unchangedMongo, unchangedES := newStores()
+	require.Equal(t, unchangedMongo.nodes, mongoRepo.nodes)
+	require.Equal(t, unchangedES.docs, esRepo.docs)
+}
+
+func TestReconcile_ChangesDuringScan(t *testing.T) {
+	mongoRepo := &fakeMongoRepo{nodes: map[string]*model.Node{
+		"deleted": node("deleted", constant.NodeStatus.Posted),
+	}}
+	esRepo := &fakeESRepo{docs: map[string]*model.Document{
+		"deleted": doc("deleted", constant.NodeStatus.Posted),
+	}}
+	mongoRepo.afterScan = func() {
+		// A node is added and posted once MongoDB was scanned.
+		mongoRepo.nodes["added"] = node("added", constant.NodeStatus.Posted)
+		esRepo.docs["added"] = doc("added", constant.NodeStatus.Posted)
+		// A node is deleted, but its document isn't updated yet.
+		mongoRepo.nodes["deleted"].Status = constant.NodeStatus.Deleted
+	}
+	svc := service.NewReconcileService(mongoRepo, esRepo)
+
+	report, err := svc.Reconcile(context.Background(), false)
+	require.NoError(t, err)
+	require.Zero(t, report.Orphaned)
+	require.Contains(t, esRepo.docs, "added")
+	// Drift which appears after the scan is left for the next run.
+	require.Zero(t, report.StatusMismatch)
+}
+
+func TestClassify(t *testing.T) {
+	posted, deleted := constant.NodeStatus.Posted, constant.NodeStatus.Deleted
+	tests := []struct {
+		name string
+		node *model.Node
+		doc  *model.Document
+		want service.Drift
+	}{
+		{"in sync", node("a", posted), doc("a", posted), service.DriftNone},
+		{"neither", nil, nil, service.DriftNone},
+		{"purged", nil, doc("a", deleted), service.DriftOrphaned},
+		{
+			"validation failed",
+			node("a", constant.NodeStatus.ValidationFailed),
+			doc("a", posted),
+			service.DriftOrphaned,
+		},
+		{
+			"validation failed without document",
+			node("a", constant.NodeStatus.ValidationFailed),
+			nil,
+			service.DriftNone,
+		},
+		{
+			"being validated",
+			node("a", constant.NodeStatus.Validated),
+			nil,
+			service.DriftNone,
+		},
+		{"posted", node("a", posted), nil, service.DriftMissing},
+		{"deleted", node("a", deleted), nil, service.DriftMissing},
+		{"stale", node("a", deleted), doc("a", posted), service.DriftStatus},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, service.Classify(tt.node, tt.doc))
+		})
+	}
+}
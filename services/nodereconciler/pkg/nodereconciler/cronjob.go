@@ -0,0 +1,74 @@
+package nodereconciler
+
+import (
+	"context"
+	"os"
+	"sync"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/elastic"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+	mongodb "github.com/MurmurationsNetwork/MurmurationsServices/pkg/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/nodereconciler/config"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/nodereconciler/internal/repository/es"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/nodereconciler/internal/repository/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/nodereconciler/internal/service"
+)
+
+// NodeReconciler manages the reconciliation of nodes between MongoDB and
+// Elasticsearch.
+type NodeReconciler struct {
+	runCleanup sync.Once // Ensures cleanup is only run once.
+}
+
+// NewCronJob creates a new instance of NodeReconciler.
+func NewCronJob() *NodeReconciler {
+	config.Init()
+
+	uri := mongodb.GetURI(
+		config.Values.Mongo.USERNAME,
+		config.Values.Mongo.PASSWORD,
+		config.Values.Mongo.HOST,
+	)
+	if err := mongodb.NewClient(uri, config.Values.Mongo.DBName); err != nil {
+		logger.Error("Failed to connect to MongoDB", err)
+		os.Exit(1)
+	}
+
+	if err := mongodb.Client.Ping(); err != nil {
+		logger.Error("Failed to ping MongoDB", err)
+		os.Exit(1)
+	}
+
+	if err := elastic.NewClient(config.Values.ES.URL); err != nil {
+		logger.Error("Failed to connect to Elasticsearch", err)
+		os.Exit(1)
+	}
+
+	return &NodeReconciler{}
+}
+
+// Run executes the node reconciliation process. When dryRun is set, the drift
+// is only reported.
+func (nr *NodeReconciler) Run(ctx context.Context, dryRun bool) error {
+	defer nr.cleanup()
+
+	svc := service.NewReconcileService(
+		mongo.NewNodeRepository(mongodb.Client.GetClient()),
+		es.NewNodeRepository(),
+	)
+
+	report, err := svc.Reconcile(ctx, dryRun)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Node reconciliation report", report.Fields()...)
+	return nil
+}
+
+// cleanup releases resources associated with the NodeReconciler.
+func (nr *NodeReconciler) cleanup() {
+	nr.runCleanup.Do(func() {
+		mongodb.Client.Disconnect()
+	})
+}
@@ -65,6 +65,8 @@ func (svc *nodeService) RevalidateNodes() error {
 				messaging.NodeCreatedData{
 					ProfileURL: node.ProfileURL,
 					Version:    *node.Version,
+					// The nodes passed the validation before.
+					Existing: true,
 				},
 			)
 			if err != nil {
@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 var Values = config{}
 
 type config struct {
@@ -7,6 +9,8 @@ type config struct {
 	Mongo   mongoConf
 	Redis   redisConf
 	Github  githubConf
+	Source  sourceConf
+	Nats    natsConf
 	IsLocal bool `env:"IS_LOCAL,required"`
 }
 
@@ -25,8 +29,37 @@ type redisConf struct {
 	URL string `env:"REDIS_URL,required"`
 }
 
+// githubConf is only required by the github source.
 type githubConf struct {
-	TOKEN     string `env:"GITHUB_TOKEN,required"`
-	BranchURL string `env:"GITHUB_BRANCH_URL,required"`
-	TreeURL   string `env:"GITHUB_TREE_URL,required"`
+	TOKEN     string `env:"GITHUB_TOKEN"`
+	BranchURL string `env:"GITHUB_BRANCH_URL"`
+	TreeURL   string `env:"GITHUB_TREE_URL"`
+}
+
+type sourceConf struct {
+	// Type is where the schemas are hosted: github, git, dir, http or s3.
+	Type string `env:"SCHEMA_SOURCE" envDefault:"github"`
+	// URL is the repository of the git source, the manifest of the http
+	// source or the endpoint of the s3 source.
+	URL    string `env:"SCHEMA_SOURCE_URL"`
+	Branch string `env:"SCHEMA_SOURCE_BRANCH" envDefault:"main"`
+	// Dir is the folder of the dir source.
+	Dir string `env:"SCHEMA_SOURCE_DIR" envDefault:"library"`
+	// Watch keeps the schemaparser running to load the changes of the dir
+	// source, checked at each WatchInterval.
+	Watch         bool          `env:"SCHEMA_SOURCE_WATCH"`
+	WatchInterval time.Duration `env:"SCHEMA_SOURCE_WATCH_INTERVAL" envDefault:"5s"`
+	S3            s3Conf
+}
+
+type s3Conf struct {
+	Bucket    string `env:"S3_BUCKET"`
+	Prefix    string `env:"S3_PREFIX"`
+	Region    string `env:"S3_REGION" envDefault:"us-east-1"`
+	AccessKey string `env:"S3_ACCESS_KEY"`
+	SecretKey string `env:"S3_SECRET_KEY"`
+}
+
+type natsConf struct {
+	URL string `env:"NATS_URL,required"`
 }
@@ -0,0 +1,21 @@
+package model
+
+import "go.mongodb.org/mongo-driver/bson"
+
+// Field is a reusable field definition of the library, which the schemas
+// reference with `$ref`.
+type Field struct {
+	Name        string `bson:"name,omitempty"`
+	Title       string `bson:"title,omitempty"`
+	Description string `bson:"description,omitempty"`
+	FullField   bson.D `bson:"full_field,omitempty"`
+	// UsedBy holds the names of the schemas referencing the field. It is
+	// always written so the schemas which stopped using it are removed.
+	UsedBy []string `bson:"used_by"`
+}
+
+// FieldJSON is the part of a field definition read by the parser.
+type FieldJSON struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
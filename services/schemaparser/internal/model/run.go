@@ -0,0 +1,43 @@
+package model
+
+// RunAction lists the kinds of runs of the schemaparser.
+var RunAction = struct {
+	// Update loads the schemas of the source.
+	Update string
+	// Rollback makes the previous generation of the library active again.
+	Rollback string
+}{
+	Update:   "update",
+	Rollback: "rollback",
+}
+
+// RunStatus lists the outcomes of the runs of the schemaparser.
+var RunStatus = struct {
+	Succeeded string
+	Failed    string
+}{
+	Succeeded: "succeeded",
+	Failed:    "failed",
+}
+
+// Run is a run of the schemaparser which changed, or tried to change, the
+// library. The runs are kept as the update history of the library.
+type Run struct {
+	Action string `bson:"action"`
+	Status string `bson:"status"`
+	// Source, Revision and Commit describe where the schemas were loaded
+	// from, or the generation rolled back to.
+	Source   string `bson:"source,omitempty"`
+	Revision string `bson:"revision,omitempty"`
+	Commit   string `bson:"commit,omitempty"`
+	// Generation is the active generation of the library after the run.
+	Generation int64 `bson:"generation"`
+	// StartedAt and EndedAt are Unix timestamps.
+	StartedAt int64 `bson:"started_at"`
+	EndedAt   int64 `bson:"ended_at"`
+	// ChangedSchemas and ChangedFields hold the names of the schemas and
+	// fields the run added, changed or removed, or tried to when it failed.
+	ChangedSchemas []string `bson:"changed_schemas"`
+	ChangedFields  []string `bson:"changed_fields"`
+	Errors         []string `bson:"errors,omitempty"`
+}
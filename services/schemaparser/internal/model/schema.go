@@ -8,6 +8,11 @@ type Schema struct {
 	Name        string `bson:"name,omitempty"`
 	URL         string `bson:"url,omitempty"`
 	FullSchema  bson.D `bson:"full_schema,omitempty"`
+	// The lifecycle fields are always written so they are cleared when they
+	// are removed from the schema.
+	Deprecated bool   `bson:"deprecated"`
+	SunsetAt   int64  `bson:"sunset_at"`
+	ReplacedBy string `bson:"replaced_by"`
 }
 
 type BranchInfo struct {
@@ -35,4 +40,10 @@ type InnerSchema struct {
 	Name    string `json:"name"`
 	Version int    `json:"version"`
 	URL     string `json:"url"`
+	// Deprecated schemas can still be linked but warn the profiles using
+	// them. After SunsetAt, a Unix timestamp, new profiles can't be posted
+	// with the schema. ReplacedBy names the schema to use instead.
+	Deprecated bool   `json:"deprecated"`
+	SunsetAt   int64  `json:"sunset_at"`
+	ReplacedBy string `json:"replaced_by"`
 }
@@ -1,16 +1,62 @@
 package mongo
 
 import (
+	"context"
+	"errors"
+
 	"go.mongodb.org/mongo-driver/bson"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/librarygen"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/mongo"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/model"
 )
 
+// ErrGenerationChanged is returned when another run switched the active
+// generation of the library during an update.
+var ErrGenerationChanged = errors.New(
+	"the active generation of the library changed during the update",
+)
+
+// publishedStatus is the status of the drafts of the registry which were
+// approved and added to the library.
+const publishedStatus = "published"
+
+// generationCollections are the collections each generation of the library
+// has a copy of.
+var generationCollections = []string{
+	constant.MongoIndex.Schema,
+	constant.MongoIndex.Field,
+}
+
 type SchemaRepository interface {
-	Update(schema *model.Schema) error
+	// GetMeta returns the active and the previous generations of the
+	// library.
+	GetMeta() (*librarygen.Meta, error)
+	// CopyGeneration replaces the schemas and fields of a generation with
+	// the ones of another generation.
+	CopyGeneration(from, to int64) error
+	Update(generation int64, schema *model.Schema) error
+	UpdateField(generation int64, field *model.Field) error
+	// DeleteField removes a field from a generation.
+	DeleteField(generation int64, name string) error
+	// GetSchemas returns the schemas of a generation, by name.
+	GetSchemas(generation int64) (map[string]*model.Schema, error)
+	// GetFields returns the fields of a generation, by name.
+	GetFields(generation int64) (map[string]*model.Field, error)
+	// AddPublishedDrafts adds the schemas published through the registry to
+	// a generation, except the ones it already has.
+	AddPublishedDrafts(generation int64) error
+	// Activate makes the generation active and the active one the previous
+	// one, unless the meta is out of date, such as when a schema was
+	// published since it was read.
+	Activate(meta *librarygen.Meta, generation librarygen.Generation) error
+	// DropGeneration removes the collections of a generation.
+	DropGeneration(generation int64) error
+	// SaveRun adds a run to the update history.
+	SaveRun(run *model.Run) error
 }
 
 func NewSchemaRepository() SchemaRepository {
@@ -20,13 +66,41 @@ func NewSchemaRepository() SchemaRepository {
 type schemaRepository struct {
 }
 
-func (r *schemaRepository) Update(schema *model.Schema) error {
+func (r *schemaRepository) GetMeta() (*librarygen.Meta, error) {
+	return librarygen.GetMeta()
+}
+
+func (r *schemaRepository) Update(
+	generation int64,
+	schema *model.Schema,
+) error {
 	filter := bson.M{"name": schema.Name}
 	update := bson.M{"$set": schema}
 	opt := options.FindOneAndUpdate().SetUpsert(true)
 
 	_, err := mongo.Client.FindOneAndUpdate(
-		constant.MongoIndex.Schema,
+		librarygen.Collection(constant.MongoIndex.Schema, generation),
+		filter,
+		update,
+		opt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *schemaRepository) UpdateField(
+	generation int64,
+	field *model.Field,
+) error {
+	filter := bson.M{"name": field.Name}
+	update := bson.M{"$set": field}
+	opt := options.FindOneAndUpdate().SetUpsert(true)
+
+	_, err := mongo.Client.FindOneAndUpdate(
+		librarygen.Collection(constant.MongoIndex.Field, generation),
 		filter,
 		update,
 		opt,
@@ -37,3 +111,173 @@ func (r *schemaRepository) Update(schema *model.Schema) error {
 
 	return nil
 }
+
+func (r *schemaRepository) DeleteField(generation int64, name string) error {
+	return mongo.Client.DeleteOne(
+		librarygen.Collection(constant.MongoIndex.Field, generation),
+		bson.M{"name": name},
+	)
+}
+
+func (r *schemaRepository) CopyGeneration(from, to int64) error {
+	for _, base := range generationCollections {
+		// $out replaces the target collection, so nothing is left of a
+		// failed run.
+		pipeline := mongodriver.Pipeline{
+			{{Key: "$out", Value: librarygen.Collection(base, to)}},
+		}
+		collection := librarygen.Collection(base, from)
+		cur, err := mongo.Client.Collection(collection).
+			Aggregate(context.TODO(), pipeline)
+		if err != nil {
+			return err
+		}
+		if err := cur.Close(context.TODO()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *schemaRepository) GetSchemas(
+	generation int64,
+) (map[string]*model.Schema, error) {
+	cur, err := mongo.Client.Find(
+		librarygen.Collection(constant.MongoIndex.Schema, generation),
+		bson.M{},
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(context.TODO())
+
+	schemas := make(map[string]*model.Schema)
+	for cur.Next(context.TODO()) {
+		var schema model.Schema
+		if err := cur.Decode(&schema); err != nil {
+			return nil, err
+		}
+		schemas[schema.Name] = &schema
+	}
+	return schemas, cur.Err()
+}
+
+func (r *schemaRepository) GetFields(
+	generation int64,
+) (map[string]*model.Field, error) {
+	cur, err := mongo.Client.Find(
+		librarygen.Collection(constant.MongoIndex.Field, generation),
+		bson.M{},
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(context.TODO())
+
+	fields := make(map[string]*model.Field)
+	for cur.Next(context.TODO()) {
+		var field model.Field
+		if err := cur.Decode(&field); err != nil {
+			return nil, err
+		}
+		fields[field.Name] = &field
+	}
+	return fields, cur.Err()
+}
+
+// publishedDraft is a draft of the registry which was published, with the
+// fields it uses.
+type publishedDraft struct {
+	model.Schema `bson:",inline"`
+	Fields       []string `bson:"fields"`
+}
+
+func (r *schemaRepository) AddPublishedDrafts(generation int64) error {
+	cur, err := mongo.Client.Find(
+		constant.MongoIndex.SchemaDraft,
+		bson.M{"status": publishedStatus},
+	)
+	if err != nil {
+		return err
+	}
+	defer cur.Close(context.TODO())
+
+	for cur.Next(context.TODO()) {
+		var draft publishedDraft
+		if err := cur.Decode(&draft); err != nil {
+			return err
+		}
+		if err := addPublishedDraft(generation, &draft); err != nil {
+			return err
+		}
+	}
+	return cur.Err()
+}
+
+// addPublishedDraft adds the schema of the draft to the generation, unless
+// the generation has it already, and records it in the fields it uses.
+func addPublishedDraft(generation int64, draft *publishedDraft) error {
+	_, err := mongo.Client.FindOneAndUpdate(
+		librarygen.Collection(constant.MongoIndex.Schema, generation),
+		bson.M{"name": draft.Name},
+		bson.M{"$setOnInsert": draft.Schema},
+		options.FindOneAndUpdate().SetUpsert(true),
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, field := range draft.Fields {
+		_, err := mongo.Client.FindOneAndUpdate(
+			librarygen.Collection(constant.MongoIndex.Field, generation),
+			bson.M{"name": field},
+			bson.M{"$addToSet": bson.M{"used_by": draft.Name}},
+		)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *schemaRepository) Activate(
+	meta *librarygen.Meta,
+	generation librarygen.Generation,
+) error {
+	// The meta is only replaced if it wasn't changed since it was read at
+	// the start of the update, by another update or by the publication of a
+	// schema. Otherwise, the upsert fails on the duplicate ID.
+	filter := meta.VersionFilter()
+	update := bson.M{"$set": bson.M{
+		"active":   generation,
+		"previous": meta.Active,
+	}}
+	opt := options.FindOneAndUpdate().SetUpsert(true)
+
+	_, err := mongo.Client.FindOneAndUpdate(
+		constant.MongoIndex.SchemaMeta,
+		filter,
+		update,
+		opt,
+	)
+	if mongodriver.IsDuplicateKeyError(err) {
+		return ErrGenerationChanged
+	}
+	return err
+}
+
+func (r *schemaRepository) DropGeneration(generation int64) error {
+	for _, base := range generationCollections {
+		collection := librarygen.Collection(base, generation)
+		err := mongo.Client.Collection(collection).Drop(context.TODO())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *schemaRepository) SaveRun(run *model.Run) error {
+	_, err := mongo.Client.InsertOne(constant.MongoIndex.SchemaRun, run)
+	return err
+}
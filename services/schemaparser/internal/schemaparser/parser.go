@@ -7,6 +7,8 @@ import (
 	"io"
 	"net/http"
 	"path"
+	"sort"
+	"strings"
 
 	"github.com/iancoleman/orderedmap"
 	"go.mongodb.org/mongo-driver/bson"
@@ -37,6 +39,17 @@ type SchemaResult struct {
 	Schema *model.SchemaJSON
 	// Full schema data as BSON.
 	FullJSON bson.D
+	// Names of the fields referenced by the schema, including the fields
+	// referenced by those fields.
+	Fields []string
+}
+
+// FieldResult represents the get field result.
+type FieldResult struct {
+	// Parsed JSON field data.
+	Field *model.FieldJSON
+	// Full field data as BSON, with its references resolved.
+	FullJSON bson.D
 }
 
 // SchemaParser represents the schema parser.
@@ -69,7 +82,11 @@ func (s *SchemaParser) GetSchema(url string) (*SchemaResult, error) {
 		return nil, fmt.Errorf("failed to convert schema to BSON: %w", err)
 	}
 
-	return &SchemaResult{Schema: parsedSchema, FullJSON: fullJSON}, nil
+	return &SchemaResult{
+		Schema:   parsedSchema,
+		FullJSON: fullJSON,
+		Fields:   referencedFields(schemaData, s.fetchField),
+	}, nil
 }
 
 // GetLocalSchema fetches, parses and converts a schema to BSON from local schema byte.
@@ -87,7 +104,125 @@ func (s *SchemaParser) GetLocalSchema(
 		return nil, fmt.Errorf("failed to convert schema to BSON: %w", err)
 	}
 
-	return &SchemaResult{Schema: parsedSchema, FullJSON: fullJSON}, nil
+	lookup := func(fileName string) ([]byte, bool) {
+		data, ok := fields[fileName]
+		return data, ok
+	}
+	return &SchemaResult{
+		Schema:   parsedSchema,
+		FullJSON: fullJSON,
+		Fields:   referencedFields(schema, lookup),
+	}, nil
+}
+
+// GetField fetches, parses and converts a field to BSON from a given URL.
+func (s *SchemaParser) GetField(url string) (*FieldResult, error) {
+	fieldData, err := s.fetchSchema(url)
+	if err != nil {
+		return nil, err
+	}
+	return s.getField(fieldData)
+}
+
+// GetLocalField parses and converts a field to BSON from local field byte.
+func (s *SchemaParser) GetLocalField(
+	field []byte,
+	fields map[string][]byte,
+) (*FieldResult, error) {
+	return s.getField(field, fields)
+}
+
+func (s *SchemaParser) getField(
+	data []byte,
+	optionalFields ...map[string][]byte,
+) (*FieldResult, error) {
+	var field model.FieldJSON
+	if err := json.Unmarshal(data, &field); err != nil {
+		return nil, fmt.Errorf("failed to parse field: %w", err)
+	}
+
+	fullJSON, err := s.convertToBson(data, optionalFields...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert field to BSON: %w", err)
+	}
+
+	return &FieldResult{Field: &field, FullJSON: fullJSON}, nil
+}
+
+// FieldName returns the name of a field from its file name or from a
+// reference to it, such as "name" for "../fields/name.json".
+func FieldName(ref string) string {
+	return strings.TrimSuffix(path.Base(ref), ".json")
+}
+
+// referencedFields returns the sorted names of the fields referenced with
+// `$ref` anywhere in the schema, and in turn by those fields, which lookup
+// returns given their file names. The references within the schema, which
+// start with "#", aren't fields.
+func referencedFields(
+	data []byte,
+	lookup func(fileName string) ([]byte, bool),
+) []string {
+	names := make(map[string]struct{})
+	var walk func(value interface{})
+	walk = func(value interface{}) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			for key, child := range v {
+				ref, ok := child.(string)
+				if !ok || key != ReferenceKey {
+					walk(child)
+					continue
+				}
+				if strings.HasPrefix(ref, "#") {
+					continue
+				}
+				// A reference to a part of a field is to the field.
+				name := FieldName(strings.SplitN(ref, "#", 2)[0])
+				if _, ok := names[name]; ok {
+					continue
+				}
+				names[name] = struct{}{}
+				if field, ok := lookup(name + ".json"); ok {
+					walk(unmarshalJSON(field))
+				}
+			}
+		case []interface{}:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(unmarshalJSON(data))
+
+	fields := make([]string, 0, len(names))
+	for name := range names {
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// unmarshalJSON returns the decoded JSON data, or nil when it isn't valid.
+func unmarshalJSON(data []byte) interface{} {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil
+	}
+	return value
+}
+
+// fetchField fetches a field given its file name, from the field list.
+func (s *SchemaParser) fetchField(fileName string) ([]byte, bool) {
+	url, ok := s.FieldListMap[fileName]
+	if !ok {
+		return nil, false
+	}
+	data, err := s.fetchSchema(url)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
 }
 
 // fetchSchema fetches the schema data from the provided URL.
@@ -144,6 +279,11 @@ func (s *SchemaParser) parseSchema(data []byte) (*model.SchemaJSON, error) {
 		return nil, err
 	}
 
+	// A schema with a sunset is deprecated until then.
+	if schema.Metadata.Schema.SunsetAt != 0 {
+		schema.Metadata.Schema.Deprecated = true
+	}
+
 	return &schema, nil
 }
 
@@ -226,6 +226,38 @@ func TestGetSchema(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, expectedSchema, result.Schema)
 	require.Equal(t, toMap(expectedFullJSON), toMap(result.FullJSON))
+	require.Equal(t, []string{"name"}, result.Fields)
+}
+
+func TestGetLocalField(t *testing.T) {
+	fields := map[string][]byte{
+		"name.json": []byte(`{"title": "Name", "type": "string"}`),
+		"contact.json": []byte(`{
+			"title": "Contact",
+			"description": "A way to reach the entity.",
+			"type": "object",
+			"properties": {"name": {"$ref": "./name.json"}}
+		}`),
+	}
+
+	result, err := schemaparser.NewSchemaParser(nil).
+		GetLocalField(fields["contact.json"], fields)
+	require.NoError(t, err)
+	require.Equal(t, &model.FieldJSON{
+		Title:       "Contact",
+		Description: "A way to reach the entity.",
+	}, result.Field)
+	require.Equal(t, map[string]interface{}{
+		"title":       "Contact",
+		"description": "A way to reach the entity.",
+		"type":        "object",
+		"properties": map[string]interface{}{
+			"name": map[string]interface{}{
+				"title": "Name",
+				"type":  "string",
+			},
+		},
+	}, toMap(result.FullJSON))
 }
 
 func toMap(d bson.D) map[string]interface{} {
@@ -241,3 +273,75 @@ func toMap(d bson.D) map[string]interface{} {
 	}
 	return m
 }
+
+func TestGetLocalSchema_Lifecycle(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata string
+		expected model.InnerSchema
+	}{
+		{
+			name:     "active",
+			metadata: `{"name": "test_schema-v1.0.0"}`,
+			expected: model.InnerSchema{Name: "test_schema-v1.0.0"},
+		},
+		{
+			name: "deprecated",
+			metadata: `{"name": "test_schema-v1.0.0", "deprecated": true,
+				"replaced_by": "test_schema-v2.0.0"}`,
+			expected: model.InnerSchema{
+				Name:       "test_schema-v1.0.0",
+				Deprecated: true,
+				ReplacedBy: "test_schema-v2.0.0",
+			},
+		},
+		{
+			name:     "sunset",
+			metadata: `{"name": "test_schema-v1.0.0", "sunset_at": 1800000000}`,
+			expected: model.InnerSchema{
+				Name:       "test_schema-v1.0.0",
+				Deprecated: true,
+				SunsetAt:   1800000000,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := `{"title": "Test", "type": "object", "properties": {},
+				"metadata": {"schema": ` + tt.metadata + `}}`
+
+			result, err := schemaparser.NewSchemaParser(nil).
+				GetLocalSchema([]byte(schema), nil)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, result.Schema.Metadata.Schema)
+		})
+	}
+}
+
+func TestGetLocalSchema_Fields(t *testing.T) {
+	fields := map[string][]byte{
+		"name.json": []byte(`{"title": "Name", "type": "string"}`),
+		"contact.json": []byte(`{
+			"title": "Contact",
+			"type": "object",
+			"properties": {"name": {"$ref": "./name.json"}}
+		}`),
+	}
+	schema := []byte(`{
+		"title": "Test Schema",
+		"properties": {
+			"contact": {"$ref": "../fields/contact.json"},
+			"tags": {"type": "array", "items": {"$ref": "#/definitions/tag"}}
+		},
+		"definitions": {"tag": {"type": "string"}},
+		"metadata": {"schema": {"name": "test_schema-v1.0.0"}}
+	}`)
+
+	result, err := schemaparser.NewSchemaParser(nil).
+		GetLocalSchema(schema, fields)
+	require.NoError(t, err)
+	// The fields referenced by the fields are used by the schema too, and
+	// the references within the schema aren't fields.
+	require.Equal(t, []string{"contact", "name"}, result.Fields)
+}
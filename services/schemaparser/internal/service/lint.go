@@ -0,0 +1,88 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemalint"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/schemaparser"
+)
+
+// LintReportKey holds the lint report of the last update, as JSON.
+const LintReportKey = "schemas:lint:report"
+
+// parseLibrary resolves the references of the schema and field files and
+// lints the results. The files which can't be parsed are reported and left
+// out of the results.
+func parseLibrary(
+	schemas map[string][]byte,
+	fields map[string][]byte,
+) (
+	map[string]*schemaparser.SchemaResult,
+	map[string]*schemaparser.FieldResult,
+	*schemalint.Report,
+) {
+	report := &schemalint.Report{}
+	parser := schemaparser.NewSchemaParser(nil)
+
+	schemaResults := make(map[string]*schemaparser.SchemaResult)
+	for fileName, data := range schemas {
+		file := "schemas/" + fileName
+		result, err := parser.GetLocalSchema(data, fields)
+		if err != nil {
+			report.Add(file, schemalint.ReferenceIssue(err))
+			continue
+		}
+		fullJSON, err := bson.MarshalExtJSON(result.FullJSON, false, false)
+		if err != nil {
+			report.Add(file, schemalint.ReferenceIssue(err))
+			continue
+		}
+		report.Add(file, schemalint.Schema(fileName, fullJSON)...)
+		schemaResults[fileName] = result
+	}
+
+	fieldResults := make(map[string]*schemaparser.FieldResult)
+	for fileName, data := range fields {
+		file := "fields/" + fileName
+		result, err := parser.GetLocalField(data, fields)
+		if err != nil {
+			report.Add(file, schemalint.ReferenceIssue(err))
+			continue
+		}
+		fullJSON, err := bson.MarshalExtJSON(result.FullJSON, false, false)
+		if err != nil {
+			report.Add(file, schemalint.ReferenceIssue(err))
+			continue
+		}
+		report.Add(file, schemalint.Field(fullJSON)...)
+		fieldResults[fileName] = result
+	}
+
+	return schemaResults, fieldResults, report
+}
+
+// setLintReport stores the report so the authors can see the issues of
+// their files, including the warnings of the successful updates.
+func (s *schemaService) setLintReport(report *schemalint.Report) {
+	data, err := json.Marshal(report)
+	if err != nil {
+		fmt.Printf("Failed to marshal the lint report: %v\n", err)
+		return
+	}
+	if err := s.redis.Set(LintReportKey, string(data), 0); err != nil {
+		fmt.Printf("Failed to set Redis lint report key: %v\n", err)
+	}
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/dateutil"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/schemaparser"
+)
+
+// RecordRun adds the run to the update history, with the outcome of err.
+func (s *schemaService) RecordRun(run *model.Run, err error) error {
+	run.EndedAt = dateutil.GetNowUnix()
+	if run.ChangedSchemas == nil {
+		run.ChangedSchemas = []string{}
+	}
+	if run.ChangedFields == nil {
+		run.ChangedFields = []string{}
+	}
+	run.Status = model.RunStatus.Succeeded
+	if err != nil {
+		run.Status = model.RunStatus.Failed
+		// The lint errors are already listed one by one.
+		if len(run.Errors) == 0 {
+			run.Errors = []string{err.Error()}
+		}
+	}
+
+	if err := s.mongoRepo.SaveRun(run); err != nil {
+		return fmt.Errorf("failed to save the run: %w", err)
+	}
+	return nil
+}
+
+// getGeneration returns the schemas and fields of a generation, by name.
+func (s *schemaService) getGeneration(generation int64) (
+	map[string]*model.Schema,
+	map[string]*model.Field,
+	error,
+) {
+	schemas, err := s.mongoRepo.GetSchemas(generation)
+	if err != nil {
+		return nil, nil, fmt.Errorf(
+			"failed to get the schemas of generation %d: %w",
+			generation,
+			err,
+		)
+	}
+	fields, err := s.mongoRepo.GetFields(generation)
+	if err != nil {
+		return nil, nil, fmt.Errorf(
+			"failed to get the fields of generation %d: %w",
+			generation,
+			err,
+		)
+	}
+	// The schemas published through the registry are appended to used_by,
+	// which is compared sorted.
+	for _, field := range fields {
+		sort.Strings(field.UsedBy)
+	}
+	return schemas, fields, nil
+}
+
+// newSchemas returns the documents of the parsed schemas, by name.
+func newSchemas(
+	results map[string]*schemaparser.SchemaResult,
+) map[string]*model.Schema {
+	schemas := make(map[string]*model.Schema, len(results))
+	for _, result := range results {
+		metadata := result.Schema.Metadata.Schema
+		schemas[metadata.Name] = &model.Schema{
+			Title:       result.Schema.Title,
+			Description: result.Schema.Description,
+			Name:        metadata.Name,
+			URL:         metadata.URL,
+			FullSchema:  result.FullJSON,
+			Deprecated:  metadata.Deprecated,
+			SunsetAt:    metadata.SunsetAt,
+			ReplacedBy:  metadata.ReplacedBy,
+		}
+	}
+	return schemas
+}
+
+// newFields returns the documents of the parsed fields, by name, with the
+// parsed schemas using them.
+func newFields(
+	results map[string]*schemaparser.FieldResult,
+	schemaResults map[string]*schemaparser.SchemaResult,
+) map[string]*model.Field {
+	usage := make(map[string][]string)
+	for _, result := range schemaResults {
+		for _, field := range result.Fields {
+			usage[field] = append(
+				usage[field],
+				result.Schema.Metadata.Schema.Name,
+			)
+		}
+	}
+
+	fields := make(map[string]*model.Field, len(results))
+	for fileName, result := range results {
+		name := schemaparser.FieldName(fileName)
+		usedBy := append([]string{}, usage[name]...)
+		sort.Strings(usedBy)
+		fields[name] = &model.Field{
+			Name:        name,
+			Title:       result.Field.Title,
+			Description: result.Field.Description,
+			FullField:   result.FullJSON,
+			UsedBy:      usedBy,
+		}
+	}
+	return fields
+}
+
+// keepOtherUsage adds to the fields the schemas of the generation using them
+// which aren't in the source, such as the ones published through the
+// registry, since the source only tells which of its schemas use the fields.
+func keepOtherUsage(
+	fields map[string]*model.Field,
+	currentFields map[string]*model.Field,
+	currentSchemas map[string]*model.Schema,
+	sourceSchemas map[string]*model.Schema,
+) {
+	for name, field := range fields {
+		current, ok := currentFields[name]
+		if !ok {
+			continue
+		}
+		for _, schema := range current.UsedBy {
+			_, inGeneration := currentSchemas[schema]
+			_, inSource := sourceSchemas[schema]
+			if inGeneration && !inSource {
+				field.UsedBy = append(field.UsedBy, schema)
+			}
+		}
+		field.UsedBy = uniqueSorted(field.UsedBy)
+	}
+}
+
+// changedNames returns the sorted names of the documents which current
+// doesn't have, or has with other values.
+func changedNames[T any](docs, current map[string]T) []string {
+	var changed []string
+	for _, name := range sortedKeys(docs) {
+		old, ok := current[name]
+		if !ok || !sameDocument(docs[name], old) {
+			changed = append(changed, name)
+		}
+	}
+	return changed
+}
+
+// missingNames returns the sorted names of the documents which current
+// doesn't have.
+func missingNames[T any](docs, current map[string]T) []string {
+	var missing []string
+	for _, name := range sortedKeys(docs) {
+		if _, ok := current[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// sameDocument reports whether a and b are stored as the same BSON document.
+func sameDocument(a, b interface{}) bool {
+	dataA, err := bson.Marshal(a)
+	if err != nil {
+		return false
+	}
+	dataB, err := bson.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(dataA, dataB)
+}
+
+func uniqueSorted(values []string) []string {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return sortedKeys(set)
+}
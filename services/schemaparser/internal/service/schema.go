@@ -1,42 +1,56 @@
 package service
 
 import (
-	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
-	"go.mongodb.org/mongo-driver/bson"
-	"golang.org/x/sync/errgroup"
-
-	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/httputil"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/dateutil"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/librarygen"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/messaging"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/redis"
-	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/config"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/model"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/repository/mongo"
-	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/schemaparser"
 )
 
 const (
 	LastCommitKey = "schemas:lastCommit"
-	maxGoroutines = 10
+	// maxActivateAttempts is the number of times a generation is activated
+	// while schemas are being published through the registry.
+	maxActivateAttempts = 5
 )
 
 type SchemaService interface {
-	GetBranchInfo(url string) (*model.BranchInfo, error)
 	HasNewCommit(lastCommit string) (bool, error)
-	UpdateSchemas(branchSha string) error
 	SetLastCommit(lastCommit string) error
-	UpdateLocalSchemas(
+	// UpdateSchemas parses the schema and field files of a source, keyed by
+	// their paths, and writes the ones which changed to a new generation of
+	// the library, which replaces the active one once it is complete. The
+	// run describes the source and receives the changes.
+	UpdateSchemas(
 		schemas map[string][]byte,
 		fields map[string][]byte,
+		run *model.Run,
 	) error
+	// Rollback makes the previous generation of the library active again.
+	Rollback(run *model.Run) error
+	// RecordRun adds the run to the update history, with the outcome of
+	// err.
+	RecordRun(run *model.Run, err error) error
 	GetUpdateError() (string, error)
+	PublishSchemasUpdated() error
 }
 
 type schemaService struct {
 	mongoRepo mongo.SchemaRepository
 	redis     redis.Redis
+
+	// updatedMu guards updatedSchemas.
+	updatedMu sync.Mutex
+	// updatedSchemas holds the names of the schemas changed in this run.
+	updatedSchemas []string
 }
 
 func NewSchemaService(
@@ -49,205 +63,260 @@ func NewSchemaService(
 	}
 }
 
-// GetBranchInfo retrieves information about a specific GitHub branch given its URL.
-func (s *schemaService) GetBranchInfo(url string) (*model.BranchInfo, error) {
-	// GetByteWithBearerToken sends a GET request to the provided URL, using
-	// the provided bearer token for authentication.
-	bytes, err := httputil.GetByteWithBearerToken(
-		url,
-		config.Values.Github.TOKEN,
-	)
+// HasNewCommit checks if there's a new commit in the schema repository.
+func (s *schemaService) HasNewCommit(lastCommit string) (bool, error) {
+	val, err := s.redis.Get(LastCommitKey)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get data from %s: %w", url, err)
+		return false, fmt.Errorf(
+			"failed to retrieve last commit from Redis: %w",
+			err,
+		)
 	}
 
-	var data model.BranchInfo
+	return val != lastCommit, nil
+}
+
+// SetLastCommit updates the revision of the last loaded commit in the Redis
+// store.
+func (s *schemaService) SetLastCommit(newLastCommitTime string) error {
+	oldLastCommitTime, err := s.redis.Get("schemas:lastCommit")
+	if err != nil {
+		return fmt.Errorf(
+			"failed to retrieve old last commit time from Redis: %w",
+			err,
+		)
+	}
 
-	err = json.Unmarshal(bytes, &data)
+	ok, err := shouldSetLastCommitTime(oldLastCommitTime, newLastCommitTime)
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal data: %w", err)
+		return fmt.Errorf("failed to compare commit times: %w", err)
 	}
 
-	return &data, nil
-}
+	if !ok {
+		return nil
+	}
 
-// HasNewCommit checks if there's a new commit in the schema repository.
-func (s *schemaService) HasNewCommit(lastCommit string) (bool, error) {
-	val, err := s.redis.Get(LastCommitKey)
+	err = s.redis.Set("schemas:lastCommit", newLastCommitTime, 0)
 	if err != nil {
-		return false, fmt.Errorf(
-			"failed to retrieve last commit from Redis: %w",
+		return fmt.Errorf(
+			"failed to set new last commit time in Redis: %w",
 			err,
 		)
 	}
 
-	return val != lastCommit, nil
+	return nil
 }
 
-func (s *schemaService) UpdateSchemas(branchSha string) error {
-	// Get schema folder list and field folder list.
-	schemaListURL, fieldListURL, err := getSchemaAndFieldFolderURLs(branchSha)
-	if err != nil {
-		setErr := s.redis.Set(
-			"schemas:update:error",
-			fmt.Sprintf("Error getting schema and field URLs: %v", err),
-			0,
+func (s *schemaService) UpdateSchemas(
+	schemas map[string][]byte,
+	fields map[string][]byte,
+	run *model.Run,
+) error {
+	// Nothing is written unless all the files are valid, so a broken file
+	// never replaces the live library.
+	schemaResults, fieldResults, report := parseLibrary(schemas, fields)
+	s.setLintReport(report)
+	if report.HasErrors() {
+		run.Errors = report.Errors()
+		err := fmt.Errorf(
+			"the schemas failed the lint: %s",
+			strings.Join(report.Errors(), "; "),
 		)
-		if setErr != nil {
-			fmt.Printf("Failed to set Redis error key: %v\n", setErr)
-		}
+		s.setUpdateError(err.Error())
 		return err
 	}
 
-	// Read field folder list and create a map of field name and field URL.
-	fieldListMap, err := getFieldsURLMap(fieldListURL)
+	meta, err := s.mongoRepo.GetMeta()
+	if err != nil {
+		return fmt.Errorf("failed to get the library generations: %w", err)
+	}
+	run.Generation = meta.Active.Number
+
+	schemaDocs := newSchemas(schemaResults)
+	fieldDocs := newFields(fieldResults, schemaResults)
+	currentSchemas, currentFields, err := s.getGeneration(meta.Active.Number)
 	if err != nil {
-		setErr := s.redis.Set(
-			"schemas:update:error",
-			fmt.Sprintf("Error getting fields URL map: %v", err),
-			0,
-		)
-		if setErr != nil {
-			fmt.Printf("Failed to set Redis error key: %v\n", setErr)
-		}
 		return err
 	}
+	keepOtherUsage(fieldDocs, currentFields, currentSchemas, schemaDocs)
+	run.ChangedSchemas = changedNames(schemaDocs, currentSchemas)
+	// The fields deleted from the source are removed too.
+	run.ChangedFields = uniqueSorted(append(
+		changedNames(fieldDocs, currentFields),
+		missingNames(currentFields, fieldDocs)...,
+	))
+	// Without changes, the active generation is kept, and so is the previous
+	// one to roll back to.
+	if len(run.ChangedSchemas) == 0 && len(run.ChangedFields) == 0 {
+		return nil
+	}
 
-	// Read schema folder list.
-	schemaList, err := getGithubTree(schemaListURL)
+	// The new generation starts as a copy of the active one, so the schemas
+	// published through the registry are kept, and the ones published until
+	// its activation are added to it. A failed write leaves the active
+	// generation untouched and the next run starts over.
+	generation := librarygen.Generation{
+		Number:    meta.Next(),
+		Source:    run.Source,
+		Revision:  run.Revision,
+		Commit:    run.Commit,
+		CreatedAt: dateutil.GetNowUnix(),
+	}
+	err = s.mongoRepo.CopyGeneration(meta.Active.Number, generation.Number)
 	if err != nil {
-		setErr := s.redis.Set(
-			"schemas:update:error",
-			fmt.Sprintf("Error getting GitHub tree: %v", err),
-			0,
+		return fmt.Errorf(
+			"failed to copy generation %d: %w",
+			meta.Active.Number,
+			err,
 		)
-		if setErr != nil {
-			fmt.Printf("Failed to set Redis error key: %v\n", setErr)
-		}
-		return err
 	}
 
-	// Create a semaphore channel to limit goroutines.
-	semaphore := make(chan struct{}, maxGoroutines)
-
-	g, ctx := errgroup.WithContext(context.Background())
-
-	for _, schemaName := range schemaList {
-		schemaNameMap := schemaName.(map[string]interface{})
-		url := schemaNameMap["url"].(string)
-
-		g.Go(func() error {
-			select {
-			case <-ctx.Done():
-				return nil
-			default:
-				semaphore <- struct{}{}
-				defer func() { <-semaphore }()
-
-				parser := schemaparser.NewSchemaParser(fieldListMap)
-				result, err := parser.GetSchema(url)
-				if err != nil {
-					setErr := s.redis.Set(
-						"schemas:update:error",
-						fmt.Sprintf(
-							"Error parsing schema from URL %s: %v",
-							url,
-							err,
-						),
-						0,
-					)
-					if setErr != nil {
-						fmt.Printf(
-							"Failed to set Redis error key: %v\n",
-							setErr,
-						)
-					}
-					return err
-				}
-				return s.updateSchema(result.Schema, result.FullJSON)
+	for _, name := range run.ChangedSchemas {
+		err := s.mongoRepo.Update(generation.Number, schemaDocs[name])
+		if err != nil {
+			return fmt.Errorf("failed to update schema %s: %w", name, err)
+		}
+	}
+	for _, name := range run.ChangedFields {
+		field, ok := fieldDocs[name]
+		if !ok {
+			err := s.mongoRepo.DeleteField(generation.Number, name)
+			if err != nil {
+				return fmt.Errorf("failed to delete field %s: %w", name, err)
 			}
-		})
+			continue
+		}
+		err := s.mongoRepo.UpdateField(generation.Number, field)
+		if err != nil {
+			return fmt.Errorf("failed to update field %s: %w", name, err)
+		}
 	}
 
-	err = g.Wait()
+	err = s.activate(meta, generation)
 	if err != nil {
-		setErr := s.redis.Set(
-			"schemas:update:error",
-			fmt.Sprintf("Error waiting for goroutines: %v", err),
-			0,
+		return fmt.Errorf(
+			"failed to activate generation %d: %w",
+			generation.Number,
+			err,
 		)
-		if setErr != nil {
-			fmt.Printf("Failed to set Redis error key: %v\n", setErr)
+	}
+	run.Generation = generation.Number
+	s.setUpdatedSchemas(run.ChangedSchemas)
+
+	// The generation before the previous one can't be rolled back to.
+	if meta.Previous != nil {
+		err := s.mongoRepo.DropGeneration(meta.Previous.Number)
+		if err != nil {
+			fmt.Printf(
+				"Failed to drop generation %d: %v\n",
+				meta.Previous.Number,
+				err,
+			)
 		}
 	}
-	return err
+
+	return nil
 }
 
-// SetLastCommit updates the last commit time in the Redis store.
-func (s *schemaService) SetLastCommit(newLastCommitTime string) error {
-	oldLastCommitTime, err := s.redis.Get("schemas:lastCommit")
+// Rollback makes the previous generation of the library active again, and
+// the active one the previous one, so a rollback can be undone with another
+// rollback. The last commit is kept, so the rolled back revision isn't
+// loaded again until the source changes.
+func (s *schemaService) Rollback(run *model.Run) error {
+	meta, err := s.mongoRepo.GetMeta()
+	if err != nil {
+		return fmt.Errorf("failed to get the library generations: %w", err)
+	}
+	run.Generation = meta.Active.Number
+	if meta.Previous == nil {
+		return errors.New("there is no previous generation to roll back to")
+	}
+
+	previous := *meta.Previous
+	run.Source = previous.Source
+	run.Revision = previous.Revision
+	run.Commit = previous.Commit
+
+	// The schemas published through the registry since the previous
+	// generation was written are kept.
+	err = s.mongoRepo.AddPublishedDrafts(previous.Number)
 	if err != nil {
 		return fmt.Errorf(
-			"failed to retrieve old last commit time from Redis: %w",
+			"failed to add the published drafts to generation %d: %w",
+			previous.Number,
 			err,
 		)
 	}
 
-	ok, err := shouldSetLastCommitTime(oldLastCommitTime, newLastCommitTime)
+	activeSchemas, activeFields, err := s.getGeneration(meta.Active.Number)
 	if err != nil {
-		return fmt.Errorf("failed to compare commit times: %w", err)
+		return err
 	}
-
-	if !ok {
-		return nil
+	schemas, fields, err := s.getGeneration(previous.Number)
+	if err != nil {
+		return err
 	}
-
-	err = s.redis.Set("schemas:lastCommit", newLastCommitTime, 0)
+	// The schemas and fields added since are removed too.
+	run.ChangedSchemas = uniqueSorted(append(
+		changedNames(schemas, activeSchemas),
+		changedNames(activeSchemas, schemas)...,
+	))
+	run.ChangedFields = uniqueSorted(append(
+		changedNames(fields, activeFields),
+		changedNames(activeFields, fields)...,
+	))
+
+	err = s.activate(meta, previous)
 	if err != nil {
 		return fmt.Errorf(
-			"failed to set new last commit time in Redis: %w",
+			"failed to activate generation %d: %w",
+			previous.Number,
 			err,
 		)
 	}
+	run.Generation = previous.Number
+	s.setUpdatedSchemas(run.ChangedSchemas)
 
 	return nil
 }
 
-func (s *schemaService) UpdateLocalSchemas(
-	schemas map[string][]byte,
-	fields map[string][]byte,
+// activate adds the schemas published through the registry to the
+// generation and makes it active. When another schema is published
+// meanwhile, it is added and the activation is tried again, unless another
+// generation was activated.
+func (s *schemaService) activate(
+	meta *librarygen.Meta,
+	generation librarygen.Generation,
 ) error {
-	parser := schemaparser.NewSchemaParser(nil)
-	for _, schema := range schemas {
-		result, err := parser.GetLocalSchema(schema, fields)
+	for attempt := 1; ; attempt++ {
+		err := s.mongoRepo.AddPublishedDrafts(generation.Number)
 		if err != nil {
-			setErr := s.redis.Set(
-				"schemas:update:error",
-				fmt.Sprintf("Error waiting for goroutines: %v", err),
-				0,
-			)
-			if setErr != nil {
-				fmt.Printf("Failed to set Redis error key: %v\n", setErr)
-			}
+			return fmt.Errorf("failed to add the published drafts: %w", err)
+		}
+
+		err = s.mongoRepo.Activate(meta, generation)
+		if !errors.Is(err, mongo.ErrGenerationChanged) ||
+			attempt == maxActivateAttempts {
 			return err
 		}
-		err = s.updateSchema(result.Schema, result.FullJSON)
+
+		current, err := s.mongoRepo.GetMeta()
 		if err != nil {
-			setErr := s.redis.Set(
-				"schemas:update:error",
-				fmt.Sprintf("Error waiting for goroutines: %v", err),
-				0,
+			return fmt.Errorf(
+				"failed to get the library generations: %w",
+				err,
 			)
-			if setErr != nil {
-				fmt.Printf("Failed to set Redis error key: %v\n", setErr)
-			}
-			return err
 		}
+		if current.Active.Number != meta.Active.Number {
+			return mongo.ErrGenerationChanged
+		}
+		meta = current
 	}
-
-	return nil
 }
 
+// shouldSetLastCommitTime reports whether the new revision replaces the old
+// one. The revisions which aren't commit times, such as the SHAs of the git
+// source, are always replaced.
 func shouldSetLastCommitTime(oldTime, newTime string) (bool, error) {
 	if oldTime == "" {
 		return true, nil
@@ -258,11 +327,11 @@ func shouldSetLastCommitTime(oldTime, newTime string) (bool, error) {
 
 	t1, err := time.Parse(time.RFC3339, oldTime)
 	if err != nil {
-		return false, err
+		return true, nil
 	}
 	t2, err := time.Parse(time.RFC3339, newTime)
 	if err != nil {
-		return false, err
+		return true, nil
 	}
 
 	// To make sure DNS updates the content of schemas.
@@ -274,117 +343,36 @@ func shouldSetLastCommitTime(oldTime, newTime string) (bool, error) {
 	return true, nil
 }
 
-func (s *schemaService) updateSchema(
-	schema *model.SchemaJSON,
-	fullJSON bson.D,
-) error {
-	doc := &model.Schema{
-		Title:       schema.Title,
-		Description: schema.Description,
-		Name:        schema.Metadata.Schema.Name,
-		URL:         schema.Metadata.Schema.URL,
-		FullSchema:  fullJSON,
-	}
-	err := s.mongoRepo.Update(doc)
+// setUpdateError stores the error which stops the next runs until it is
+// resolved.
+func (s *schemaService) setUpdateError(message string) {
+	err := s.redis.Set("schemas:update:error", message, 0)
 	if err != nil {
-		return err
+		fmt.Printf("Failed to set Redis error key: %v\n", err)
 	}
-	return nil
 }
 
-// getBranchFolders retrieves URLs for 'schemas' and 'fields' directories
-// given the branch's SHA.
-func getSchemaAndFieldFolderURLs(branchSha string) (string, string, error) {
-	// Construct the URL to the GitHub tree API endpoint for the branch.
-	rootURL := config.Values.Github.TreeURL + "/" + branchSha
+// PublishSchemasUpdated notifies other services about the schemas changed in
+// this run so they can drop any cached copies.
+func (s *schemaService) PublishSchemasUpdated() error {
+	s.updatedMu.Lock()
+	schemas := s.updatedSchemas
+	s.updatedSchemas = nil
+	s.updatedMu.Unlock()
 
-	// Fetch the tree (list of files and directories) from the GitHub API.
-	rootList, err := getGithubTree(rootURL)
-	if err != nil {
-		return "", "", fmt.Errorf("failed to fetch tree from %s: %w",
-			rootURL, err)
+	if len(schemas) == 0 {
+		return nil
 	}
 
-	var (
-		schemaListURL string
-		fieldListURL  string
+	err := messaging.Broadcast(
+		messaging.SchemasUpdated,
+		messaging.SchemasUpdatedData{Schemas: schemas},
 	)
-
-	// Iterate over each item in the root directory.
-	for _, item := range rootList {
-		itemMap := item.(map[string]interface{})
-		// If the item is the 'schemas' directory, save its URL.
-		if itemMap["path"].(string) == "schemas" {
-			schemaListURL = itemMap["url"].(string)
-		}
-		// If the item is the 'fields' directory, save its URL.
-		if itemMap["path"].(string) == "fields" {
-			fieldListURL = itemMap["url"].(string)
-		}
-	}
-
-	if schemaListURL == "" {
-		return "", "", fmt.Errorf("'schemas' directory not found")
-	}
-	if fieldListURL == "" {
-		return "", "", fmt.Errorf("'fields' directory not found")
-	}
-
-	return schemaListURL, fieldListURL, nil
-}
-
-// getFieldsURLMap fetches the GitHub tree (list of files and directories)
-// from the given URL, and then creates a map that maps field path to field URL.
-func getFieldsURLMap(fieldListURL string) (map[string]string, error) {
-	fieldList, err := getGithubTree(fieldListURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch tree from %s: %w",
-			fieldListURL, err)
+		return fmt.Errorf("failed to publish schemas updated event: %w", err)
 	}
 
-	// Initialize a map to hold the field path to URL mapping.
-	fieldListMap := make(map[string]string)
-
-	// Iterate over each field in the list.
-	for _, field := range fieldList {
-		fieldMap := field.(map[string]interface{})
-		fieldPath := fieldMap["path"].(string)
-		fieldURL := fieldMap["url"].(string)
-
-		// If the field has a non-empty path and URL, add it to the map.
-		if len(fieldPath) > 0 && len(fieldURL) > 0 {
-			fieldListMap[fieldPath] = fieldURL
-		} else {
-			return nil, fmt.Errorf("field path '%s' or url '%s' is empty",
-				fieldPath, fieldURL)
-		}
-	}
-
-	// Return the map.
-	return fieldListMap, nil
-}
-
-// getGithubTree fetches and returns the GitHub repository's tree (list of
-// files and directories) given a URL to the tree API endpoint.
-//
-// https://docs.github.com/en/rest/git/trees?apiVersion=2022-11-28#get-a-tree.
-func getGithubTree(url string) ([]interface{}, error) {
-	resp, err := httputil.GetWithBearerToken(url, config.Values.Github.TOKEN)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch data from %s: %w", url, err)
-	}
-
-	defer resp.Body.Close()
-
-	var data map[string]interface{}
-	err = json.NewDecoder(resp.Body).Decode(&data)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decode JSON response: %w", err)
-	}
-
-	tree := data["tree"].([]interface{})
-
-	return tree, nil
+	return nil
 }
 
 func (s *schemaService) GetUpdateError() (string, error) {
@@ -402,3 +390,9 @@ func (s *schemaService) GetUpdateError() (string, error) {
 
 	return val, nil
 }
+
+func (s *schemaService) setUpdatedSchemas(names []string) {
+	s.updatedMu.Lock()
+	s.updatedSchemas = names
+	s.updatedMu.Unlock()
+}
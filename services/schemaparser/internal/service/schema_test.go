@@ -1,9 +1,18 @@
 package service
 
 import (
+	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/librarygen"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/redis"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemalint"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/repository/mongo"
 )
 
 func TestShouldSetLastCommitTime(t *testing.T) {
@@ -37,4 +46,506 @@ func TestShouldSetLastCommitTime(t *testing.T) {
 		ok, _ := shouldSetLastCommitTime(oldLastCommitTime, newLastCommitTime)
 		assert.Equal(t, true, ok)
 	})
+	t.Run("revision is not a time", func(t *testing.T) {
+		oldRevision := "2021-02-19T00:00:00Z"
+		newRevision := "3f786850e387550fdab836ed7e6dc881de23001b"
+		ok, err := shouldSetLastCommitTime(oldRevision, newRevision)
+		require.NoError(t, err)
+		assert.Equal(t, true, ok)
+	})
+}
+
+// fakeSchemaRepo keeps the generations of the library in memory.
+type fakeSchemaRepo struct {
+	meta        librarygen.Meta
+	generations map[int64]*fakeGeneration
+	runs        []*model.Run
+	// failSchema is the name of a schema which can't be written.
+	failSchema string
+	// drafts holds the schemas published through the registry.
+	drafts map[string]*model.Schema
+	// beforeActivate runs before each activation.
+	beforeActivate func()
+}
+
+type fakeGeneration struct {
+	schemas map[string]*model.Schema
+	fields  map[string]*model.Field
+}
+
+func newFakeSchemaRepo() *fakeSchemaRepo {
+	return &fakeSchemaRepo{
+		generations: map[int64]*fakeGeneration{0: newFakeGeneration()},
+		drafts:      map[string]*model.Schema{},
+	}
+}
+
+func newFakeGeneration() *fakeGeneration {
+	return &fakeGeneration{
+		schemas: map[string]*model.Schema{},
+		fields:  map[string]*model.Field{},
+	}
+}
+
+// active returns the generation the library reads.
+func (r *fakeSchemaRepo) active() *fakeGeneration {
+	return r.generations[r.meta.Active.Number]
+}
+
+func (r *fakeSchemaRepo) GetMeta() (*librarygen.Meta, error) {
+	meta := r.meta
+	return &meta, nil
+}
+
+func (r *fakeSchemaRepo) CopyGeneration(from, to int64) error {
+	generation := newFakeGeneration()
+	for name, schema := range r.generations[from].schemas {
+		generation.schemas[name] = schema
+	}
+	for name, field := range r.generations[from].fields {
+		generation.fields[name] = field
+	}
+	r.generations[to] = generation
+	return nil
+}
+
+func (r *fakeSchemaRepo) Update(generation int64, schema *model.Schema) error {
+	if schema.Name == r.failSchema {
+		return errors.New("write failed")
+	}
+	r.generations[generation].schemas[schema.Name] = schema
+	return nil
+}
+
+func (r *fakeSchemaRepo) UpdateField(
+	generation int64,
+	field *model.Field,
+) error {
+	r.generations[generation].fields[field.Name] = field
+	return nil
+}
+
+func (r *fakeSchemaRepo) DeleteField(generation int64, name string) error {
+	delete(r.generations[generation].fields, name)
+	return nil
+}
+
+func (r *fakeSchemaRepo) GetSchemas(
+	generation int64,
+) (map[string]*model.Schema, error) {
+	return r.generations[generation].schemas, nil
+}
+
+func (r *fakeSchemaRepo) GetFields(
+	generation int64,
+) (map[string]*model.Field, error) {
+	return r.generations[generation].fields, nil
+}
+
+// publish publishes a schema through the registry, the way the library
+// does.
+func (r *fakeSchemaRepo) publish(schema *model.Schema) {
+	r.drafts[schema.Name] = schema
+	r.active().schemas[schema.Name] = schema
+	r.meta.Version++
+}
+
+func (r *fakeSchemaRepo) AddPublishedDrafts(generation int64) error {
+	for name, schema := range r.drafts {
+		if _, ok := r.generations[generation].schemas[name]; !ok {
+			r.generations[generation].schemas[name] = schema
+		}
+	}
+	return nil
+}
+
+func (r *fakeSchemaRepo) Activate(
+	meta *librarygen.Meta,
+	generation librarygen.Generation,
+) error {
+	if r.beforeActivate != nil {
+		r.beforeActivate()
+	}
+	if meta.Version != r.meta.Version {
+		return mongo.ErrGenerationChanged
+	}
+	previous := meta.Active
+	r.meta.Active = generation
+	r.meta.Previous = &previous
+	r.meta.Version++
+	return nil
+}
+
+func (r *fakeSchemaRepo) DropGeneration(generation int64) error {
+	delete(r.generations, generation)
+	return nil
+}
+
+func (r *fakeSchemaRepo) SaveRun(run *model.Run) error {
+	r.runs = append(r.runs, run)
+	return nil
+}
+
+func TestUpdateSchemas_FieldUsage(t *testing.T) {
+	schema := func(name string, refs ...string) []byte {
+		properties := `"linked_schemas": ` +
+			`{"$ref": "../fields/linked_schemas.json"}`
+		for _, ref := range refs {
+			properties += `, "` + ref + `": {"$ref": "../fields/` + ref +
+				`.json"}`
+		}
+		return []byte(`{"title": "` + name + `", "properties": {` +
+			properties + `}, "metadata": {"schema": {"name": "` + name +
+			`"}}}`)
+	}
+	repo := newFakeSchemaRepo()
+	svc := NewSchemaService(repo, redis.NewClient(redis.MemoryURL))
+
+	err := svc.UpdateSchemas(
+		map[string][]byte{
+			"people_schema-v0.1.0.json": schema(
+				"people_schema-v0.1.0", "name", "tags",
+			),
+			// The name is used through the contact.
+			"organizations_schema-v1.0.0.json": schema(
+				"organizations_schema-v1.0.0", "contact",
+			),
+		},
+		map[string][]byte{
+			"linked_schemas.json": []byte(
+				`{"title": "Linked Schemas", "type": "array"}`,
+			),
+			"name.json": []byte(`{"title": "Name", "type": "string"}`),
+			"contact.json": []byte(`{"title": "Contact", "type": "object",
+				"properties": {"name": {"$ref": "./name.json"}}}`),
+			"tags.json": []byte(
+				`{"title": "Tags", "type": "array", "items": {}}`,
+			),
+			"image.json": []byte(`{"title": "Image", "type": "string"}`),
+		},
+		&model.Run{},
+	)
+	require.NoError(t, err)
+
+	fields := repo.active().fields
+	require.Len(t, repo.active().schemas, 2)
+	require.Equal(
+		t,
+		[]string{"organizations_schema-v1.0.0", "people_schema-v0.1.0"},
+		fields["name"].UsedBy,
+	)
+	require.Equal(
+		t,
+		[]string{"organizations_schema-v1.0.0"},
+		fields["contact"].UsedBy,
+	)
+	require.Equal(
+		t,
+		[]string{"people_schema-v0.1.0"},
+		fields["tags"].UsedBy,
+	)
+	require.Equal(t, "Image", fields["image"].Title)
+	require.Empty(t, fields["image"].UsedBy)
+}
+
+func TestUpdateSchemas_RemovedField(t *testing.T) {
+	schemas := map[string][]byte{
+		"test_schema-v1.0.0.json": []byte(`{"title": "Test",
+			"properties": {"linked_schemas":
+				{"$ref": "../fields/linked_schemas.json"}},
+			"metadata": {"schema": {"name": "test_schema-v1.0.0"}}}`),
+	}
+	fields := map[string][]byte{
+		"linked_schemas.json": []byte(
+			`{"title": "Linked Schemas", "type": "array", "items": {}}`,
+		),
+		"image.json": []byte(`{"title": "Image", "type": "string"}`),
+	}
+	repo := newFakeSchemaRepo()
+	svc := NewSchemaService(repo, redis.NewClient(redis.MemoryURL))
+	require.NoError(t, svc.UpdateSchemas(schemas, fields, &model.Run{}))
+	require.Contains(t, repo.active().fields, "image")
+
+	delete(fields, "image.json")
+	run := &model.Run{}
+	require.NoError(t, svc.UpdateSchemas(schemas, fields, run))
+	require.Equal(t, []string{"image"}, run.ChangedFields)
+	require.Equal(
+		t,
+		[]string{"linked_schemas"},
+		sortedKeys(repo.active().fields),
+	)
+	// The previous generation keeps the field to roll back to.
+	previous := repo.generations[repo.meta.Previous.Number]
+	require.Contains(t, previous.fields, "image")
+}
+
+func TestUpdateSchemas_RegistryUsage(t *testing.T) {
+	schemas := func(title string) map[string][]byte {
+		return map[string][]byte{
+			"test_schema-v1.0.0.json": []byte(`{"title": "` + title + `",
+				"properties": {"linked_schemas":
+					{"$ref": "../fields/linked_schemas.json"}},
+				"metadata": {"schema": {"name": "test_schema-v1.0.0"}}}`),
+		}
+	}
+	fields := map[string][]byte{
+		"linked_schemas.json": []byte(
+			`{"title": "Linked Schemas", "type": "array", "items": {}}`,
+		),
+	}
+	repo := newFakeSchemaRepo()
+	svc := NewSchemaService(repo, redis.NewClient(redis.MemoryURL))
+	err := svc.UpdateSchemas(schemas("First"), fields, &model.Run{})
+	require.NoError(t, err)
+
+	// A schema using the field is published through the registry.
+	repo.publish(&model.Schema{Name: "registry_schema-v1.0.0"})
+	field := *repo.active().fields["linked_schemas"]
+	field.UsedBy = append(field.UsedBy, "registry_schema-v1.0.0")
+	repo.active().fields["linked_schemas"] = &field
+
+	// The same files don't change the field.
+	run := &model.Run{}
+	require.NoError(t, svc.UpdateSchemas(schemas("First"), fields, run))
+	require.Empty(t, run.ChangedFields)
+	require.Equal(t, int64(1), run.Generation)
+
+	// The published schema still uses the field after the next update.
+	run = &model.Run{}
+	require.NoError(t, svc.UpdateSchemas(schemas("Second"), fields, run))
+	require.Empty(t, run.ChangedFields)
+	require.Equal(t, int64(2), run.Generation)
+	require.Equal(
+		t,
+		[]string{"registry_schema-v1.0.0", "test_schema-v1.0.0"},
+		repo.active().fields["linked_schemas"].UsedBy,
+	)
+}
+
+func TestUpdateSchemas_Generations(t *testing.T) {
+	schemas := func(title string) map[string][]byte {
+		return map[string][]byte{
+			"test_schema-v1.0.0.json": []byte(`{"title": "` + title + `",
+				"properties": {"linked_schemas":
+					{"$ref": "../fields/linked_schemas.json"}},
+				"metadata": {"schema": {"name": "test_schema-v1.0.0"}}}`),
+		}
+	}
+	fields := map[string][]byte{
+		"linked_schemas.json": []byte(
+			`{"title": "Linked Schemas", "type": "array", "items": {}}`,
+		),
+	}
+	repo := newFakeSchemaRepo()
+	// A schema published through the registry, which the source doesn't
+	// hold.
+	repo.generations[0].schemas["registry_schema-v1.0.0"] = &model.Schema{
+		Name: "registry_schema-v1.0.0",
+	}
+	svc := NewSchemaService(repo, redis.NewClient(redis.MemoryURL))
+
+	run := &model.Run{Source: "git", Commit: "a1"}
+	err := svc.UpdateSchemas(schemas("First"), fields, run)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), repo.meta.Active.Number)
+	require.Equal(t, "a1", repo.meta.Active.Commit)
+	require.Equal(t, int64(0), repo.meta.Previous.Number)
+	require.Equal(t, int64(1), run.Generation)
+	require.Equal(t, []string{"test_schema-v1.0.0"}, run.ChangedSchemas)
+	require.Equal(t, []string{"linked_schemas"}, run.ChangedFields)
+
+	run = &model.Run{Source: "git", Commit: "b2"}
+	err = svc.UpdateSchemas(schemas("Second"), fields, run)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), repo.meta.Active.Number)
+	require.Equal(t, int64(1), repo.meta.Previous.Number)
+	require.Equal(t, []string{"test_schema-v1.0.0"}, run.ChangedSchemas)
+	require.Empty(t, run.ChangedFields)
+	// Only the active and the previous generations are kept.
+	require.Len(t, repo.generations, 2)
+	require.Equal(
+		t,
+		[]string{"registry_schema-v1.0.0", "test_schema-v1.0.0"},
+		sortedKeys(repo.active().schemas),
+	)
+
+	// The same files don't replace the previous generation.
+	run = &model.Run{Source: "git", Commit: "b2"}
+	err = svc.UpdateSchemas(schemas("Second"), fields, run)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), run.Generation)
+	require.Empty(t, run.ChangedSchemas)
+	require.Equal(t, int64(1), repo.meta.Previous.Number)
+
+	// A failed write leaves the active generation untouched.
+	repo.failSchema = "test_schema-v1.0.0"
+	run = &model.Run{Source: "git", Commit: "c3"}
+	err = svc.UpdateSchemas(schemas("Third"), fields, run)
+	require.Error(t, err)
+	require.Equal(t, int64(2), run.Generation)
+	require.Equal(t, "b2", repo.meta.Active.Commit)
+	require.Equal(
+		t,
+		"Second",
+		repo.active().schemas["test_schema-v1.0.0"].Title,
+	)
+
+	run = &model.Run{Action: model.RunAction.Rollback}
+	require.NoError(t, svc.Rollback(run))
+	require.Equal(t, "a1", repo.meta.Active.Commit)
+	require.Equal(t, "b2", repo.meta.Previous.Commit)
+	require.Equal(t, "a1", run.Commit)
+	require.Equal(t, int64(1), run.Generation)
+	require.Equal(t, []string{"test_schema-v1.0.0"}, run.ChangedSchemas)
+	require.Equal(
+		t,
+		"First",
+		repo.active().schemas["test_schema-v1.0.0"].Title,
+	)
+
+	// Rolling back again undoes the rollback.
+	require.NoError(t, svc.Rollback(&model.Run{}))
+	require.Equal(t, "b2", repo.meta.Active.Commit)
+}
+
+func TestUpdateSchemas_PublishedDrafts(t *testing.T) {
+	schemas := func(title string) map[string][]byte {
+		return map[string][]byte{
+			"test_schema-v1.0.0.json": []byte(`{"title": "` + title + `",
+				"properties": {"linked_schemas":
+					{"$ref": "../fields/linked_schemas.json"}},
+				"metadata": {"schema": {"name": "test_schema-v1.0.0"}}}`),
+		}
+	}
+	fields := map[string][]byte{
+		"linked_schemas.json": []byte(
+			`{"title": "Linked Schemas", "type": "array", "items": {}}`,
+		),
+	}
+	repo := newFakeSchemaRepo()
+	svc := NewSchemaService(repo, redis.NewClient(redis.MemoryURL))
+	err := svc.UpdateSchemas(schemas("First"), fields, &model.Run{})
+	require.NoError(t, err)
+
+	// A draft is approved while the next generation is written, after the
+	// active one was copied.
+	repo.beforeActivate = func() {
+		repo.beforeActivate = nil
+		repo.publish(&model.Schema{Name: "during_update-v1.0.0"})
+	}
+	run := &model.Run{}
+	require.NoError(t, svc.UpdateSchemas(schemas("Second"), fields, run))
+	require.Equal(t, int64(2), run.Generation)
+	require.Equal(
+		t,
+		[]string{"during_update-v1.0.0", "test_schema-v1.0.0"},
+		sortedKeys(repo.active().schemas),
+	)
+
+	// The drafts published since the previous generation are kept by a
+	// rollback.
+	repo.publish(&model.Schema{Name: "after_update-v1.0.0"})
+	run = &model.Run{Action: model.RunAction.Rollback}
+	require.NoError(t, svc.Rollback(run))
+	require.Equal(t, int64(1), run.Generation)
+	require.Equal(t, []string{"test_schema-v1.0.0"}, run.ChangedSchemas)
+	require.Equal(
+		t,
+		[]string{
+			"after_update-v1.0.0",
+			"during_update-v1.0.0",
+			"test_schema-v1.0.0",
+		},
+		sortedKeys(repo.active().schemas),
+	)
+	require.Equal(
+		t,
+		"First",
+		repo.active().schemas["test_schema-v1.0.0"].Title,
+	)
+}
+
+func TestRecordRun(t *testing.T) {
+	repo := newFakeSchemaRepo()
+	svc := NewSchemaService(repo, redis.NewClient(redis.MemoryURL))
+
+	require.NoError(t, svc.RecordRun(&model.Run{
+		Action:         model.RunAction.Update,
+		ChangedSchemas: []string{"test_schema-v1.0.0"},
+	}, nil))
+	require.NoError(t, svc.RecordRun(&model.Run{
+		Action: model.RunAction.Update,
+	}, errors.New("failed to load the schemas")))
+	require.NoError(t, svc.RecordRun(&model.Run{
+		Action: model.RunAction.Update,
+		Errors: []string{
+			"schemas/a.json: /: first",
+			"schemas/b.json: /: second",
+		},
+	}, errors.New("the schemas failed the lint")))
+
+	require.Len(t, repo.runs, 3)
+	require.Equal(t, model.RunStatus.Succeeded, repo.runs[0].Status)
+	require.NotZero(t, repo.runs[0].EndedAt)
+	require.Empty(t, repo.runs[0].Errors)
+	require.Equal(t, model.RunStatus.Failed, repo.runs[1].Status)
+	require.Equal(
+		t,
+		[]string{"failed to load the schemas"},
+		repo.runs[1].Errors,
+	)
+	require.Equal(t, []string{}, repo.runs[1].ChangedSchemas)
+	require.Len(t, repo.runs[2].Errors, 2)
+}
+
+func TestUpdateSchemas_Lint(t *testing.T) {
+	repo := newFakeSchemaRepo()
+	redisClient := redis.NewClient(redis.MemoryURL)
+	svc := NewSchemaService(repo, redisClient)
+
+	err := svc.UpdateSchemas(
+		map[string][]byte{
+			// The name doesn't match the file.
+			"test_schema-v1.0.0.json": []byte(`{"title": "Test",
+				"properties": {"linked_schemas": {"title": "Linked Schemas",
+					"type": "array", "items": {"type": "string"}}},
+				"metadata": {"schema": {"name": "test_schema-v2.0.0"}}}`),
+			"broken_schema-v1.0.0.json": []byte(`{"title": "Broken",
+				"properties": {"tags": {"$ref": "../fields/tags.json"}},
+				"metadata": {"schema": {"name": "broken_schema-v1.0.0"}}}`),
+		},
+		map[string][]byte{
+			"name.json": []byte(`{"type": "string"}`),
+		},
+		&model.Run{},
+	)
+	require.Error(t, err)
+
+	// Nothing replaces the live library.
+	require.Equal(t, int64(0), repo.meta.Active.Number)
+	require.Len(t, repo.generations, 1)
+
+	data, err := redisClient.Get(LintReportKey)
+	require.NoError(t, err)
+	var report schemalint.Report
+	require.NoError(t, json.Unmarshal([]byte(data), &report))
+
+	rules := map[string][]string{}
+	for _, file := range report.Files {
+		for _, issue := range file.Issues {
+			rules[file.File] = append(rules[file.File], issue.Rule)
+		}
+	}
+	require.Equal(t, map[string][]string{
+		"fields/name.json": {schemalint.RuleFieldTitle},
+		"schemas/broken_schema-v1.0.0.json": {
+			schemalint.RuleReference,
+		},
+		"schemas/test_schema-v1.0.0.json": {schemalint.RuleFileName},
+	}, rules)
+
+	updateError, err := svc.GetUpdateError()
+	require.NoError(t, err)
+	require.Contains(t, updateError, "schemas/test_schema-v1.0.0.json")
 }
@@ -0,0 +1,76 @@
+package source
+
+import (
+	"context"
+	"time"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+)
+
+type dirSource struct {
+	dir string
+	// interval between two checks of the files in watch mode.
+	interval time.Duration
+}
+
+// NewDir returns a source reading a local copy of the library, such as the
+// "library" folder. In watch mode, the files are checked at each interval.
+func NewDir(dir string, interval time.Duration) SchemaSource {
+	return &dirSource{
+		dir:      dir,
+		interval: interval,
+	}
+}
+
+func (s *dirSource) Name() string {
+	return "dir"
+}
+
+// Revision hashes the files, since a folder has no revision.
+func (s *dirSource) Revision(_ context.Context) (string, error) {
+	snapshot, err := readLibrary(s.dir)
+	if err != nil {
+		return "", err
+	}
+	return snapshot.Revision, nil
+}
+
+func (s *dirSource) Load(_ context.Context) (*Snapshot, error) {
+	return readLibrary(s.dir)
+}
+
+// Watch polls the files, as there is no portable way to be notified of the
+// changes in a folder.
+func (s *dirSource) Watch(ctx context.Context) <-chan struct{} {
+	changes := make(chan struct{}, 1)
+	go func() {
+		defer close(changes)
+
+		last, _ := s.Revision(ctx)
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			revision, err := s.Revision(ctx)
+			if err != nil {
+				logger.Error("Failed to read the schemas of "+s.dir, err)
+				continue
+			}
+			if revision == last {
+				continue
+			}
+			last = revision
+			// A pending change already covers this one.
+			select {
+			case changes <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return changes
+}
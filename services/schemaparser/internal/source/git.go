@@ -0,0 +1,88 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+type gitSource struct {
+	url    string
+	branch string
+}
+
+// NewGit returns a source cloning a branch of a git repository with the
+// `git` command, so the repository can be hosted anywhere. The library
+// folders are at the root of the repository.
+func NewGit(url, branch string) SchemaSource {
+	return &gitSource{
+		url:    url,
+		branch: branch,
+	}
+}
+
+func (s *gitSource) Name() string {
+	return "git"
+}
+
+// Revision returns the SHA of the last commit of the branch.
+func (s *gitSource) Revision(ctx context.Context) (string, error) {
+	out, err := runGit(ctx, "ls-remote", s.url, "refs/heads/"+s.branch)
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(out)
+	if len(fields) == 0 {
+		return "", fmt.Errorf("branch %s not found in %s", s.branch, s.url)
+	}
+	return fields[0], nil
+}
+
+// Load clones the last commit of the branch into a temporary folder.
+func (s *gitSource) Load(ctx context.Context) (*Snapshot, error) {
+	dir, err := os.MkdirTemp("", "schemaparser-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a temporary folder: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	_, err = runGit(
+		ctx,
+		"clone", "--quiet", "--depth", "1", "--single-branch",
+		"--branch", s.branch, s.url, dir,
+	)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := runGit(ctx, "-C", dir, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := readLibrary(dir)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.Commit = strings.TrimSpace(commit)
+	snapshot.Revision = snapshot.Commit
+	return snapshot, nil
+}
+
+func runGit(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	// Never wait for credentials.
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	out, err := cmd.Output()
+	if err != nil {
+		var stderr string
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			stderr = strings.TrimSpace(string(exitErr.Stderr))
+		}
+		return "", fmt.Errorf("git %s failed: %w: %s", args[0], err, stderr)
+	}
+	return string(out), nil
+}
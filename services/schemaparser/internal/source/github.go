@@ -0,0 +1,229 @@
+package source
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/model"
+)
+
+// maxGoroutines limits the files fetched at the same time from GitHub.
+const maxGoroutines = 10
+
+type githubSource struct {
+	branchURL string
+	treeURL   string
+	token     string
+}
+
+// NewGitHub returns a source reading a repository through the GitHub API.
+// branchURL is the API URL of the branch and treeURL the one of the trees of
+// the repository.
+func NewGitHub(branchURL, treeURL, token string) SchemaSource {
+	return &githubSource{
+		branchURL: branchURL,
+		treeURL:   treeURL,
+		token:     token,
+	}
+}
+
+func (s *githubSource) Name() string {
+	return "github"
+}
+
+// Revision returns the date of the last commit of the branch, which is
+// compared to the previous one to give GitHub time to serve the new content.
+func (s *githubSource) Revision(ctx context.Context) (string, error) {
+	branchInfo, err := s.getBranchInfo(ctx)
+	if err != nil {
+		return "", err
+	}
+	return branchInfo.Commit.InnerCommit.Author.Date, nil
+}
+
+func (s *githubSource) Load(ctx context.Context) (*Snapshot, error) {
+	branchInfo, err := s.getBranchInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	schemaListURL, fieldListURL, err := s.getFolderURLs(
+		ctx,
+		branchInfo.Commit.Sha,
+	)
+	if err != nil {
+		return nil, err
+	}
+	schemas, err := s.getFiles(ctx, schemaListURL)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := s.getFiles(ctx, fieldListURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Snapshot{
+		Revision: branchInfo.Commit.InnerCommit.Author.Date,
+		Commit:   branchInfo.Commit.Sha,
+		Schemas:  schemas,
+		Fields:   fields,
+	}, nil
+}
+
+// getBranchInfo retrieves information about the branch.
+func (s *githubSource) getBranchInfo(
+	ctx context.Context,
+) (*model.BranchInfo, error) {
+	bytes, err := get(ctx, s.branchURL, s.header())
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to get last_commit and schema_list from %s: %w",
+			s.branchURL,
+			err,
+		)
+	}
+
+	var data model.BranchInfo
+	err = json.Unmarshal(bytes, &data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal data: %w", err)
+	}
+	return &data, nil
+}
+
+// getFolderURLs retrieves the tree URLs of the 'schemas' and 'fields'
+// folders given the branch's SHA.
+func (s *githubSource) getFolderURLs(
+	ctx context.Context,
+	branchSha string,
+) (string, string, error) {
+	rootURL := s.treeURL + "/" + branchSha
+	rootList, err := s.getTree(ctx, rootURL)
+	if err != nil {
+		return "", "", err
+	}
+
+	var schemaListURL, fieldListURL string
+	for _, item := range rootList {
+		switch item.Path {
+		case "schemas":
+			schemaListURL = item.URL
+		case "fields":
+			fieldListURL = item.URL
+		}
+	}
+
+	if schemaListURL == "" {
+		return "", "", fmt.Errorf("'schemas' directory not found")
+	}
+	if fieldListURL == "" {
+		return "", "", fmt.Errorf("'fields' directory not found")
+	}
+	return schemaListURL, fieldListURL, nil
+}
+
+// getFiles fetches the files of a tree, keyed by their paths.
+func (s *githubSource) getFiles(
+	ctx context.Context,
+	treeURL string,
+) (map[string][]byte, error) {
+	tree, err := s.getTree(ctx, treeURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	files := make(map[string][]byte, len(tree))
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(maxGoroutines)
+	for _, item := range tree {
+		if item.Path == "" || item.URL == "" {
+			return nil, fmt.Errorf(
+				"file path '%s' or url '%s' is empty",
+				item.Path,
+				item.URL,
+			)
+		}
+		g.Go(func() error {
+			data, err := s.getBlob(ctx, item.URL)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			files[item.Path] = data
+			mu.Unlock()
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+type treeItem struct {
+	Path string `json:"path"`
+	URL  string `json:"url"`
+}
+
+// getTree fetches the tree (list of files and directories) of the
+// repository given a URL to the tree API endpoint.
+//
+// https://docs.github.com/en/rest/git/trees?apiVersion=2022-11-28#get-a-tree.
+func (s *githubSource) getTree(
+	ctx context.Context,
+	url string,
+) ([]treeItem, error) {
+	data, err := get(ctx, url, s.header())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tree from %s: %w", url, err)
+	}
+
+	var tree struct {
+		Tree []treeItem `json:"tree"`
+	}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON response: %w", err)
+	}
+	return tree.Tree, nil
+}
+
+// getBlob fetches the content of a file, which GitHub encodes in base64.
+func (s *githubSource) getBlob(
+	ctx context.Context,
+	url string,
+) ([]byte, error) {
+	data, err := get(ctx, url, s.header())
+	if err != nil {
+		return nil, err
+	}
+
+	var blob struct {
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal(data, &blob); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response body: %w", err)
+	}
+	content, err := base64.StdEncoding.DecodeString(blob.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode base64 content: %w", err)
+	}
+	if len(content) == 0 {
+		return nil, fmt.Errorf(
+			"get file failed, url: %s, content is empty",
+			url,
+		)
+	}
+	return content, nil
+}
+
+func (s *githubSource) header() http.Header {
+	return http.Header{"Authorization": {"Bearer " + s.token}}
+}
@@ -0,0 +1,131 @@
+package source
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// manifest lists the files of the library served over HTTP.
+type manifest struct {
+	// Revision is optional, the manifest is hashed without it.
+	Revision string `json:"revision"`
+	// Schemas and Fields are the URLs of the files, relative to the
+	// manifest.
+	Schemas []string `json:"schemas"`
+	Fields  []string `json:"fields"`
+}
+
+type httpSource struct {
+	manifestURL string
+}
+
+// NewHTTP returns a source reading the files listed by a JSON manifest, such
+// as:
+//
+//	{
+//	  "revision": "2024-05-01",
+//	  "schemas": ["schemas/organizations_schema-v1.0.0.json"],
+//	  "fields": ["fields/name.json"]
+//	}
+func NewHTTP(manifestURL string) SchemaSource {
+	return &httpSource{
+		manifestURL: manifestURL,
+	}
+}
+
+func (s *httpSource) Name() string {
+	return "http"
+}
+
+func (s *httpSource) Revision(ctx context.Context) (string, error) {
+	_, revision, err := s.getManifest(ctx)
+	return revision, err
+}
+
+func (s *httpSource) Load(ctx context.Context) (*Snapshot, error) {
+	m, revision, err := s.getManifest(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	schemas, err := s.getFiles(ctx, "schemas", m.Schemas)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := s.getFiles(ctx, "fields", m.Fields)
+	if err != nil {
+		return nil, err
+	}
+	return &Snapshot{
+		Revision: revision,
+		Schemas:  schemas,
+		Fields:   fields,
+	}, nil
+}
+
+// getManifest fetches the manifest and its revision.
+func (s *httpSource) getManifest(
+	ctx context.Context,
+) (*manifest, string, error) {
+	data, err := get(ctx, s.manifestURL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, "", fmt.Errorf("failed to unmarshal the manifest: %w", err)
+	}
+	if m.Revision != "" {
+		return &m, m.Revision, nil
+	}
+	hash := sha256.Sum256(data)
+	return &m, hex.EncodeToString(hash[:]), nil
+}
+
+// getFiles fetches the files of a folder of the library. Like in the other
+// sources, the files in the folder next to the manifest are keyed by their
+// paths relative to it, and the others by their names, which must be unique.
+func (s *httpSource) getFiles(
+	ctx context.Context,
+	folder string,
+	refs []string,
+) (map[string][]byte, error) {
+	base, err := url.Parse(s.manifestURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest URL: %w", err)
+	}
+	prefix := path.Join(path.Dir(base.Path), folder) + "/"
+
+	files := make(map[string][]byte, len(refs))
+	for _, ref := range refs {
+		fileURL, err := base.Parse(ref)
+		if err != nil {
+			return nil, fmt.Errorf("invalid file URL %s: %w", ref, err)
+		}
+		name := path.Base(fileURL.Path)
+		if strings.HasPrefix(fileURL.Path, prefix) {
+			name = strings.TrimPrefix(fileURL.Path, prefix)
+		}
+		if _, ok := files[name]; ok {
+			return nil, fmt.Errorf(
+				"the manifest lists more than one file as %s/%s",
+				folder,
+				name,
+			)
+		}
+
+		data, err := get(ctx, fileURL.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		files[name] = data
+	}
+	return files, nil
+}
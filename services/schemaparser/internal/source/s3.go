@@ -0,0 +1,263 @@
+package source
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Config locates a bucket of an S3-compatible storage.
+type S3Config struct {
+	// Endpoint is the URL of the storage, such as "https://s3.amazonaws.com".
+	// The buckets are addressed by path.
+	Endpoint string
+	Bucket   string
+	// Prefix is the folder holding the `schemas` and `fields` folders.
+	Prefix string
+	Region string
+	// The requests are anonymous without keys, for public buckets.
+	AccessKey string
+	SecretKey string
+}
+
+type s3Source struct {
+	cfg S3Config
+}
+
+// NewS3 returns a source reading the files of an S3-compatible bucket.
+func NewS3(cfg S3Config) SchemaSource {
+	cfg.Endpoint = strings.TrimSuffix(cfg.Endpoint, "/")
+	if cfg.Prefix != "" && !strings.HasSuffix(cfg.Prefix, "/") {
+		cfg.Prefix += "/"
+	}
+	return &s3Source{
+		cfg: cfg,
+	}
+}
+
+func (s *s3Source) Name() string {
+	return "s3"
+}
+
+// Revision hashes the ETags of the files, which change with their content.
+func (s *s3Source) Revision(ctx context.Context) (string, error) {
+	schemas, err := s.list(ctx, "schemas/")
+	if err != nil {
+		return "", err
+	}
+	fields, err := s.list(ctx, "fields/")
+	if err != nil {
+		return "", err
+	}
+	return hashFiles(schemas, fields), nil
+}
+
+func (s *s3Source) Load(ctx context.Context) (*Snapshot, error) {
+	schemaTags, err := s.list(ctx, "schemas/")
+	if err != nil {
+		return nil, err
+	}
+	fieldTags, err := s.list(ctx, "fields/")
+	if err != nil {
+		return nil, err
+	}
+
+	schemas, err := s.getFiles(ctx, "schemas/", schemaTags)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := s.getFiles(ctx, "fields/", fieldTags)
+	if err != nil {
+		return nil, err
+	}
+	return &Snapshot{
+		Revision: hashFiles(schemaTags, fieldTags),
+		Schemas:  schemas,
+		Fields:   fields,
+	}, nil
+}
+
+type listBucketResult struct {
+	Contents []struct {
+		Key  string `xml:"Key"`
+		ETag string `xml:"ETag"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// list returns the ETags of the JSON files of a folder, keyed by their paths
+// relative to the folder.
+func (s *s3Source) list(
+	ctx context.Context,
+	folder string,
+) (map[string][]byte, error) {
+	prefix := s.cfg.Prefix + folder
+	tags := make(map[string][]byte)
+	token := ""
+	for {
+		query := map[string]string{"list-type": "2", "prefix": prefix}
+		if token != "" {
+			query["continuation-token"] = token
+		}
+		data, err := s.get(ctx, "/"+s.cfg.Bucket, query)
+		if err != nil {
+			return nil, err
+		}
+
+		var result listBucketResult
+		if err := xml.Unmarshal(data, &result); err != nil {
+			return nil, fmt.Errorf("failed to decode the bucket list: %w", err)
+		}
+		for _, object := range result.Contents {
+			if strings.HasSuffix(object.Key, ".json") {
+				name := strings.TrimPrefix(object.Key, prefix)
+				tags[name] = []byte(object.ETag)
+			}
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return tags, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+func (s *s3Source) getFiles(
+	ctx context.Context,
+	folder string,
+	names map[string][]byte,
+) (map[string][]byte, error) {
+	files := make(map[string][]byte, len(names))
+	for name := range names {
+		key := s.cfg.Prefix + folder + name
+		data, err := s.get(ctx, "/"+s.cfg.Bucket+"/"+key, nil)
+		if err != nil {
+			return nil, err
+		}
+		files[name] = data
+	}
+	return files, nil
+}
+
+// get sends a GET request to the storage, signed when there are keys.
+func (s *s3Source) get(
+	ctx context.Context,
+	path string,
+	query map[string]string,
+) ([]byte, error) {
+	canonicalPath := awsEscape(path, true)
+	canonicalQuery := canonicalQueryString(query)
+	url := s.cfg.Endpoint + canonicalPath
+	if canonicalQuery != "" {
+		url += "?" + canonicalQuery
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a request to %s: %w", url, err)
+	}
+	if s.cfg.AccessKey != "" {
+		s.sign(req, canonicalPath, canonicalQuery, time.Now().UTC())
+	}
+	return do(req)
+}
+
+// emptyPayloadHash is the SHA-256 of the empty body of the GET requests.
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// sign adds the AWS Signature Version 4 of the request.
+//
+// https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html.
+func (s *s3Source) sign(
+	req *http.Request,
+	canonicalPath string,
+	canonicalQuery string,
+	now time.Time,
+) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", emptyPayloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		canonicalPath,
+		canonicalQuery,
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + emptyPayloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		emptyPayloadHash,
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+
+	scope := date + "/" + s.cfg.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	key := []byte("AWS4" + s.cfg.SecretKey)
+	for _, part := range []string{date, s.cfg.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKey,
+		scope,
+		signedHeaders,
+		signature,
+	))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// canonicalQueryString encodes the query sorted by key, as signed.
+func canonicalQueryString(query map[string]string) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = awsEscape(key, false) + "=" + awsEscape(query[key], false)
+	}
+	return strings.Join(pairs, "&")
+}
+
+// awsEscape percent-encodes all the characters but the unreserved ones of
+// RFC 3986, and the slashes of the paths.
+func awsEscape(s string, keepSlash bool) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~',
+			c == '/' && keepSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
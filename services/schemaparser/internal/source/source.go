@@ -0,0 +1,157 @@
+// Package source fetches the schemas and fields of the library from where
+// they are hosted, such as a GitHub repository or an S3 bucket.
+package source
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Snapshot holds the files of the library at a revision, keyed by their
+// paths in the `schemas` and `fields` folders, such as "name.json".
+type Snapshot struct {
+	// Revision identifies the content of the source, such as the SHA of a
+	// commit or a hash of the files.
+	Revision string
+	// Commit is the SHA of the loaded commit, for the versioned sources.
+	Commit  string
+	Schemas map[string][]byte
+	Fields  map[string][]byte
+}
+
+// SchemaSource is a place where the schemas of the library are hosted.
+type SchemaSource interface {
+	// Name is the kind of the source, such as "git", used in the logs.
+	Name() string
+	// Revision returns the current revision of the source, without loading
+	// the files when possible, so unchanged sources can be skipped.
+	Revision(ctx context.Context) (string, error)
+	// Load fetches the schema and field files.
+	Load(ctx context.Context) (*Snapshot, error)
+}
+
+// Watcher is implemented by the sources which can report their changes.
+type Watcher interface {
+	// Watch returns a channel receiving a value whenever the files change.
+	// The channel is closed once ctx is done.
+	Watch(ctx context.Context) <-chan struct{}
+}
+
+// client fetches the files of the remote sources.
+var client = &http.Client{Timeout: 30 * time.Second}
+
+// get sends a GET request and returns the body of the response, which must
+// be successful.
+func get(
+	ctx context.Context,
+	url string,
+	header http.Header,
+) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a request to %s: %w", url, err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	return do(req)
+}
+
+func do(req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", req.URL, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the body of %s: %w", req.URL, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf(
+			"request to %s returned status code %d",
+			req.URL,
+			resp.StatusCode,
+		)
+	}
+	return data, nil
+}
+
+// readLibrary reads the `schemas` and `fields` folders of a copy of the
+// library.
+func readLibrary(dir string) (*Snapshot, error) {
+	schemas, err := readFiles(filepath.Join(dir, "schemas"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema files from dir: %w", err)
+	}
+	fields, err := readFiles(filepath.Join(dir, "fields"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read field files from dir: %w", err)
+	}
+	return &Snapshot{
+		Revision: hashFiles(schemas, fields),
+		Schemas:  schemas,
+		Fields:   fields,
+	}, nil
+}
+
+// readFiles reads the JSON files of a folder and its sub-folders, keyed by
+// their paths relative to the folder.
+func readFiles(dirPath string) (map[string][]byte, error) {
+	filesData := make(map[string][]byte)
+
+	err := filepath.WalkDir(
+		dirPath,
+		func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && filepath.Ext(path) == ".json" {
+				data, err := os.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				relPath, err := filepath.Rel(dirPath, path)
+				if err != nil {
+					return err
+				}
+				filesData[filepath.ToSlash(relPath)] = data
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return filesData, nil
+}
+
+// hashFiles returns a hash of the names and contents of the files, used as
+// the revision of the sources without one.
+func hashFiles(schemas, fields map[string][]byte) string {
+	hash := sha256.New()
+	for _, files := range []map[string][]byte{schemas, fields} {
+		names := make([]string, 0, len(files))
+		for name := range files {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(hash, "%s\x00%d\x00", name, len(files[name]))
+			hash.Write(files[name])
+		}
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
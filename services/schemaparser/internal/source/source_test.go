@@ -0,0 +1,326 @@
+package source_test
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/source"
+)
+
+var (
+	testSchema = []byte(`{"title": "Test"}`)
+	testField  = []byte(`{"type": "string"}`)
+)
+
+// writeLibrary writes a library folder with a schema and a field.
+func writeLibrary(t *testing.T, dir string) {
+	t.Helper()
+	for path, data := range map[string][]byte{
+		"schemas/test_schema-v1.0.0.json": testSchema,
+		"fields/name.json":                testField,
+	} {
+		path = filepath.Join(dir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, data, 0o600))
+	}
+}
+
+func requireFiles(t *testing.T, snapshot *source.Snapshot) {
+	t.Helper()
+	require.Equal(
+		t,
+		map[string][]byte{"test_schema-v1.0.0.json": testSchema},
+		snapshot.Schemas,
+	)
+	require.Equal(t, map[string][]byte{"name.json": testField}, snapshot.Fields)
+	require.NotEmpty(t, snapshot.Revision)
+}
+
+func TestDir(t *testing.T) {
+	dir := t.TempDir()
+	writeLibrary(t, dir)
+	src := source.NewDir(dir, 10*time.Millisecond)
+
+	snapshot, err := src.Load(context.Background())
+	require.NoError(t, err)
+	requireFiles(t, snapshot)
+
+	revision, err := src.Revision(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, snapshot.Revision, revision)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := src.(source.Watcher).Watch(ctx)
+
+	// Let the watcher read the first revision.
+	time.Sleep(50 * time.Millisecond)
+	err = os.WriteFile(
+		filepath.Join(dir, "fields", "tags.json"),
+		[]byte(`{"type": "array"}`),
+		0o600,
+	)
+	require.NoError(t, err)
+
+	select {
+	case <-changes:
+	case <-time.After(time.Second):
+		t.Fatal("the change of the files was not reported")
+	}
+
+	revision, err = src.Revision(context.Background())
+	require.NoError(t, err)
+	require.NotEqual(t, snapshot.Revision, revision)
+}
+
+func TestGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	writeLibrary(t, dir)
+	for _, args := range [][]string{
+		{"init", "--quiet", "--initial-branch", "main"},
+		{"add", "."},
+		{"-c", "user.name=Test", "-c", "user.email=test@example.org",
+			"commit", "--quiet", "-m", "Add the schemas"},
+	} {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	src := source.NewGit("file://"+dir, "main")
+	revision, err := src.Revision(context.Background())
+	require.NoError(t, err)
+	require.Len(t, revision, 40)
+
+	snapshot, err := src.Load(context.Background())
+	require.NoError(t, err)
+	requireFiles(t, snapshot)
+	require.Equal(t, revision, snapshot.Revision)
+	require.Equal(t, revision, snapshot.Commit)
+
+	_, err = source.NewGit("file://"+dir, "missing").
+		Revision(context.Background())
+	require.Error(t, err)
+}
+
+func TestHTTP(t *testing.T) {
+	files := map[string][]byte{
+		"/library/manifest.json": []byte(`{
+			"schemas": ["schemas/test_schema-v1.0.0.json"],
+			"fields": ["/fields/name.json"]
+		}`),
+		"/library/schemas/test_schema-v1.0.0.json": testSchema,
+		"/fields/name.json":                        testField,
+	}
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, ok := files[r.URL.Path]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write(data)
+		}),
+	)
+	defer server.Close()
+
+	src := source.NewHTTP(server.URL + "/library/manifest.json")
+	snapshot, err := src.Load(context.Background())
+	require.NoError(t, err)
+	requireFiles(t, snapshot)
+
+	// Without a revision in the manifest, the manifest is hashed.
+	revision, err := src.Revision(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, snapshot.Revision, revision)
+
+	_, err = source.NewHTTP(server.URL + "/missing.json").
+		Load(context.Background())
+	require.Error(t, err)
+}
+
+func TestHTTP_Paths(t *testing.T) {
+	files := map[string][]byte{
+		"/library/manifest.json": []byte(`{
+			"schemas": ["schemas/test_schema-v1.0.0.json"],
+			"fields": ["fields/name.json", "fields/contact/name.json"]
+		}`),
+		"/library/duplicates.json": []byte(`{
+			"schemas": ["schemas/test_schema-v1.0.0.json"],
+			"fields": ["/fields/name.json", "/other/name.json"]
+		}`),
+		"/library/schemas/test_schema-v1.0.0.json": testSchema,
+		"/library/fields/name.json":                testField,
+		"/library/fields/contact/name.json":        testField,
+		"/fields/name.json":                        testField,
+		"/other/name.json":                         testField,
+	}
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, ok := files[r.URL.Path]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write(data)
+		}),
+	)
+	defer server.Close()
+
+	// The files are keyed by their paths in the folder, like in the dir.
+	snapshot, err := source.NewHTTP(server.URL + "/library/manifest.json").
+		Load(context.Background())
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		map[string][]byte{
+			"name.json":         testField,
+			"contact/name.json": testField,
+		},
+		snapshot.Fields,
+	)
+
+	// The files outside of the folder are keyed by name, which can't be
+	// listed twice.
+	_, err = source.NewHTTP(server.URL + "/library/duplicates.json").
+		Load(context.Background())
+	require.ErrorContains(t, err, "fields/name.json")
+}
+
+func TestS3(t *testing.T) {
+	objects := map[string][]byte{
+		"murm/schemas/test_schema-v1.0.0.json": testSchema,
+		"murm/fields/name.json":                testField,
+		"murm/fields/README.md":                []byte("Fields"),
+	}
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			auth := r.Header.Get("Authorization")
+			if !strings.HasPrefix(
+				auth,
+				"AWS4-HMAC-SHA256 Credential=key/",
+			) || !strings.Contains(auth, "/us-east-1/s3/aws4_request") {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+
+			if r.URL.Path == "/bucket" {
+				prefix := r.URL.Query().Get("prefix")
+				var contents strings.Builder
+				for key := range objects {
+					if strings.HasPrefix(key, prefix) {
+						fmt.Fprintf(
+							&contents,
+							"<Contents><Key>%s</Key><ETag>%q</ETag></Contents>",
+							key,
+							key,
+						)
+					}
+				}
+				fmt.Fprintf(
+					w,
+					"<ListBucketResult>%s</ListBucketResult>",
+					contents.String(),
+				)
+				return
+			}
+
+			data, ok := objects[strings.TrimPrefix(r.URL.Path, "/bucket/")]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write(data)
+		}),
+	)
+	defer server.Close()
+
+	src := source.NewS3(source.S3Config{
+		Endpoint:  server.URL,
+		Bucket:    "bucket",
+		Prefix:    "murm",
+		Region:    "us-east-1",
+		AccessKey: "key",
+		SecretKey: "secret",
+	})
+	snapshot, err := src.Load(context.Background())
+	require.NoError(t, err)
+	requireFiles(t, snapshot)
+
+	revision, err := src.Revision(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, snapshot.Revision, revision)
+}
+
+func TestGitHub(t *testing.T) {
+	var server *httptest.Server
+	blob := func(data []byte) string {
+		return fmt.Sprintf(
+			`{"content": %q}`,
+			base64.StdEncoding.EncodeToString(data),
+		)
+	}
+	responses := map[string]func() string{
+		"/branches/main": func() string {
+			return `{"commit": {"sha": "abc", "commit": {"author": {
+				"date": "2024-05-01T00:00:00Z"}}}}`
+		},
+		"/trees/abc": func() string {
+			return fmt.Sprintf(`{"tree": [
+				{"path": "schemas", "url": "%[1]s/trees/schemas"},
+				{"path": "fields", "url": "%[1]s/trees/fields"}
+			]}`, server.URL)
+		},
+		"/trees/schemas": func() string {
+			return fmt.Sprintf(`{"tree": [{"path": "test_schema-v1.0.0.json",
+				"url": "%s/blobs/schema"}]}`, server.URL)
+		},
+		"/trees/fields": func() string {
+			return fmt.Sprintf(`{"tree": [{"path": "name.json",
+				"url": "%s/blobs/name"}]}`, server.URL)
+		},
+		"/blobs/schema": func() string { return blob(testSchema) },
+		"/blobs/name":   func() string { return blob(testField) },
+	}
+	server = httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			response, ok := responses[r.URL.Path]
+			if !ok || r.Header.Get("Authorization") != "Bearer token" {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write([]byte(response()))
+		}),
+	)
+	defer server.Close()
+
+	src := source.NewGitHub(
+		server.URL+"/branches/main",
+		server.URL+"/trees",
+		"token",
+	)
+	revision, err := src.Revision(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "2024-05-01T00:00:00Z", revision)
+
+	snapshot, err := src.Load(context.Background())
+	require.NoError(t, err)
+	requireFiles(t, snapshot)
+	require.Equal(t, revision, snapshot.Revision)
+	require.Equal(t, "abc", snapshot.Commit)
+}
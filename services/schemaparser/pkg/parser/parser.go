@@ -0,0 +1,52 @@
+// Package parser resolves the references of the schemas the way the
+// schemaparser does, so that other services can parse a schema without
+// running the schemaparser.
+package parser
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/schemaparser"
+)
+
+// ParsedSchema is a schema whose references to the fields are resolved, the
+// way it is stored in the library.
+type ParsedSchema struct {
+	Title       string
+	Description string
+	Name        string
+	URL         string
+	Deprecated  bool
+	SunsetAt    int64
+	ReplacedBy  string
+	// FullSchema is the schema with its references resolved.
+	FullSchema bson.D
+	// Fields holds the names of the fields the schema references.
+	Fields []string
+}
+
+// ParseSchema resolves the `$ref`s of the schema against the fields, keyed by
+// their file names such as "name.json", like the schemas of the library
+// folder.
+func ParseSchema(
+	schema []byte,
+	fields map[string][]byte,
+) (*ParsedSchema, error) {
+	result, err := schemaparser.NewSchemaParser(nil).GetLocalSchema(schema, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := result.Schema.Metadata.Schema
+	return &ParsedSchema{
+		Title:       result.Schema.Title,
+		Description: result.Schema.Description,
+		Name:        metadata.Name,
+		URL:         metadata.URL,
+		Deprecated:  metadata.Deprecated,
+		SunsetAt:    metadata.SunsetAt,
+		ReplacedBy:  metadata.ReplacedBy,
+		FullSchema:  result.FullJSON,
+		Fields:      result.Fields,
+	}, nil
+}
@@ -1,23 +1,28 @@
 package schemaparser
 
 import (
+	"context"
 	"fmt"
-	"io/fs"
 	"os"
-	"path/filepath"
 
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/dateutil"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
 	mongodb "github.com/MurmurationsNetwork/MurmurationsServices/pkg/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/natsclient"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/redis"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/config"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/model"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/repository/mongo"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/service"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/source"
 )
 
 // SchemaCron represents a cron job for managing schema updates.
 type SchemaCron struct {
 	// svc is an instance of the SchemaService to handle schema related operations.
 	svc service.SchemaService
+	// source is where the schemas are hosted.
+	source source.SchemaSource
 }
 
 // NewCronJob creates a new instance of CronJob and initializes the
@@ -30,11 +35,24 @@ func NewCronJob() *SchemaCron {
 		os.Exit(1)
 	}
 
+	err = natsclient.Initialize(config.Values.Nats.URL)
+	if err != nil {
+		logger.Error("Failed to create Nats client", err)
+		os.Exit(1)
+	}
+
+	schemaSource, err := newSchemaSource()
+	if err != nil {
+		logger.Error("Failed to create the schema source", err)
+		os.Exit(1)
+	}
+
 	return &SchemaCron{
 		svc: service.NewSchemaService(
 			mongo.NewSchemaRepository(),
 			redisClient,
 		),
+		source: schemaSource,
 	}
 }
 
@@ -58,36 +76,43 @@ func (sc *SchemaCron) Run() error {
 	}
 
 	if config.Values.IsLocal {
-		schemaData, err := readSchemaFilesFromDir("library/schemas")
-		if err != nil {
-			return fmt.Errorf("failed to read schema files from dir: %w", err)
-		}
-		fieldData, err := readSchemaFilesFromDir("library/fields")
-		if err != nil {
-			return fmt.Errorf("failed to read field files from dir: %w", err)
+		if err := sc.updateLocalSchemas("library"); err != nil {
+			return err
 		}
+	}
+
+	return sc.update(context.Background())
+}
+
+// Watch loads the schemas again whenever the source changes, until ctx is
+// done. It returns right away when the watch mode is off or the source
+// can't be watched.
+func (sc *SchemaCron) Watch(ctx context.Context) {
+	watcher, ok := sc.source.(source.Watcher)
+	if !config.Values.Source.Watch || !ok {
+		return
+	}
 
-		// if schema data is not empty, update the schemas
-		if len(schemaData) > 0 {
-			err = sc.svc.UpdateLocalSchemas(schemaData, fieldData)
-			if err != nil {
-				return fmt.Errorf("failed to update local schemas: %w", err)
-			}
+	logger.Info("Watching the schemas of the " + sc.source.Name() + " source")
+	for range watcher.Watch(ctx) {
+		if err := sc.update(ctx); err != nil {
+			logger.Error("Failed to update the schemas", err)
 		}
 	}
+}
 
-	branchInfo, err := sc.svc.GetBranchInfo(config.Values.Github.BranchURL)
+// update loads the schemas of the source when its revision has changed.
+func (sc *SchemaCron) update(ctx context.Context) error {
+	revision, err := sc.source.Revision(ctx)
 	if err != nil {
 		return fmt.Errorf(
-			"failed to get last_commit and schema_list from %s: %w",
-			config.Values.Github.BranchURL,
+			"failed to get the revision of the %s source: %w",
+			sc.source.Name(),
 			err,
 		)
 	}
 
-	hasNewCommit, err := sc.svc.HasNewCommit(
-		branchInfo.Commit.InnerCommit.Author.Date,
-	)
+	hasNewCommit, err := sc.svc.HasNewCommit(revision)
 	if err != nil {
 		return fmt.Errorf(
 			"failed to get schemas:lastCommit: %w",
@@ -97,19 +122,40 @@ func (sc *SchemaCron) Run() error {
 
 	if !hasNewCommit {
 		logger.Info(
-			"No new commit found. Latest commit on GitHub: " +
-				branchInfo.Commit.InnerCommit.Author.Date,
+			"No new revision found. Latest revision of the " +
+				sc.source.Name() + " source: " + revision,
 		)
 		return nil
 	}
 
-	err = sc.svc.UpdateSchemas(branchInfo.Commit.Sha)
+	run := newRun(model.RunAction.Update, sc.source.Name())
+	run.Revision = revision
+	err = sc.load(ctx, run)
+	sc.recordRun(run, err)
+	return err
+}
+
+// load writes the schemas of the source to the library.
+func (sc *SchemaCron) load(ctx context.Context, run *model.Run) error {
+	snapshot, err := sc.source.Load(ctx)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to load the schemas of the %s source: %w",
+			sc.source.Name(),
+			err,
+		)
+	}
+	run.Revision = snapshot.Revision
+	run.Commit = snapshot.Commit
+
+	err = sc.svc.UpdateSchemas(snapshot.Schemas, snapshot.Fields, run)
 	if err != nil {
 		return fmt.Errorf("failed to update schemas: %w", err)
 	}
+	sc.publishSchemasUpdated()
 
-	// After successfully updating the schemas, update the last commit date.
-	err = sc.svc.SetLastCommit(branchInfo.Commit.InnerCommit.Author.Date)
+	// After successfully updating the schemas, update the last revision.
+	err = sc.svc.SetLastCommit(snapshot.Revision)
 	if err != nil {
 		return fmt.Errorf(
 			"failed to set schemas:lastCommit: %w",
@@ -120,6 +166,70 @@ func (sc *SchemaCron) Run() error {
 	return nil
 }
 
+// updateLocalSchemas loads the schemas and fields of the library folder at
+// dir.
+func (sc *SchemaCron) updateLocalSchemas(dir string) error {
+	dirSource := source.NewDir(dir, 0)
+	snapshot, err := dirSource.Load(context.Background())
+	if err != nil {
+		return err
+	}
+
+	// if schema data is not empty, update the schemas
+	if len(snapshot.Schemas) > 0 {
+		run := newRun(model.RunAction.Update, dirSource.Name())
+		run.Revision = snapshot.Revision
+		err = sc.svc.UpdateSchemas(snapshot.Schemas, snapshot.Fields, run)
+		sc.recordRun(run, err)
+		if err != nil {
+			return fmt.Errorf("failed to update local schemas: %w", err)
+		}
+		sc.publishSchemasUpdated()
+	}
+	return nil
+}
+
+// Rollback makes the previous generation of the library active again.
+func (sc *SchemaCron) Rollback() error {
+	if err := sc.connectToMongoDB(); err != nil {
+		return fmt.Errorf("failed to connect to MongoDB: %w", err)
+	}
+
+	run := newRun(model.RunAction.Rollback, "")
+	err := sc.svc.Rollback(run)
+	sc.recordRun(run, err)
+	if err != nil {
+		return fmt.Errorf("failed to roll back the schemas: %w", err)
+	}
+	sc.publishSchemasUpdated()
+	return nil
+}
+
+func newRun(action string, sourceName string) *model.Run {
+	return &model.Run{
+		Action:    action,
+		Source:    sourceName,
+		StartedAt: dateutil.GetNowUnix(),
+	}
+}
+
+// recordRun adds the run to the update history. Failing to record it is not
+// fatal, as it doesn't change the library.
+func (sc *SchemaCron) recordRun(run *model.Run, err error) {
+	if err := sc.svc.RecordRun(run, err); err != nil {
+		logger.Error("Failed to record the schemaparser run", err)
+	}
+}
+
+// publishSchemasUpdated lets the validation service know which schemas have
+// changed. Failing to publish is not fatal, cached schemas also expire on
+// their own.
+func (sc *SchemaCron) publishSchemasUpdated() {
+	if err := sc.svc.PublishSchemasUpdated(); err != nil {
+		logger.Error("Failed to publish schemas updated event", err)
+	}
+}
+
 // connectToMongoDB establishes a connection to MongoDB.
 func (sc *SchemaCron) connectToMongoDB() error {
 	uri := mongodb.GetURI(
@@ -137,37 +247,3 @@ func (sc *SchemaCron) connectToMongoDB() error {
 	}
 	return nil
 }
-
-// get Schemas from local folder.
-func readSchemaFilesFromDir(dirPath string) (map[string][]byte, error) {
-	filesData := make(
-		map[string][]byte,
-	) // Initialize a map to store filename and its content
-
-	err := filepath.WalkDir(
-		dirPath,
-		func(path string, d fs.DirEntry, err error) error {
-			if err != nil {
-				return err
-			}
-			if !d.IsDir() && filepath.Ext(path) == ".json" {
-				data, err := os.ReadFile(path)
-				if err != nil {
-					return err
-				}
-				relPath, err := filepath.Rel(dirPath, path)
-				if err != nil {
-					return err
-				}
-				filesData[relPath] = data
-			}
-			return nil
-		},
-	)
-
-	if err != nil {
-		return nil, err
-	}
-
-	return filesData, nil
-}
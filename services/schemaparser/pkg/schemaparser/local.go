@@ -0,0 +1,21 @@
+package schemaparser
+
+import (
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/redis"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/repository/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/service"
+)
+
+// LoadLocalSchemas loads the schemas and fields of the library folder at dir,
+// such as "library", without going through GitHub. The caller must be
+// connected to MongoDB. The services subscribed to the schema updates are
+// notified, so they stop using the cached versions.
+func LoadLocalSchemas(dir string, redisClient redis.Redis) error {
+	sc := &SchemaCron{
+		svc: service.NewSchemaService(
+			mongo.NewSchemaRepository(),
+			redisClient,
+		),
+	}
+	return sc.updateLocalSchemas(dir)
+}
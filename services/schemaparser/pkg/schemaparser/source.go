@@ -0,0 +1,60 @@
+package schemaparser
+
+import (
+	"fmt"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/config"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/source"
+)
+
+// newSchemaSource returns the source of the schemas set by SCHEMA_SOURCE.
+func newSchemaSource() (source.SchemaSource, error) {
+	cfg := config.Values.Source
+	switch cfg.Type {
+	case "github":
+		github := config.Values.Github
+		if github.BranchURL == "" || github.TreeURL == "" {
+			return nil, fmt.Errorf(
+				"GITHUB_BRANCH_URL and GITHUB_TREE_URL are required by " +
+					"the github source",
+			)
+		}
+		return source.NewGitHub(
+			github.BranchURL,
+			github.TreeURL,
+			github.TOKEN,
+		), nil
+	case "git":
+		if cfg.URL == "" {
+			return nil, fmt.Errorf(
+				"SCHEMA_SOURCE_URL is required by the git source",
+			)
+		}
+		return source.NewGit(cfg.URL, cfg.Branch), nil
+	case "dir":
+		return source.NewDir(cfg.Dir, cfg.WatchInterval), nil
+	case "http":
+		if cfg.URL == "" {
+			return nil, fmt.Errorf(
+				"SCHEMA_SOURCE_URL is required by the http source",
+			)
+		}
+		return source.NewHTTP(cfg.URL), nil
+	case "s3":
+		if cfg.URL == "" || cfg.S3.Bucket == "" {
+			return nil, fmt.Errorf(
+				"SCHEMA_SOURCE_URL and S3_BUCKET are required by the s3 source",
+			)
+		}
+		return source.NewS3(source.S3Config{
+			Endpoint:  cfg.URL,
+			Bucket:    cfg.S3.Bucket,
+			Prefix:    cfg.S3.Prefix,
+			Region:    cfg.S3.Region,
+			AccessKey: cfg.S3.AccessKey,
+			SecretKey: cfg.S3.SecretKey,
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown schema source: %s", cfg.Type)
+	}
+}
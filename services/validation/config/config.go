@@ -31,6 +31,12 @@ type ServerConfig struct {
 type LibraryConfig struct {
 	// Internal URL of the library
 	InternalURL string `env:"LIBRARY_URL,required"`
+	// How long compiled schemas are cached before being fetched again
+	SchemaCacheTTL time.Duration `env:"SCHEMA_CACHE_TTL,required"`
+	// Maximum number of compiled schemas kept in the cache
+	SchemaCacheSize int `env:"SCHEMA_CACHE_SIZE" envDefault:"500"`
+	// Schema every profile is validated against
+	DefaultSchema string `env:"DEFAULT_SCHEMA" envDefault:"default-v2.1.0"`
 }
 
 // NATSConfig holds the NATS related configuration.
@@ -41,6 +47,10 @@ type NATSConfig struct {
 	ClientID string `env:"NATS_CLIENT_ID,required"`
 	// NATS URL
 	URL string `env:"NATS_URL,required"`
+	// Number of messages fetched at once
+	BatchSize int `env:"NATS_BATCH_SIZE,required"`
+	// How long a message may go unacknowledged before it is redelivered
+	AckWait time.Duration `env:"NATS_ACK_WAIT,required"`
 }
 
 type redisConf struct {
@@ -1,8 +1,6 @@
 package event
 
 import (
-	"encoding/json"
-	"errors"
 	"fmt"
 	"time"
 
@@ -11,6 +9,7 @@ import (
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/messaging"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/redis"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/validation/config"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/validation/internal/model"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/validation/internal/service"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/validation/internal/validation"
@@ -43,31 +42,18 @@ func (handler *nodeHandler) NewNodeCreatedListener() error {
 		messaging.NodeCreated,
 		validation.QueueGroup,
 		handler.newNodeCreatedHandler,
+		messaging.WithBatchSize(config.Values.NATS.BatchSize),
+		messaging.WithAckWait(config.Values.NATS.AckWait),
 	)
 }
 
 // newNodeCreatedHandler handles the logic for node-created messages.
-func (handler *nodeHandler) newNodeCreatedHandler(msg *nats.Msg) {
-	defer func() {
-		if err := recover(); err != nil {
-			logger.Error(
-				fmt.Sprintf("Panic occurred in nodeCreated handler: %v", err),
-				errors.New("panic"),
-			)
-		}
-	}()
-
-	defer func() {
-		// Acknowledge the message regardless of error.
-		if err := msg.Ack(); err != nil {
-			logger.Error("Error when acknowledging message", err)
-		}
-	}()
-
+func (handler *nodeHandler) newNodeCreatedHandler(msg *nats.Msg) error {
 	var nodeCreatedData messaging.NodeCreatedData
-	if err := json.Unmarshal(msg.Data, &nodeCreatedData); err != nil {
-		logger.Error("Error when trying to parse nodeCreatedData", err)
-		return
+	if _, err := messaging.Decode(msg, &nodeCreatedData); err != nil {
+		return messaging.Permanent(
+			fmt.Errorf("error when trying to parse nodeCreatedData: %w", err),
+		)
 	}
 
 	nodeKey := fmt.Sprintf(
@@ -77,26 +63,28 @@ func (handler *nodeHandler) newNodeCreatedHandler(msg *nats.Msg) {
 	)
 	exists, err := handler.redis.Get(nodeKey)
 	if err != nil {
-		logger.Error("Error getting key from Redis", err)
-		return
+		return fmt.Errorf("error getting key from Redis: %w", err)
 	}
 
-	if exists == "" {
-		handler.validationService.ValidateNode(&model.Node{
-			ProfileURL: nodeCreatedData.ProfileURL,
-			Version:    nodeCreatedData.Version,
-		})
-		err := handler.redis.Set(nodeKey, "processed", 10*time.Second)
-		if err != nil {
-			logger.Error("Error setting key in Redis", err)
-		}
-		logger.Info(
-			fmt.Sprintf(
-				"Successfully processed profile with URL: %s",
-				nodeCreatedData.ProfileURL,
-			),
-		)
-	} else {
+	if exists != "" {
 		logger.Info(fmt.Sprintf("Duplicate node created event: %s", nodeKey))
+		return nil
 	}
+
+	handler.validationService.ValidateNode(&model.Node{
+		ProfileURL: nodeCreatedData.ProfileURL,
+		Version:    nodeCreatedData.Version,
+		Existing:   nodeCreatedData.Existing,
+	})
+	err = handler.redis.Set(nodeKey, "processed", 10*time.Second)
+	if err != nil {
+		logger.Error("Error setting key in Redis", err)
+	}
+	logger.Info(
+		fmt.Sprintf(
+			"Successfully processed profile with URL: %s",
+			nodeCreatedData.ProfileURL,
+		),
+	)
+	return nil
 }
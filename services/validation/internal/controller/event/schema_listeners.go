@@ -0,0 +1,56 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/messaging"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/validation/internal/service"
+)
+
+// SchemaHandler provides an interface for handling schema events.
+type SchemaHandler interface {
+	NewSchemasUpdatedListener() error
+}
+
+type schemaHandler struct {
+	validationService service.ValidationService
+}
+
+// NewSchemaHandler creates a new SchemaHandler with the provided validation
+// service.
+func NewSchemaHandler(
+	validationService service.ValidationService,
+) SchemaHandler {
+	return &schemaHandler{
+		validationService: validationService,
+	}
+}
+
+// NewSchemasUpdatedListener starts a listener for schemas-updated events.
+func (handler *schemaHandler) NewSchemasUpdatedListener() error {
+	return messaging.SubscribeBroadcast(
+		messaging.SchemasUpdated,
+		handler.newSchemasUpdatedHandler,
+	)
+}
+
+// newSchemasUpdatedHandler invalidates the cached schemas listed in the
+// message. If the message can't be decoded, the whole cache is cleared so
+// stale schemas are never kept around.
+func (handler *schemaHandler) newSchemasUpdatedHandler(msg *nats.Msg) {
+	var data messaging.SchemasUpdatedData
+	if err := json.Unmarshal(msg.Data, &data); err != nil {
+		logger.Error("Error when trying to parse schemasUpdatedData", err)
+		handler.validationService.InvalidateSchemas(nil)
+		return
+	}
+
+	handler.validationService.InvalidateSchemas(data.Schemas)
+	logger.Info(
+		fmt.Sprintf("Invalidated cached schemas: %v", data.Schemas),
+	)
+}
@@ -7,4 +7,6 @@ type Node struct {
 	// Version is the version vector of the node.
 	// https://en.wikipedia.org/wiki/Version_vector
 	Version int32 `json:"version"`
+	// Existing is true when the node was already accepted by the index.
+	Existing bool `json:"existing"`
 }
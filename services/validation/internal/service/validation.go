@@ -17,24 +17,33 @@ import (
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/validation/internal/model"
 )
 
-const DefaultSchema = "default-v2.1.0"
-
 type ValidationService interface {
 	ValidateNode(node *model.Node)
+	InvalidateSchemas(schemaNames []string)
 }
 
 type validationService struct {
-	redis redis.Redis
+	redis       redis.Redis
+	schemaCache *profilevalidator.SchemaCache
 }
 
 func NewValidationService(
 	redis redis.Redis,
+	schemaCache *profilevalidator.SchemaCache,
 ) ValidationService {
 	return &validationService{
-		redis: redis,
+		redis:       redis,
+		schemaCache: schemaCache,
 	}
 }
 
+// InvalidateSchemas drops the given schemas from the compiled schema cache so
+// the next validation fetches them from the library again. An empty list
+// clears the whole cache.
+func (svc *validationService) InvalidateSchemas(schemaNames []string) {
+	svc.schemaCache.Invalidate(schemaNames...)
+}
+
 func (svc *validationService) ValidateNode(node *model.Node) {
 	profileStr, err := httputil.GetJSONStr(node.ProfileURL)
 	if err != nil {
@@ -56,10 +65,12 @@ func (svc *validationService) ValidateNode(node *model.Node) {
 		return
 	}
 
-	if err := svc.validateAgainstDefaultSchema(profileStr, node); err != nil {
+	defaultNotices, err := svc.validateAgainstDefaultSchema(profileStr, node)
+	if err != nil {
 		return
 	}
-	if err := svc.validateAgainstLinkedSchemas(profileStr, node); err != nil {
+	linkedNotices, err := svc.validateAgainstLinkedSchemas(profileStr, node)
+	if err != nil {
 		return
 	}
 
@@ -145,6 +156,7 @@ func (svc *validationService) ValidateNode(node *model.Node) {
 			LastUpdated: dateutil.GetNowUnix(),
 			Version:     node.Version,
 			Expires:     expires,
+			Notices:     append(defaultNotices, linkedNotices...),
 		},
 	)
 	if err != nil {
@@ -182,14 +194,23 @@ func (svc *validationService) sendNodeValidationFailedEvent(
 }
 
 // validateAgainstDefaultSchema handles the validation of the node's profile
-// against the default schema.
+// against the default schema. It returns the notices about the schema, such
+// as its newer versions.
 func (svc *validationService) validateAgainstDefaultSchema(
 	profileStr string,
 	node *model.Node,
-) error {
+) ([]string, error) {
 	validator, err := profilevalidator.NewBuilder().
 		WithStrProfile(profileStr).
-		WithURLSchemas(config.Values.Library.InternalURL, []string{DefaultSchema}).
+		WithURLSchemas(
+			config.Values.Library.InternalURL,
+			[]string{config.Values.Library.DefaultSchema},
+		).
+		WithSchemaCache(svc.schemaCache).
+		WithNewerVersionNotices(
+			config.Values.Library.InternalURL,
+			svc.schemaCache,
+		).
 		Build()
 	if err != nil {
 		logger.Error("Failed to build schema validator", err)
@@ -202,10 +223,13 @@ func (svc *validationService) validateAgainstDefaultSchema(
 			[]int{http.StatusInternalServerError},
 		)
 		svc.sendNodeValidationFailedEvent(node, &errors)
-		return err
+		return nil, err
 	}
 
 	result := validator.Validate()
+	if !node.Existing {
+		result.RejectSunset()
+	}
 	if !result.Valid {
 		errors := jsonapi.NewError(
 			result.ErrorMessages,
@@ -214,17 +238,19 @@ func (svc *validationService) validateAgainstDefaultSchema(
 			result.ErrorStatus,
 		)
 		svc.sendNodeValidationFailedEvent(node, &errors)
-		return fmt.Errorf("validation failed")
+		return nil, fmt.Errorf("validation failed")
 	}
 
-	return nil
+	return result.Notices, nil
 }
 
 // validateAgainstLinkedSchemas handles the extraction and validation of the node's profile against linked schemas.
+// It returns the notices about the linked schemas, such as their newer
+// versions.
 func (svc *validationService) validateAgainstLinkedSchemas(
 	profileStr string,
 	node *model.Node,
-) error {
+) ([]string, error) {
 	linkedSchemas, err := getLinkedSchemas(profileStr)
 	if err != nil {
 		errors := jsonapi.NewError(
@@ -234,12 +260,17 @@ func (svc *validationService) validateAgainstLinkedSchemas(
 			[]int{http.StatusBadRequest},
 		)
 		svc.sendNodeValidationFailedEvent(node, &errors)
-		return err
+		return nil, err
 	}
 
 	validator, err := profilevalidator.NewBuilder().
 		WithStrProfile(profileStr).
 		WithURLSchemas(config.Values.Library.InternalURL, linkedSchemas).
+		WithSchemaCache(svc.schemaCache).
+		WithNewerVersionNotices(
+			config.Values.Library.InternalURL,
+			svc.schemaCache,
+		).
 		Build()
 	if err != nil {
 		logger.Error("Failed to build schema validator", err)
@@ -252,10 +283,13 @@ func (svc *validationService) validateAgainstLinkedSchemas(
 			[]int{http.StatusInternalServerError},
 		)
 		svc.sendNodeValidationFailedEvent(node, &errors)
-		return err
+		return nil, err
 	}
 
 	result := validator.Validate()
+	if !node.Existing {
+		result.RejectSunset()
+	}
 	if !result.Valid {
 		errors := jsonapi.NewError(
 			result.ErrorMessages,
@@ -264,10 +298,10 @@ func (svc *validationService) validateAgainstLinkedSchemas(
 			result.ErrorStatus,
 		)
 		svc.sendNodeValidationFailedEvent(node, &errors)
-		return fmt.Errorf("validation against linked schemas failed")
+		return nil, fmt.Errorf("validation against linked schemas failed")
 	}
 
-	return nil
+	return result.Notices, nil
 }
 
 func getLinkedSchemas(profileStr string) ([]string, error) {
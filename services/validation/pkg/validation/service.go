@@ -15,8 +15,10 @@ import (
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/core"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/handler"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/messaging"
 	midlogger "github.com/MurmurationsNetwork/MurmurationsServices/pkg/middleware/logger"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/natsclient"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/profile/profilevalidator"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/redis"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/validation/config"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/validation/internal/controller/event"
@@ -31,6 +33,8 @@ type Service struct {
 	isRunning *abool.AtomicBool
 	// Node event handler
 	nodeHandler event.NodeHandler
+	// Schema event handler
+	schemaHandler event.SchemaHandler
 	// Ensures cleanup is only run once
 	runCleanup sync.Once
 	// Context for shutdown
@@ -53,10 +57,15 @@ func NewService() *Service {
 	}
 
 	svc.setupServer()
-	svc.nodeHandler = event.NewNodeHandler(
+	validationService := service.NewValidationService(
 		redisClient,
-		service.NewValidationService(redisClient),
+		profilevalidator.NewSchemaCache(
+			config.Values.Library.SchemaCacheTTL,
+			config.Values.Library.SchemaCacheSize,
+		),
 	)
+	svc.nodeHandler = event.NewNodeHandler(redisClient, validationService)
+	svc.schemaHandler = event.NewSchemaHandler(validationService)
 	core.InstallShutdownHandler(svc.Shutdown)
 
 	return svc
@@ -85,8 +94,12 @@ func (s *Service) setupServer() {
 	)
 }
 
-// setupNATS initializes Nats service.
+// setupNATS initializes Nats service, unless the events go through another
+// bus.
 func (s *Service) setupNATS() {
+	if !messaging.UsesNATS() {
+		return
+	}
 	err := natsclient.Initialize(config.Values.NATS.URL)
 	if err != nil {
 		logger.Error("Failed to create Nats client", err)
@@ -108,6 +121,9 @@ func (s *Service) Run() {
 		err != http.ErrServerClosed {
 		s.panic("Error when trying to listen events", err)
 	}
+	if err := s.schemaHandler.NewSchemasUpdatedListener(); err != nil {
+		s.panic("Error when trying to listen schema events", err)
+	}
 	if err := s.server.ListenAndServe(); err != nil &&
 		err != http.ErrServerClosed {
 		s.panic("Error when trying to start the server", err)
@@ -157,9 +173,12 @@ func (s *Service) cleanup() {
 		s.shutdownCancelCtx()
 
 		// Disconnect from NATS.
-		if err := natsclient.GetInstance().Disconnect(); err != nil {
-			logger.Error("Error disconnecting from NATS: %v", err)
-			errOccurred = true
+		natsClient := natsclient.GetInstance()
+		if natsClient != nil {
+			if err := natsClient.Disconnect(); err != nil {
+				logger.Error("Error disconnecting from NATS: %v", err)
+				errOccurred = true
+			}
 		}
 
 		// Log based on whether an error occurred.